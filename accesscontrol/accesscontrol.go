@@ -0,0 +1,95 @@
+// Package accesscontrol provides the registry of access-controller types
+// that OrbitDB resolves by the name recorded in a database manifest.
+package accesscontrol
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/oplog"
+)
+
+// AccessController decides whether an entry may be written to a database log.
+type AccessController = oplog.AccessController
+
+// Options describes the database an access controller is created for.
+type Options struct {
+	Address  string                  // Database address
+	Name     string                  // Database name from the manifest
+	Type     string                  // Database type from the manifest
+	Write    []string                // Write list from the manifest, if any
+	Identity *identitytypes.Identity // Identity opening the database
+}
+
+// Factory creates an access controller for a database.
+type Factory func(opts Options) (AccessController, error)
+
+// registry stores available access-controller types.
+var (
+	registry   = make(map[string]Factory)
+	registryMu sync.RWMutex
+)
+
+// Register registers an access-controller type under the given name.
+func Register(typeName string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[typeName] = factory
+}
+
+// Get retrieves an access-controller factory by type name.
+func Get(typeName string) (Factory, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	factory, exists := registry[typeName]
+	if !exists {
+		return nil, errors.New("access controller type not found")
+	}
+	return factory, nil
+}
+
+// Types returns the names of all registered access-controller types, sorted.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open allows every writer whose entry carries a valid signature.
+type Open struct{}
+
+// AllowAllAccessController is the allow-all default, the controller a log
+// behaves as when none is configured.
+type AllowAllAccessController = Open
+
+// Type implements AccessController.
+func (Open) Type() string {
+	return "open"
+}
+
+// CanAppend implements AccessController.
+func (Open) CanAppend(entry oplog.EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	return true, nil
+}
+
+// init registers the built-in access-controller types. The "ipfs" type
+// enforces the write list recorded in the manifest, so every peer opening the
+// database uses the same list; a manifest without one allows nobody.
+func init() {
+	open := func(opts Options) (AccessController, error) {
+		return Open{}, nil
+	}
+	Register("open", open)
+	Register("ipfs", func(opts Options) (AccessController, error) {
+		return NewIPFSAccessController(opts.Write), nil
+	})
+}
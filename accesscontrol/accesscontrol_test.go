@@ -0,0 +1,111 @@
+package accesscontrol
+
+import (
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/oplog"
+)
+
+type denyAll struct{}
+
+func (denyAll) Type() string { return "deny-all" }
+
+func (denyAll) CanAppend(entry oplog.EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	return false, nil
+}
+
+func TestBuiltinTypes(t *testing.T) {
+	for _, name := range []string{"open", "ipfs"} {
+		if _, err := Get(name); err != nil {
+			t.Fatalf("Expected %q to be registered: %v", name, err)
+		}
+	}
+
+	factory, _ := Get("open")
+	ac, err := factory(Options{Address: "/orbitdb/test"})
+	if err != nil {
+		t.Fatalf("Failed to create open controller: %v", err)
+	}
+	if ok, err := ac.CanAppend(oplog.EncodedEntry{}, nil); err != nil || !ok {
+		t.Errorf("Expected open controller to allow writes, got %v (%v)", ok, err)
+	}
+}
+
+func TestIPFSFactoryEmptyWriteList(t *testing.T) {
+	factory, err := Get("ipfs")
+	if err != nil {
+		t.Fatalf("Expected ipfs to be registered: %v", err)
+	}
+	owner := &identitytypes.Identity{ID: "owner"}
+
+	// Without a write list nobody may write, not even the opening identity
+	for _, opts := range []Options{{Address: "/orbitdb/test", Identity: owner}, {Address: "/orbitdb/test"}} {
+		ac, err := factory(opts)
+		if err != nil {
+			t.Fatalf("Failed to create ipfs controller: %v", err)
+		}
+		if _, ok := ac.(Open); ok {
+			t.Fatal("Expected the ipfs factory never to return an open controller")
+		}
+		for _, identity := range []*identitytypes.Identity{nil, owner} {
+			if ok, _ := ac.CanAppend(oplog.EncodedEntry{}, identity); ok {
+				t.Errorf("Expected %v to be denied without a write list", identity)
+			}
+		}
+	}
+
+	// The manifest's list is used as given
+	ac, err := factory(Options{Address: "/orbitdb/test", Write: []string{"owner"}})
+	if err != nil {
+		t.Fatalf("Failed to create ipfs controller: %v", err)
+	}
+	if ok, _ := ac.CanAppend(oplog.EncodedEntry{}, owner); !ok {
+		t.Error("Expected a listed identity to be allowed")
+	}
+}
+
+func TestAllowAllAccessController(t *testing.T) {
+	var ac AccessController = AllowAllAccessController{}
+	if ac.Type() != "open" {
+		t.Errorf("Expected allow-all controller type 'open', got %q", ac.Type())
+	}
+	if ok, err := ac.CanAppend(oplog.EncodedEntry{}, nil); err != nil || !ok {
+		t.Errorf("Expected allow-all controller to allow writes, got %v (%v)", ok, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if _, err := Get("deny-all"); err == nil {
+		t.Fatal("Expected error for an unregistered type")
+	}
+
+	var received Options
+	Register("deny-all", func(opts Options) (AccessController, error) {
+		received = opts
+		return denyAll{}, nil
+	})
+
+	factory, err := Get("deny-all")
+	if err != nil {
+		t.Fatalf("Expected registered type to be found: %v", err)
+	}
+	ac, err := factory(Options{Name: "test-db"})
+	if err != nil {
+		t.Fatalf("Failed to create controller: %v", err)
+	}
+	if received.Name != "test-db" {
+		t.Errorf("Expected factory to receive options, got %+v", received)
+	}
+	if ok, _ := ac.CanAppend(oplog.EncodedEntry{}, nil); ok {
+		t.Error("Expected custom controller to deny writes")
+	}
+
+	found := false
+	for _, name := range Types() {
+		found = found || name == "deny-all"
+	}
+	if !found {
+		t.Errorf("Expected Types to include deny-all, got %v", Types())
+	}
+}
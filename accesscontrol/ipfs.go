@@ -0,0 +1,49 @@
+package accesscontrol
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/oplog"
+)
+
+// Wildcard in a write list allows every writer.
+const Wildcard = "*"
+
+// IPFSAccessController allows appends only from the identity IDs in its write
+// list. It encodes to JSON so the list can be stored and shared with peers.
+type IPFSAccessController struct {
+	Write []string `json:"write"`
+}
+
+// NewIPFSAccessController creates a controller for the given write list. An
+// empty list denies everyone, and a list containing Wildcard allows everyone.
+func NewIPFSAccessController(write []string) *IPFSAccessController {
+	return &IPFSAccessController{Write: append([]string{}, write...)}
+}
+
+// DecodeIPFSAccessController decodes a controller encoded with json.Marshal.
+func DecodeIPFSAccessController(data []byte) (*IPFSAccessController, error) {
+	var ac IPFSAccessController
+	if err := json.Unmarshal(data, &ac); err != nil {
+		return nil, fmt.Errorf("invalid access controller: %w", err)
+	}
+	return NewIPFSAccessController(ac.Write), nil
+}
+
+// Type implements AccessController.
+func (ac *IPFSAccessController) Type() string {
+	return "ipfs"
+}
+
+// CanAppend implements AccessController. Entries whose writer identity was not
+// resolved are only allowed by a wildcard.
+func (ac *IPFSAccessController) CanAppend(entry oplog.EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	for _, id := range ac.Write {
+		if id == Wildcard || (identity != nil && id == identity.ID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
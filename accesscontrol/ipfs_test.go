@@ -0,0 +1,99 @@
+package accesscontrol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/oplog"
+	"orbitdb/go-orbitdb/storage"
+)
+
+func newTestIdentity(t *testing.T, ks *keystore.KeyStore, id string) *identitytypes.Identity {
+	t.Helper()
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity(id)
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	return identity
+}
+
+func TestIPFSAccessController(t *testing.T) {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	allowed := newTestIdentity(t, ks, "allowed")
+	denied := newTestIdentity(t, ks, "denied")
+
+	ac := NewIPFSAccessController([]string{allowed.ID})
+	if ac.Type() != "ipfs" {
+		t.Errorf("Expected type 'ipfs', got %q", ac.Type())
+	}
+
+	entries := storage.NewMemoryStorage()
+	allowedLog, err := oplog.NewLog("test-log", allowed, entries, ks, oplog.WithAccessController(ac))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	deniedLog, err := oplog.NewLog("test-log", denied, entries, ks, oplog.WithAccessController(ac))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	if _, err := allowedLog.Append("allowed"); err != nil {
+		t.Errorf("Expected append from a listed identity to succeed: %v", err)
+	}
+	if _, err := deniedLog.Append("denied"); err == nil {
+		t.Error("Expected append from an unlisted identity to fail")
+	}
+}
+
+func TestIPFSAccessControllerWriteList(t *testing.T) {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity := newTestIdentity(t, ks, "writer")
+
+	tests := []struct {
+		name  string
+		write []string
+		want  bool
+	}{
+		{"empty list denies everyone", nil, false},
+		{"other identities only", []string{"someone-else"}, false},
+		{"listed identity", []string{"someone-else", identity.ID}, true},
+		{"wildcard overrides specific entries", []string{"someone-else", Wildcard}, true},
+	}
+	for _, tt := range tests {
+		ac := NewIPFSAccessController(tt.write)
+		if ok, err := ac.CanAppend(oplog.EncodedEntry{}, identity); err != nil || ok != tt.want {
+			t.Errorf("%s: expected %v, got %v (%v)", tt.name, tt.want, ok, err)
+		}
+	}
+
+	if ok, _ := NewIPFSAccessController([]string{identity.ID}).CanAppend(oplog.EncodedEntry{}, nil); ok {
+		t.Error("Expected an unresolved identity to be denied without a wildcard")
+	}
+}
+
+func TestIPFSAccessControllerSerialization(t *testing.T) {
+	ac := NewIPFSAccessController([]string{"writer-a", "writer-b"})
+
+	data, err := json.Marshal(ac)
+	if err != nil {
+		t.Fatalf("Failed to encode controller: %v", err)
+	}
+	if string(data) != `{"write":["writer-a","writer-b"]}` {
+		t.Errorf("Unexpected encoding %s", data)
+	}
+
+	decoded, err := DecodeIPFSAccessController(data)
+	if err != nil {
+		t.Fatalf("Failed to decode controller: %v", err)
+	}
+	if len(decoded.Write) != 2 || decoded.Write[0] != "writer-a" || decoded.Write[1] != "writer-b" {
+		t.Errorf("Expected write list to round-trip, got %v", decoded.Write)
+	}
+
+	if _, err := DecodeIPFSAccessController([]byte("not json")); err == nil {
+		t.Error("Expected invalid JSON to be rejected")
+	}
+}
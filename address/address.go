@@ -0,0 +1,53 @@
+// Package address parses and formats OrbitDB database addresses of the form
+// /orbitdb/<manifest-cid>/<name>. Addresses created by this implementation
+// carry no name and are just /orbitdb/<manifest-cid>.
+package address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+)
+
+// Prefix is the prefix of every OrbitDB database address.
+const Prefix = "/orbitdb/"
+
+// Address identifies a database by the CID of its manifest.
+type Address struct {
+	Root cid.Cid // CID of the database manifest
+	Path string  // Optional database name following the root
+}
+
+// Parse parses an OrbitDB address. The root may use any multibase encoding.
+func Parse(s string) (*Address, error) {
+	if !strings.HasPrefix(s, Prefix) {
+		return nil, fmt.Errorf("address %q does not start with %s", s, Prefix)
+	}
+
+	root, path, _ := strings.Cut(strings.TrimPrefix(s, Prefix), "/")
+	if root == "" {
+		return nil, errors.New("address has no manifest CID")
+	}
+	c, err := cid.Decode(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest CID %q: %w", root, err)
+	}
+
+	return &Address{Root: c, Path: path}, nil
+}
+
+// String returns the canonical form of the address, with the root encoded
+// in base58btc as manifest hashes are.
+func (a Address) String() string {
+	root, err := a.Root.StringOfBase(multibase.Base58BTC)
+	if err != nil {
+		root = a.Root.String()
+	}
+	if a.Path == "" {
+		return Prefix + root
+	}
+	return Prefix + root + "/" + a.Path
+}
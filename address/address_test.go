@@ -0,0 +1,73 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// testRoot returns a dag-cbor CID and its base58btc encoding.
+func testRoot(t *testing.T) (cid.Cid, string) {
+	t.Helper()
+	digest, err := mh.Sum([]byte("manifest"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatalf("Failed to hash: %v", err)
+	}
+	c := cid.NewCidV1(cid.DagCBOR, digest)
+	encoded, err := c.StringOfBase(multibase.Base58BTC)
+	if err != nil {
+		t.Fatalf("Failed to encode CID: %v", err)
+	}
+	return c, encoded
+}
+
+func TestParse(t *testing.T) {
+	root, encoded := testRoot(t)
+
+	tests := []struct {
+		input string
+		path  string
+	}{
+		{Prefix + encoded + "/my-db", "my-db"},
+		{Prefix + encoded, ""},
+	}
+	for _, tt := range tests {
+		addr, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", tt.input, err)
+		}
+		if !addr.Root.Equals(root) || addr.Path != tt.path {
+			t.Errorf("Expected root %s and path %q, got %s and %q", root, tt.path, addr.Root, addr.Path)
+		}
+		if addr.String() != tt.input {
+			t.Errorf("Expected String to reproduce %s, got %s", tt.input, addr.String())
+		}
+	}
+
+	// Other multibase encodings are accepted and formatted canonically
+	addr, err := Parse(Prefix + root.String() + "/my-db")
+	if err != nil {
+		t.Fatalf("Failed to parse base32 address: %v", err)
+	}
+	if addr.String() != Prefix+encoded+"/my-db" {
+		t.Errorf("Expected canonical address, got %s", addr.String())
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, encoded := testRoot(t)
+
+	for _, input := range []string{
+		encoded + "/my-db",
+		"/ipfs/" + encoded,
+		"orbitdb/" + encoded,
+		Prefix,
+		Prefix + "not-a-cid/my-db",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Expected %q to be rejected", input)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package databases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Counter represents a grow-only counter database. Every increment is stored
+// as its own entry, so concurrent increments from different peers are all
+// counted once their logs are joined.
+type Counter struct {
+	*Database // Embeds the base Database for shared functionality
+}
+
+// NewCounter creates a new Counter database instance.
+func NewCounter(db *Database) *Counter {
+	return &Counter{
+		Database: db,
+	}
+}
+
+// Increment adds n to the counter. Negative increments are rejected.
+func (c *Counter) Increment(n int) error {
+	if n < 0 {
+		return errors.New("counter increment cannot be negative")
+	}
+
+	op := map[string]interface{}{
+		"op":    "INC",
+		"value": n,
+	}
+
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to serialize operation: %w", err)
+	}
+
+	_, err = c.AddOperation(string(payload))
+	return err
+}
+
+// Value returns the sum of all increments in the log, across all branches.
+func (c *Counter) Value() (int, error) {
+	entries, err := c.Log.Values()
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve log entries: %w", err)
+	}
+
+	total := 0
+	for _, entry := range entries {
+		payload, ok := decodeOperation(entry)
+		if !ok || payload["op"] != "INC" {
+			continue
+		}
+
+		// JSON numbers decode as float64
+		value, ok := payload["value"].(float64)
+		if !ok || value < 0 || value != float64(int(value)) {
+			fmt.Printf("Warning: Invalid increment in entry %s: %v\n", entry.Hash, payload["value"])
+			continue
+		}
+		total += int(value)
+	}
+	return total, nil
+}
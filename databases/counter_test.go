@@ -0,0 +1,70 @@
+package databases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// setupCounterPeer creates a Counter replica of "test-address" written by
+// its own identity.
+func setupCounterPeer(t *testing.T, id string) *databases.Counter {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity(id)
+	require.NoError(t, err)
+
+	host, ps := setupLibp2pHostAndPubSub(t)
+	db, err := databases.NewDatabase("test-address", "test-counter", identity, storage.NewMemoryStorage(), ks, host, ps)
+	require.NoError(t, err)
+	return databases.NewCounter(db)
+}
+
+// TestCounterIncrement tests incrementing a single counter.
+func TestCounterIncrement(t *testing.T) {
+	counter := setupCounterPeer(t, "alice")
+
+	value, err := counter.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 0, value)
+
+	require.NoError(t, counter.Increment(2))
+	require.NoError(t, counter.Increment(3))
+
+	value, err = counter.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+	assert.Equal(t, 5, counter.SnapshotState())
+
+	// Negative increments are rejected and leave the log unchanged
+	assert.Error(t, counter.Increment(-1))
+	value, err = counter.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 5, value)
+}
+
+// TestCounterJoin tests that increments made on independent replicas are all
+// counted once the logs are joined.
+func TestCounterJoin(t *testing.T) {
+	alice := setupCounterPeer(t, "alice")
+	bob := setupCounterPeer(t, "bob")
+
+	require.NoError(t, alice.Increment(1))
+	require.NoError(t, alice.Increment(4))
+	require.NoError(t, bob.Increment(10))
+
+	require.NoError(t, alice.Log.Join(bob.Log))
+	require.NoError(t, bob.Log.Join(alice.Log))
+
+	aliceValue, err := alice.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 15, aliceValue)
+
+	bobValue, err := bob.Value()
+	require.NoError(t, err)
+	assert.Equal(t, 15, bobValue)
+}
@@ -14,21 +14,30 @@ import (
 	"sync"
 )
 
+// ErrBroadcast is returned with the entry's hash when an operation was stored
+// in the log but could not be broadcast to peers. The entry is not rolled
+// back; it reaches peers on the next head exchange.
+var ErrBroadcast = errors.New("entry stored but broadcast failed")
+
 // Database represents the base class for all database types.
 type Database struct {
-	Address     string
-	Name        string
-	Identity    *identitytypes.Identity
-	Meta        map[string]interface{}
-	Log         *oplog.Log
-	Sync        *orbitsync.Sync
-	Events      chan interface{}
-	taskQueue   chan func()
-	stopChannel chan struct{}
-	mu          sync.Mutex
+	Address          string
+	Name             string
+	Type             string   // Database type from the manifest
+	AccessController string   // Access controller named in the manifest
+	Write            []string // Write list named in the manifest
+	Admin            string   // Public key allowed to update the manifest
+	Identity         *identitytypes.Identity
+	Meta             map[string]interface{}
+	Log              *oplog.Log
+	Sync             *orbitsync.Sync
+	Events           chan interface{}
+	taskQueue        chan func()
+	stopChannel      chan struct{}
+	mu               sync.Mutex
 }
 
-// NewDatabase creates a new Database instance.
+// NewDatabase creates a new Database instance. logOpts configure its oplog.
 func NewDatabase(
 	address, name string,
 	identity *identitytypes.Identity,
@@ -36,6 +45,7 @@ func NewDatabase(
 	keyStore *keystore.KeyStore,
 	host host.Host,
 	pubsub *pubsub.PubSub,
+	logOpts ...oplog.LogOption,
 ) (*Database, error) {
 	// Validate inputs
 	if address == "" {
@@ -58,7 +68,7 @@ func NewDatabase(
 	}
 
 	// Initialize the log
-	log, err := oplog.NewLog(address, identity, entryStorage, keyStore)
+	log, err := oplog.NewLog(address, identity, entryStorage, keyStore, logOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize oplog: %w", err)
 	}
@@ -117,7 +127,31 @@ func (db *Database) AddOperation(op interface{}) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to serialize operation: %w", err)
 	}
+	return db.appendOperation(func(commit func(*oplog.EncodedEntry) error) (*oplog.EncodedEntry, error) {
+		return db.Log.AppendTx(payload, commit)
+	})
+}
 
+// AddOperationData appends a new operation encoded as CBOR payload data
+// rather than a JSON string, so nested maps and numbers are stored natively.
+// The stores read both forms.
+func (db *Database) AddOperationData(op interface{}) (string, error) {
+	if op == nil {
+		return "", errors.New("operation cannot be nil")
+	}
+	data, err := oplog.EncodePayload(op)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize operation: %w", err)
+	}
+	return db.appendOperation(func(commit func(*oplog.EncodedEntry) error) (*oplog.EncodedEntry, error) {
+		return db.Log.AppendDataTx(data, commit)
+	})
+}
+
+// appendOperation runs an append on the task queue, broadcasting the entry
+// once it is durable, and returns its hash. A failed broadcast still returns
+// the hash, with an error wrapping ErrBroadcast.
+func (db *Database) appendOperation(appendTx func(commit func(*oplog.EncodedEntry) error) (*oplog.EncodedEntry, error)) (string, error) {
 	// Create a result channel for hash and error
 	resultChan := make(chan struct {
 		hash string
@@ -131,21 +165,19 @@ func (db *Database) AddOperation(op interface{}) (string, error) {
 			err  error
 		}
 
-		// Append the operation to the log
-		entry, err := db.Log.Append(payload)
-		if err != nil {
+		// Append the operation to the log, broadcasting only once it is durable
+		entry, err := appendTx(func(entry *oplog.EncodedEntry) error {
+			if syncErr := db.Sync.Broadcast(*entry); syncErr != nil {
+				return fmt.Errorf("%w: %w", ErrBroadcast, syncErr)
+			}
+			return nil
+		})
+		if err != nil && !(entry != nil && errors.Is(err, ErrBroadcast)) {
 			result.err = fmt.Errorf("failed to append to log: %w", err)
 			resultChan <- result
 			return
 		}
 
-		// Add the entry to sync
-		if syncErr := db.Sync.Add(entry.Payload); syncErr != nil {
-			result.err = fmt.Errorf("failed to sync entry: %w", syncErr)
-			resultChan <- result
-			return
-		}
-
 		// Emit the update event safely
 		select {
 		case db.Events <- entry:
@@ -154,8 +186,9 @@ func (db *Database) AddOperation(op interface{}) (string, error) {
 			fmt.Println("warning: Events channel full, event dropped")
 		}
 
-		// Return the hash
+		// Return the hash, along with any broadcast failure
 		result.hash = entry.Hash
+		result.err = err
 		resultChan <- result
 	}
 
@@ -222,10 +255,10 @@ func (db *Database) ApplyOperation(data []byte) {
 		}
 
 		// Join the entry into the log
-		processed := make(map[string]bool)
-
-		// Join the entry into the log
-		if joinErr := db.Log.JoinEntry(&entry, processed); joinErr != nil {
+		db.Log.Mu.Lock()
+		joinErr := db.Log.JoinEntry(&entry, make(map[string]bool))
+		db.Log.Mu.Unlock()
+		if joinErr != nil {
 			fmt.Printf("applyOperation: failed to join entry: %v\n", joinErr)
 			return
 		}
@@ -2,6 +2,7 @@ package databases_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -93,6 +94,70 @@ func TestAddOperation(t *testing.T) {
 	}
 }
 
+// failingStorage rejects every write.
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) Put(key string, value []byte) error {
+	return errors.New("storage unavailable")
+}
+
+// TestAddOperationStorageFailure tests that nothing is broadcast when the entry cannot be stored.
+func TestAddOperationStorageFailure(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entryStorage := failingStorage{Storage: storage.NewMemoryStorage()}
+	host1, ps := setupLibp2pHostAndPubSub(t)
+
+	db, err := databases.NewDatabase("test-address", "test-db", identity, entryStorage, ks, host1, ps)
+	require.NoError(t, err)
+
+	_, err = db.AddOperation(map[string]string{"key": "test", "value": "123"})
+	require.Error(t, err)
+
+	assert.Nil(t, db.Log.Head, "head must not advance when storage fails")
+	assert.Equal(t, 0, db.Sync.PublishedMessages(), "nothing may be broadcast when storage fails")
+	select {
+	case event := <-db.Events:
+		t.Errorf("Expected no event, got %v", event)
+	default:
+	}
+}
+
+// TestAddOperationBroadcastFailure tests that an entry stored but not broadcast
+// is reported with its hash and ErrBroadcast.
+func TestAddOperationBroadcastFailure(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entryStorage := storage.NewMemoryStorage()
+	host1, ps := setupLibp2pHostAndPubSub(t)
+
+	db, err := databases.NewDatabase("test-address", "test-db", identity, entryStorage, ks, host1, ps)
+	require.NoError(t, err)
+
+	// Publishing on a stopped sync fails
+	db.Sync.Stop()
+
+	hash, err := db.AddOperation(map[string]string{"key": "test", "value": "123"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, databases.ErrBroadcast))
+	require.NotEmpty(t, hash, "the stored entry's hash is returned")
+
+	require.NotNil(t, db.Log.Head)
+	assert.Equal(t, hash, db.Log.Head.Hash)
+	_, err = db.Log.Get(hash)
+	require.NoError(t, err)
+	select {
+	case event := <-db.Events:
+		entry, ok := event.(*oplog.EncodedEntry)
+		require.True(t, ok)
+		assert.Equal(t, hash, entry.Hash)
+	default:
+		t.Error("Expected an event for the stored entry")
+	}
+}
+
 // TestAddOperationSerializationError tests serialization errors in AddOperation.
 func TestAddOperationSerializationError(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
@@ -126,7 +191,8 @@ func TestApplyOperation(t *testing.T) {
 
 	// Create the clock and the entry
 	clock := oplog.NewClock(identity.ID, 1)
-	entry := oplog.NewEntry(ks, identity, logID, payload, clock, nil, nil)
+	entry, err := oplog.NewEntry(ks, identity, logID, payload, clock, nil, nil)
+	require.NoError(t, err)
 
 	// Encode the entry to bytes
 	data := entry.Bytes
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"orbitdb/go-orbitdb/oplog"
+	"sort"
 )
 
 // Documents represents a database for storing structured documents.
@@ -95,97 +97,68 @@ func (d *Documents) Del(id string) (string, error) {
 	return d.KeyValue.Del(id)
 }
 
-// Query retrieves documents matching a user-defined filter function.
+// Query retrieves the current documents matching a user-defined filter
+// function, in the order they were last written. Superseded versions and
+// deleted documents are excluded.
 func (d *Documents) Query(filterFn func(doc map[string]interface{}) bool) ([]map[string]interface{}, error) {
-	entries, err := d.Log.Values()
+	records, err := d.documents()
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve log entries: %w", err)
+		return nil, err
 	}
 
-	results := make([]map[string]interface{}, 0)
-	for _, entry := range entries {
-		fmt.Printf("Debug (Query): Processing entry with hash %s and payload: %s\n", entry.Hash, entry.Payload)
-
-		var rawPayload DocumentPayload
-
-		// Attempt to unmarshal directly into DocumentPayload
-		err := json.Unmarshal([]byte(entry.Payload), &rawPayload)
-		if err != nil {
-			fmt.Printf("Warning (Query): Failed to decode payload for entry %s: %v. Attempting fallback decoding.\n", entry.Hash, err)
-
-			// Fallback: Attempt to decode double-encoded payload
-			var doubleEncodedPayload string
-			if json.Unmarshal([]byte(entry.Payload), &doubleEncodedPayload) == nil {
-				if json.Unmarshal([]byte(doubleEncodedPayload), &rawPayload) == nil {
-					fmt.Printf("Debug (Query): Fallback decoding succeeded for entry %s\n", entry.Hash)
-				} else {
-					fmt.Printf("Warning (Query): Fallback decoding failed for entry %s\n", entry.Hash)
-					continue
-				}
-			} else {
-				fmt.Printf("Warning (Query): Double decoding not applicable for entry %s\n", entry.Hash)
-				continue
-			}
-		}
-
-		// Skip deleted documents
-		if rawPayload.Op == "DEL" {
-			continue
-		}
+	sort.Slice(records, func(i, j int) bool {
+		return oplog.CompareClocks(records[i].Clock, records[j].Clock) < 0
+	})
 
-		// Apply the filter function
-		if filterFn(rawPayload.Value) {
-			results = append(results, rawPayload.Value)
+	results := make([]map[string]interface{}, 0)
+	for _, record := range records {
+		doc := record.Value.(map[string]interface{})
+		if filterFn(doc) {
+			results = append(results, doc)
 		}
 	}
-
-	fmt.Printf("Debug (Query): Query results: %+v\n", results)
 	return results, nil
 }
 
-// All retrieves all documents in the database.
+// All retrieves all current documents keyed by their index field value.
 func (d *Documents) All() (map[string]map[string]interface{}, error) {
-	entries, err := d.Log.Values()
+	records, err := d.documents()
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve log entries: %w", err)
+		return nil, err
 	}
 
-	results := make(map[string]map[string]interface{})
-	for _, entry := range entries {
-		var rawPayload DocumentPayload
-
-		// Attempt to unmarshal directly into DocumentPayload
-		err := json.Unmarshal([]byte(entry.Payload), &rawPayload)
-		if err != nil {
-			fmt.Printf("Warning: Failed to decode payload for entry %s. Attempting fallback decoding. Payload: %s, Error: %v\n", entry.Hash, entry.Payload, err)
-
-			// Fallback: Attempt to decode double-encoded payload
-			var doubleEncodedPayload string
-			if json.Unmarshal([]byte(entry.Payload), &doubleEncodedPayload) == nil {
-				if json.Unmarshal([]byte(doubleEncodedPayload), &rawPayload) == nil {
-					fmt.Printf("Debug: Fallback decoding succeeded for entry %s\n", entry.Hash)
-				} else {
-					fmt.Printf("Warning: Fallback decoding failed for entry %s\n", entry.Hash)
-					continue
-				}
-			} else {
-				fmt.Printf("Warning: Double decoding not applicable for entry %s\n", entry.Hash)
-				continue
-			}
-		}
+	results := make(map[string]map[string]interface{}, len(records))
+	for _, record := range records {
+		doc := record.Value.(map[string]interface{})
+		results[record.key] = doc
+	}
+	return results, nil
+}
 
-		// Skip deleted documents
-		if rawPayload.Op == "DEL" {
-			continue
-		}
+// documentRecord is the latest live version of a document.
+type documentRecord struct {
+	kvRecord
+	key string
+}
 
-		// Add to results using the "key" field
-		results[rawPayload.Key] = rawPayload.Value
+// documents replays the log and returns the latest put for every index
+// value that has not been deleted since.
+func (d *Documents) documents() ([]documentRecord, error) {
+	records, _, err := d.state()
+	if err != nil {
+		return nil, err
 	}
 
-	if len(results) == 0 {
-		fmt.Println("Debug: No entries found in the log")
+	docs := make([]documentRecord, 0, len(records))
+	for key, record := range records {
+		if record.Deleted {
+			continue
+		}
+		if _, ok := record.Value.(map[string]interface{}); !ok {
+			fmt.Printf("Warning: Skipping non-document value for key %s\n", key)
+			continue
+		}
+		docs = append(docs, documentRecord{kvRecord: record, key: key})
 	}
-
-	return results, nil
+	return docs, nil
 }
@@ -193,3 +193,88 @@ func TestDocuments_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, results)
 }
+
+// TestDocuments_QueryLatestVersions tests that queries see only the latest
+// version of each document and exclude deleted ones.
+func TestDocuments_QueryLatestVersions(t *testing.T) {
+	docs := setupDocumentsTest(t)
+
+	_, err := docs.Put(map[string]interface{}{"_id": "doc1", "type": "test", "value": 10})
+	require.NoError(t, err)
+	_, err = docs.Put(map[string]interface{}{"_id": "doc2", "type": "test", "value": 20})
+	require.NoError(t, err)
+	_, err = docs.Put(map[string]interface{}{"_id": "doc3", "type": "test", "value": 30})
+	require.NoError(t, err)
+
+	// Update doc1 and delete doc2
+	_, err = docs.Put(map[string]interface{}{"_id": "doc1", "type": "test", "value": 11})
+	require.NoError(t, err)
+	require.NoError(t, docs.Delete("doc2"))
+
+	results, err := docs.Query(func(doc map[string]interface{}) bool {
+		return doc["type"] == "test"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"_id": "doc3", "type": "test", "value": float64(30)},
+		{"_id": "doc1", "type": "test", "value": float64(11)},
+	}, results)
+
+	all, err := docs.All()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.NotContains(t, all, "doc2")
+}
+
+// TestDocuments_CustomIndex tests indexing documents by a configured field.
+func TestDocuments_CustomIndex(t *testing.T) {
+	docs, err := databases.NewDocuments("name", setupKeyValueTest(t))
+	require.NoError(t, err)
+
+	_, err = docs.Put(map[string]interface{}{"name": "alice", "age": 30})
+	require.NoError(t, err)
+	_, err = docs.Put(map[string]interface{}{"_id": "ignored"})
+	assert.Error(t, err)
+
+	doc, err := docs.Get("alice")
+	require.NoError(t, err)
+	assert.Equal(t, float64(30), doc["age"])
+}
+
+// TestDocuments_NativePayload tests storing a nested document as CBOR
+// payload data and reading it back with its structure and number types.
+func TestDocuments_NativePayload(t *testing.T) {
+	docs := setupDocumentsTest(t)
+
+	doc := map[string]interface{}{
+		"_id":   "doc1",
+		"title": `A "quoted" title`,
+		"meta": map[string]interface{}{
+			"views": 42,
+			"tags":  []interface{}{"go", "cbor"},
+		},
+	}
+	op := map[string]interface{}{"op": "PUT", "key": "doc1", "value": doc}
+	hash, err := docs.AddOperationData(op)
+	require.NoError(t, err, "Failed to add operation data")
+
+	entry, err := docs.Log.Get(hash)
+	require.NoError(t, err)
+	assert.Empty(t, entry.Payload, "Expected no string payload")
+	assert.NotEmpty(t, entry.PayloadData, "Expected CBOR payload data")
+
+	got, err := docs.Get("doc1")
+	require.NoError(t, err, "Failed to get document")
+	assert.Equal(t, `A "quoted" title`, got["title"])
+	meta, ok := got["meta"].(map[string]interface{})
+	require.True(t, ok, "Expected a nested map, got %T", got["meta"])
+	assert.Equal(t, int64(42), meta["views"], "Expected the integer to keep its type")
+	assert.Equal(t, []interface{}{"go", "cbor"}, meta["tags"])
+
+	// String payloads keep working alongside
+	_, err = docs.Put(map[string]interface{}{"_id": "doc2", "title": "Plain"})
+	require.NoError(t, err)
+	plain, err := docs.Get("doc2")
+	require.NoError(t, err)
+	assert.Equal(t, "Plain", plain["title"])
+}
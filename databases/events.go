@@ -134,6 +134,29 @@ func (e *Events) Iterator(gt, gte, lt, lte string, amount int) ([]map[string]int
 	return results, nil
 }
 
+// IteratorWithOptions walks the event log with the oplog iterator and returns
+// events newest first, bounded by entry hashes and an amount as in
+// oplog.IteratorOptions.
+func (e *Events) IteratorWithOptions(opts oplog.IteratorOptions) ([]map[string]interface{}, error) {
+	ch, err := e.Log.Iterator(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate log entries: %w", err)
+	}
+
+	results := make([]map[string]interface{}, 0)
+	for entry := range ch {
+		payload, ok := decodeOperation(entry)
+		if !ok {
+			continue
+		}
+		results = append(results, map[string]interface{}{
+			"hash":  entry.Hash,
+			"value": payload["value"],
+		})
+	}
+	return results, nil
+}
+
 // All retrieves all events in the event log.
 func (e *Events) All() ([]map[string]interface{}, error) {
 	// Retrieve all log entries
@@ -175,19 +198,7 @@ func (e *Events) All() ([]map[string]interface{}, error) {
 	return results, nil
 }
 
+// CompareClocks orders clocks by time, breaking ties using the identity string.
 func CompareClocks(clock1 oplog.Clock, clock2 oplog.Clock) int {
-	if clock1.Time != clock2.Time {
-		if clock1.Time < clock2.Time {
-			return -1
-		}
-		return 1
-	}
-	// Break ties using the identity string
-	if clock1.ID < clock2.ID {
-		return -1
-	}
-	if clock1.ID > clock2.ID {
-		return 1
-	}
-	return 0
+	return oplog.CompareClocks(clock1, clock2)
 }
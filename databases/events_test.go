@@ -13,6 +13,7 @@ import (
 	"orbitdb/go-orbitdb/databases"
 	"orbitdb/go-orbitdb/identities/providers"
 	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/oplog"
 	"orbitdb/go-orbitdb/storage"
 )
 
@@ -115,3 +116,41 @@ func TestEvents_Iterator(t *testing.T) {
 	assert.Equal(t, hash1, it[0]["hash"], "First entry should match the first added hash")
 	assert.Equal(t, hash2, it[1]["hash"], "Second entry should match the second added hash")
 }
+
+func TestEvents_IteratorWithOptions(t *testing.T) {
+	db := setupDatabaseTest(t)
+	events := databases.NewEvents(db)
+
+	hashes := make([]string, 0, 5)
+	for i := 1; i <= 5; i++ {
+		hash, err := events.Add(fmt.Sprintf("Event %d", i))
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+
+	// The three most recent events, newest first
+	it, err := events.IteratorWithOptions(oplog.IteratorOptions{Amount: 3})
+	require.NoError(t, err)
+	require.Len(t, it, 3)
+	assert.Equal(t, "Event 5", it[0]["value"])
+	assert.Equal(t, "Event 4", it[1]["value"])
+	assert.Equal(t, "Event 3", it[2]["value"])
+	assert.Equal(t, hashes[4], it[0]["hash"])
+
+	// The next page continues below the last hash
+	it, err = events.IteratorWithOptions(oplog.IteratorOptions{LT: hashes[2], Amount: 3})
+	require.NoError(t, err)
+	require.Len(t, it, 2)
+	assert.Equal(t, "Event 2", it[0]["value"])
+	assert.Equal(t, "Event 1", it[1]["value"])
+
+	// GT stops before the bound
+	it, err = events.IteratorWithOptions(oplog.IteratorOptions{GT: hashes[2], Amount: -1})
+	require.NoError(t, err)
+	require.Len(t, it, 2)
+	assert.Equal(t, "Event 5", it[0]["value"])
+	assert.Equal(t, "Event 4", it[1]["value"])
+
+	_, err = events.IteratorWithOptions(oplog.IteratorOptions{LT: "unknown", Amount: -1})
+	assert.Error(t, err)
+}
@@ -8,9 +8,13 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/oplog"
 	"orbitdb/go-orbitdb/storage"
 )
 
+// ErrKeyNotFound is returned by GetString when a key is unset or deleted.
+var ErrKeyNotFound = errors.New("key not found")
+
 // KeyValue extends the base Database with key-value functionality.
 type KeyValue struct {
 	*Database
@@ -53,47 +57,147 @@ func (kv *KeyValue) Put(key string, value interface{}) (string, error) {
 
 // Get retrieves the value for a given key.
 func (kv *KeyValue) Get(key string) (interface{}, error) {
-	entries, err := kv.Log.Values()
+	value, _, _, _, err := kv.latest(key)
+	return value, err
+}
+
+// Set stores a string value under a key.
+func (kv *KeyValue) Set(key, value string) error {
+	_, err := kv.Put(key, value)
+	return err
+}
+
+// GetString retrieves the string value for a key, returning ErrKeyNotFound
+// when the key is unset or deleted.
+func (kv *KeyValue) GetString(key string) (string, error) {
+	value, _, _, ok, err := kv.latest(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve log entries: %w", err)
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	str, isString := value.(string)
+	if !isString {
+		return "", fmt.Errorf("value for key %s is not a string", key)
 	}
+	return str, nil
+}
 
-	// Traverse log entries in reverse order (most recent first)
-	for i := len(entries) - 1; i >= 0; i-- {
-		entry := entries[i]
+// Delete removes a key, discarding the entry hash.
+func (kv *KeyValue) Delete(key string) error {
+	_, err := kv.Del(key)
+	return err
+}
 
-		// Decode the outer JSON-encoded payload string
-		var rawPayload string
-		err := json.Unmarshal([]byte(entry.Payload), &rawPayload)
-		if err != nil {
-			fmt.Printf("Warning: Failed to decode outer payload for entry %s: %v\n", entry.Hash, err)
-			continue
+// GetWithMeta retrieves the value for a given key along with the identity
+// hash of its author and the clock of the winning entry. ok is false when
+// the key is unset or deleted.
+func (kv *KeyValue) GetWithMeta(key string) (value interface{}, author string, clock oplog.Clock, ok bool, err error) {
+	return kv.latest(key)
+}
+
+// latest finds the most recent operation on a key and returns its value and provenance.
+func (kv *KeyValue) latest(key string) (interface{}, string, oplog.Clock, bool, error) {
+	records, _, err := kv.state()
+	if err != nil {
+		return nil, "", oplog.Clock{}, false, err
+	}
+
+	record, ok := records[key]
+	if !ok || record.Deleted {
+		return nil, "", oplog.Clock{}, false, nil
+	}
+	return record.Value, record.Author, record.Clock, true, nil
+}
+
+// kvRecord is the winning operation on a key. Deleted records are kept so a
+// compaction snapshot still shadows older puts; see Compact.
+type kvRecord struct {
+	Value   interface{} `json:"value,omitempty"`
+	Deleted bool        `json:"deleted,omitempty"`
+	Author  string      `json:"author"`
+	Clock   oplog.Clock `json:"clock"`
+}
+
+// state folds the log into the winning record per key, keeping for every key
+// the operation with the greatest clock. Snapshot entries contribute their
+// records with the clocks of the operations they replaced. It also returns
+// the entries it read.
+func (kv *KeyValue) state() (map[string]kvRecord, []oplog.EncodedEntry, error) {
+	entries, err := kv.Log.Values()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve log entries: %w", err)
+	}
+
+	records := make(map[string]kvRecord)
+	apply := func(key string, record kvRecord) {
+		if current, ok := records[key]; !ok || oplog.CompareClocks(record.Clock, current.Clock) > 0 {
+			records[key] = record
 		}
+	}
 
-		// Decode the inner JSON string into a map
-		var payload map[string]interface{}
-		err = json.Unmarshal([]byte(rawPayload), &payload)
-		if err != nil {
-			fmt.Printf("Warning: Failed to decode inner payload for entry %s: %v\n", entry.Hash, err)
+	for _, entry := range entries {
+		payload, ok := decodeOperation(entry)
+		if !ok {
 			continue
 		}
 
-		op, ok := payload["op"].(string)
-		entryKey, _ := payload["key"].(string)
-		if !ok || entryKey != key {
-			continue
+		op, _ := payload["op"].(string)
+		key, _ := payload["key"].(string)
+
+		switch op {
+		case "PUT":
+			apply(key, kvRecord{Value: payload["value"], Author: entry.Identity, Clock: entry.Clock})
+		case "DEL":
+			apply(key, kvRecord{Deleted: true, Author: entry.Identity, Clock: entry.Clock})
+		case "SNAPSHOT":
+			snapshot, err := decodeSnapshot(payload)
+			if err != nil {
+				fmt.Printf("Warning: Failed to decode snapshot for entry %s: %v\n", entry.Hash, err)
+				continue
+			}
+			for key, record := range snapshot {
+				apply(key, record)
+			}
 		}
+	}
 
-		// Handle the operation
-		if op == "PUT" {
-			return payload["value"], nil
-		} else if op == "DEL" {
-			return nil, nil
+	return records, entries, nil
+}
+
+// decodeOperation decodes the double-encoded JSON payload of an entry, or
+// its CBOR payload data.
+func decodeOperation(entry oplog.EncodedEntry) (map[string]interface{}, bool) {
+	// Operations added with AddOperationData are CBOR payload data
+	if len(entry.PayloadData) > 0 {
+		value, err := oplog.DecodePayload(entry.PayloadData)
+		if err != nil {
+			fmt.Printf("Warning: Failed to decode payload data for entry %s: %v\n", entry.Hash, err)
+			return nil, false
+		}
+		payload, ok := value.(map[string]interface{})
+		if !ok {
+			fmt.Printf("Warning: Payload data for entry %s is not an operation\n", entry.Hash)
+			return nil, false
 		}
+		return payload, true
 	}
 
-	// If the key is not found, return nil
-	return nil, nil
+	// Decode the outer JSON-encoded payload string
+	var rawPayload string
+	if err := json.Unmarshal([]byte(entry.Payload), &rawPayload); err != nil {
+		fmt.Printf("Warning: Failed to decode outer payload for entry %s: %v\n", entry.Hash, err)
+		return nil, false
+	}
+
+	// Decode the inner JSON string into a map
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(rawPayload), &payload); err != nil {
+		fmt.Printf("Warning: Failed to decode inner payload for entry %s: %v\n", entry.Hash, err)
+		return nil, false
+	}
+	return payload, true
 }
 
 // Del removes a key-value pair.
@@ -117,51 +221,16 @@ func (kv *KeyValue) Del(key string) (string, error) {
 
 // All retrieves all key-value pairs in the database.
 func (kv *KeyValue) All() (map[string]interface{}, error) {
-	entries, err := kv.Log.Values()
+	records, _, err := kv.state()
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve log entries: %w", err)
+		return nil, err
 	}
 
 	result := make(map[string]interface{})
-	processedKeys := make(map[string]bool)
-
-	// Traverse log entries in reverse order (most recent first)
-	for i := len(entries) - 1; i >= 0; i-- {
-		entry := entries[i]
-
-		// Decode the outer JSON-encoded payload string
-		var rawPayload string
-		err := json.Unmarshal([]byte(entry.Payload), &rawPayload)
-		if err != nil {
-			fmt.Printf("Warning: Failed to decode outer payload for entry %s: %v\n", entry.Hash, err)
-			continue
-		}
-
-		// Decode the inner JSON string into a map
-		var payload map[string]interface{}
-		err = json.Unmarshal([]byte(rawPayload), &payload)
-		if err != nil {
-			fmt.Printf("Warning: Failed to decode inner payload for entry %s: %v\n", entry.Hash, err)
-			continue
-		}
-
-		op, ok := payload["op"].(string)
-		key, _ := payload["key"].(string)
-		value, _ := payload["value"].(interface{})
-
-		// If the key has already been processed, skip it
-		if processedKeys[key] {
-			continue
+	for key, record := range records {
+		if !record.Deleted {
+			result[key] = record.Value
 		}
-
-		if ok && op == "PUT" {
-			result[key] = value
-		} else if op == "DEL" {
-			delete(result, key)
-		}
-
-		processedKeys[key] = true
 	}
-
 	return result, nil
 }
@@ -0,0 +1,75 @@
+package databases
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Compact replaces the history of the key-value log with a single snapshot
+// entry holding the winning record for every key, then prunes every entry it
+// covers except the current heads. The snapshot is appended after the heads
+// and broadcast like any other operation, so lagging peers converge by
+// joining it. Records keep the clocks of the operations they replace, so
+// concurrent writes a peer has not yet delivered still win or lose exactly
+// as they would have against the original history. It returns the hash of
+// the snapshot entry, or an empty hash when the log is empty.
+func (kv *KeyValue) Compact() (string, error) {
+	records, entries, err := kv.state()
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	heads, err := kv.Log.Heads()
+	if err != nil {
+		return "", fmt.Errorf("failed to read heads: %w", err)
+	}
+	retain := make(map[string]bool, len(heads))
+	for _, head := range heads {
+		retain[head.Hash] = true
+	}
+
+	op := map[string]interface{}{
+		"op":    "SNAPSHOT",
+		"state": records,
+	}
+
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize snapshot: %w", err)
+	}
+
+	hash, err := kv.AddOperation(string(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to append snapshot: %w", err)
+	}
+
+	// Only prune entries the snapshot was built from
+	pruned := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !retain[entry.Hash] {
+			pruned = append(pruned, entry.Hash)
+		}
+	}
+	if err := kv.Log.Prune(pruned); err != nil {
+		return hash, fmt.Errorf("failed to prune compacted entries: %w", err)
+	}
+
+	return hash, nil
+}
+
+// decodeSnapshot decodes the records held by a SNAPSHOT operation.
+func decodeSnapshot(payload map[string]interface{}) (map[string]kvRecord, error) {
+	data, err := json.Marshal(payload["state"])
+	if err != nil {
+		return nil, err
+	}
+
+	var records map[string]kvRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
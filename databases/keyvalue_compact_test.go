@@ -0,0 +1,122 @@
+package databases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// setupKeyValuePeer creates a KeyValue replica of "test-address" written by
+// its own identity.
+func setupKeyValuePeer(t *testing.T, id string) *databases.KeyValue {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity(id)
+	require.NoError(t, err)
+
+	host, ps := setupLibp2pHostAndPubSub(t)
+	kv, err := databases.NewKeyValue("test-address", "test-keyvalue", identity, storage.NewMemoryStorage(), ks, host, ps)
+	require.NoError(t, err)
+	return kv
+}
+
+// TestCompactPreservesAll tests that compaction keeps the current state.
+func TestCompactPreservesAll(t *testing.T) {
+	kv := setupKeyValueTest(t)
+
+	for _, value := range []string{"v1", "v2", "v3"} {
+		_, err := kv.Put("key1", value)
+		require.NoError(t, err)
+	}
+	_, err := kv.Put("key2", "value2")
+	require.NoError(t, err)
+	_, err = kv.Put("key3", "value3")
+	require.NoError(t, err)
+	_, err = kv.Del("key3")
+	require.NoError(t, err)
+
+	before, err := kv.All()
+	require.NoError(t, err)
+
+	hash, err := kv.Compact()
+	require.NoError(t, err)
+	assert.NotEmpty(t, hash)
+
+	after, err := kv.All()
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// Only the snapshot and the previous head remain
+	entries, err := kv.Log.Values()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	heads, err := kv.Log.Heads()
+	require.NoError(t, err)
+	require.Len(t, heads, 1)
+	assert.Equal(t, hash, heads[0].Hash)
+
+	// Writes after compaction build on the snapshot
+	_, err = kv.Put("key1", "v4")
+	require.NoError(t, err)
+	_, err = kv.Del("key2")
+	require.NoError(t, err)
+
+	value, err := kv.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "v4", value)
+
+	value, err = kv.Get("key2")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// Compacting an empty log is a no-op
+	empty := setupKeyValueTest(t)
+	hash, err = empty.Compact()
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
+
+// TestCompactLaggingPeer tests that a peer holding an older state, with a
+// concurrent write of its own, converges with a compacted replica.
+func TestCompactLaggingPeer(t *testing.T) {
+	alice := setupKeyValuePeer(t, "alice")
+	bob := setupKeyValuePeer(t, "bob")
+
+	_, err := alice.Put("key1", "v1")
+	require.NoError(t, err)
+	require.NoError(t, bob.Log.Join(alice.Log))
+
+	// Bob writes concurrently while Alice moves on and compacts
+	_, err = bob.Put("bob-key", "bob-value")
+	require.NoError(t, err)
+
+	_, err = alice.Put("key1", "v2")
+	require.NoError(t, err)
+	_, err = alice.Put("key2", "value2")
+	require.NoError(t, err)
+	_, err = alice.Compact()
+	require.NoError(t, err)
+
+	// Bob fetches the snapshot, Alice fetches Bob's write
+	require.NoError(t, bob.Log.Join(alice.Log))
+	require.NoError(t, alice.Log.Join(bob.Log))
+
+	expected := map[string]interface{}{
+		"key1":    "v2",
+		"key2":    "value2",
+		"bob-key": "bob-value",
+	}
+
+	aliceAll, err := alice.All()
+	require.NoError(t, err)
+	assert.Equal(t, expected, aliceAll)
+
+	bobAll, err := bob.All()
+	require.NoError(t, err)
+	assert.Equal(t, expected, bobAll)
+}
@@ -93,6 +93,28 @@ func (kvi *KeyValueIndexed) UpdateIndex() error {
 			if err := kvi.indexStorage.Delete(key); err != nil {
 				fmt.Printf("Warning: Failed to delete key %s from index: %v\n", key, err)
 			}
+		case "SNAPSHOT":
+			records, err := decodeSnapshot(payload)
+			if err != nil {
+				fmt.Printf("Warning: Failed to decode snapshot for entry %s: %v\n", entry.Hash, err)
+				continue
+			}
+			for key, record := range records {
+				if record.Deleted {
+					_ = kvi.indexStorage.Delete(key)
+					continue
+				}
+				serializedEntry, err := json.Marshal(map[string]interface{}{
+					"hash":  entry.Hash,
+					"value": record.Value,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to serialize index entry: %w", err)
+				}
+				if err := kvi.indexStorage.Put(key, serializedEntry); err != nil {
+					fmt.Printf("Warning: Failed to index key %s: %v\n", key, err)
+				}
+			}
 		}
 
 		kvi.processed[entry.Hash] = true
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
 )
 
 // TestPut tests the Put method of KeyValue
@@ -141,3 +142,61 @@ func TestInvalidKey(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "key cannot be empty")
 }
+
+// TestGetWithMeta tests that GetWithMeta reports the author of the winning put
+func TestGetWithMeta(t *testing.T) {
+	kv := setupKeyValueTest(t)
+
+	_, err := kv.Put("key1", "value1")
+	require.NoError(t, err)
+	_, err = kv.Put("key1", "value2")
+	require.NoError(t, err)
+
+	value, author, clock, ok, err := kv.GetWithMeta("key1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value2", value)
+	assert.Equal(t, kv.Identity.Hash, author)
+	assert.Equal(t, kv.Log.Identity.ID, clock.ID)
+	assert.Equal(t, 2, clock.Time)
+
+	// Deleted and unknown keys report no provenance
+	_, err = kv.Del("key1")
+	require.NoError(t, err)
+	_, author, _, ok, err = kv.GetWithMeta("key1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, author)
+
+	_, _, _, ok, err = kv.GetWithMeta("missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestSetGetStringDelete tests the string convenience API of KeyValue
+func TestSetGetStringDelete(t *testing.T) {
+	kv := setupKeyValueTest(t)
+
+	// Setting a key twice returns the latest value
+	require.NoError(t, kv.Set("key1", "value1"))
+	require.NoError(t, kv.Set("key1", "value2"))
+
+	value, err := kv.GetString("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value2", value)
+
+	// Deleting then getting returns a not-found error
+	require.NoError(t, kv.Delete("key1"))
+	_, err = kv.GetString("key1")
+	assert.ErrorIs(t, err, databases.ErrKeyNotFound)
+
+	_, err = kv.GetString("missing")
+	assert.ErrorIs(t, err, databases.ErrKeyNotFound)
+
+	// Non-string values are reported rather than coerced
+	_, err = kv.Put("number", 42)
+	require.NoError(t, err)
+	_, err = kv.GetString("number")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, databases.ErrKeyNotFound)
+}
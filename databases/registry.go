@@ -0,0 +1,64 @@
+package databases
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// DatabaseFactory wraps a base Database into a concrete database type.
+type DatabaseFactory func(db *Database) (interface{}, error)
+
+// databaseRegistry stores available database types.
+var (
+	databaseRegistry   = make(map[string]DatabaseFactory)
+	databaseRegistryMu sync.RWMutex
+)
+
+// RegisterDatabaseType registers a database type under the given name.
+func RegisterDatabaseType(name string, factory DatabaseFactory) {
+	databaseRegistryMu.Lock()
+	defer databaseRegistryMu.Unlock()
+	databaseRegistry[name] = factory
+}
+
+// GetDatabaseType retrieves a database factory by type name.
+func GetDatabaseType(name string) (DatabaseFactory, error) {
+	databaseRegistryMu.RLock()
+	defer databaseRegistryMu.RUnlock()
+
+	factory, exists := databaseRegistry[name]
+	if !exists {
+		return nil, errors.New("database type not found")
+	}
+	return factory, nil
+}
+
+// DatabaseTypes returns the names of all registered database types, sorted.
+func DatabaseTypes() []string {
+	databaseRegistryMu.RLock()
+	defer databaseRegistryMu.RUnlock()
+
+	names := make([]string, 0, len(databaseRegistry))
+	for name := range databaseRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// init registers the built-in database types.
+func init() {
+	RegisterDatabaseType("events", func(db *Database) (interface{}, error) {
+		return NewEvents(db), nil
+	})
+	RegisterDatabaseType("keyvalue", func(db *Database) (interface{}, error) {
+		return &KeyValue{Database: db}, nil
+	})
+	RegisterDatabaseType("documents", func(db *Database) (interface{}, error) {
+		return NewDocuments("", &KeyValue{Database: db})
+	})
+	RegisterDatabaseType("counter", func(db *Database) (interface{}, error) {
+		return NewCounter(db), nil
+	})
+}
@@ -0,0 +1,26 @@
+package databases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
+)
+
+// TestDatabaseRegistry tests registering and resolving database types.
+func TestDatabaseRegistry(t *testing.T) {
+	types := databases.DatabaseTypes()
+	assert.Subset(t, types, []string{"counter", "documents", "events", "keyvalue"})
+
+	_, err := databases.GetDatabaseType("unknown")
+	assert.Error(t, err)
+
+	databases.RegisterDatabaseType("registry-test", func(db *databases.Database) (interface{}, error) {
+		return db, nil
+	})
+	factory, err := databases.GetDatabaseType("registry-test")
+	require.NoError(t, err)
+	assert.NotNil(t, factory)
+	assert.Contains(t, databases.DatabaseTypes(), "registry-test")
+}
@@ -0,0 +1,57 @@
+package databases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/multiformats/go-multibase"
+)
+
+// ShareBundle is everything a peer needs to open a shared database.
+type ShareBundle struct {
+	Address          string   `json:"address"`
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	AccessController string   `json:"accessController,omitempty"`
+	Write            []string `json:"write,omitempty"`
+	Admin            string   `json:"admin,omitempty"`
+}
+
+// ShareLink bundles the database address, name, type, access controller,
+// write list and admin into a single base58btc string that can be opened with OrbitDB.OpenShareLink.
+func (db *Database) ShareLink() (string, error) {
+	if db.Type == "" {
+		return "", errors.New("database type is unknown")
+	}
+
+	data, err := json.Marshal(ShareBundle{
+		Address:          db.Address,
+		Name:             db.Name,
+		Type:             db.Type,
+		AccessController: db.AccessController,
+		Write:            db.Write,
+		Admin:            db.Admin,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode share link: %w", err)
+	}
+	return multibase.Encode(multibase.Base58BTC, data)
+}
+
+// ParseShareLink decodes a link produced by ShareLink.
+func ParseShareLink(link string) (*ShareBundle, error) {
+	_, data, err := multibase.Decode(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share link: %w", err)
+	}
+
+	var bundle ShareBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid share link: %w", err)
+	}
+	if bundle.Address == "" || bundle.Name == "" || bundle.Type == "" {
+		return nil, errors.New("share link is missing required fields")
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,35 @@
+package databases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
+)
+
+// TestShareLink tests encoding and parsing a share link
+func TestShareLink(t *testing.T) {
+	kv := setupKeyValueTest(t)
+
+	// A database without a known type cannot be shared
+	_, err := kv.ShareLink()
+	assert.Error(t, err)
+
+	kv.Type = "keyvalue"
+	kv.AccessController = "ipfs"
+	link, err := kv.ShareLink()
+	require.NoError(t, err)
+
+	bundle, err := databases.ParseShareLink(link)
+	require.NoError(t, err)
+	assert.Equal(t, databases.ShareBundle{
+		Address:          kv.Address,
+		Name:             kv.Name,
+		Type:             "keyvalue",
+		AccessController: "ipfs",
+	}, *bundle)
+
+	_, err = databases.ParseShareLink("zinvalid")
+	assert.Error(t, err)
+}
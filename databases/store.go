@@ -0,0 +1,71 @@
+package databases
+
+import "fmt"
+
+// Store is implemented by databases that can expose their materialized state.
+type Store interface {
+	// SnapshotState returns a deep copy of the current materialized view,
+	// built from a single read of the log so it is consistent at one point
+	// in time. Callers may read or modify it without holding any lock.
+	SnapshotState() any
+
+	// ShareLink returns a link other peers can open with OrbitDB.OpenShareLink.
+	ShareLink() (string, error)
+}
+
+// SnapshotState returns a copy of all key-value pairs.
+func (kv *KeyValue) SnapshotState() any {
+	all, err := kv.All()
+	if err != nil {
+		fmt.Printf("Warning: Failed to snapshot state: %v\n", err)
+		return nil
+	}
+	return deepCopy(all)
+}
+
+// SnapshotState returns a copy of all events, most recent first.
+func (e *Events) SnapshotState() any {
+	all, err := e.All()
+	if err != nil {
+		fmt.Printf("Warning: Failed to snapshot state: %v\n", err)
+		return nil
+	}
+	return deepCopy(all)
+}
+
+// SnapshotState returns the current counter value.
+func (c *Counter) SnapshotState() any {
+	value, err := c.Value()
+	if err != nil {
+		fmt.Printf("Warning: Failed to snapshot state: %v\n", err)
+		return nil
+	}
+	return value
+}
+
+// deepCopy copies the maps and slices produced by JSON decoding so that a
+// snapshot shares no mutable state with the store.
+func deepCopy(v any) any {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			out[k] = deepCopy(item)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]map[string]interface{}, len(value))
+		for i, item := range value {
+			out[i], _ = deepCopy(item).(map[string]interface{})
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, item := range value {
+			out[i] = deepCopy(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,36 @@
+package databases_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/databases"
+)
+
+// TestSnapshotState tests that a snapshot is isolated from later changes to the store
+func TestSnapshotState(t *testing.T) {
+	kv := setupKeyValueTest(t)
+	var store databases.Store = kv
+
+	_, err := kv.Put("key1", map[string]interface{}{"nested": "original"})
+	require.NoError(t, err)
+
+	snapshot, ok := store.SnapshotState().(map[string]interface{})
+	require.True(t, ok)
+
+	// Mutating the store does not alter the snapshot
+	_, err = kv.Put("key1", "updated")
+	require.NoError(t, err)
+	_, err = kv.Put("key2", "value2")
+	require.NoError(t, err)
+
+	assert.Len(t, snapshot, 1)
+	assert.Equal(t, map[string]interface{}{"nested": "original"}, snapshot["key1"])
+
+	// Mutating the snapshot does not alter a later snapshot
+	snapshot["key1"].(map[string]interface{})["nested"] = "changed"
+	latest := store.SnapshotState().(map[string]interface{})
+	assert.Equal(t, "updated", latest["key1"])
+	assert.Equal(t, "value2", latest["key2"])
+}
@@ -0,0 +1,71 @@
+package identities
+
+import (
+	"errors"
+	"fmt"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/keystore"
+)
+
+// ExportIdentity exports the identity created for id together with its
+// private key, encrypted with password; see identitytypes.Identity.Export.
+// Only identities whose keys are held in the KeyStore, of type "publickey",
+// can be exported.
+func (ids *Identities) ExportIdentity(id string, password string) ([]byte, error) {
+	ids.mu.RLock()
+	identity := ids.byID[id]
+	ids.mu.RUnlock()
+	if identity == nil {
+		return nil, fmt.Errorf("no identity for id %s", id)
+	}
+	if identity.Type != "publickey" {
+		return nil, fmt.Errorf("identities of type %q cannot be exported", identity.Type)
+	}
+
+	privateKey, err := ids.keystore.GetKey(identity.ID)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := keystore.SerializePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return identity.Export(keyBytes, password)
+}
+
+// ImportIdentity restores an identity exported with ExportIdentity, adding
+// its private key to the KeyStore so it can sign again.
+func (ids *Identities) ImportIdentity(data []byte, password string) (*identitytypes.Identity, error) {
+	identity, keyBytes, err := identitytypes.ImportIdentity(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := keystore.DeserializePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exported key: %w", err)
+	}
+	if keystore.PublicKeyToHex(&privateKey.PublicKey) != identity.PublicKey {
+		return nil, errors.New("exported key does not match identity public key")
+	}
+	if !ids.VerifyIdentity(identity) {
+		return nil, errors.New("imported identity failed verification")
+	}
+
+	lock := ids.lockFor(identity.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := ids.keystore.AddKey(identity.ID, privateKey); err != nil {
+		return nil, err
+	}
+	if err := ids.persist(identity); err != nil {
+		return nil, fmt.Errorf("failed to store identity: %w", err)
+	}
+
+	ids.mu.Lock()
+	ids.storage[identity.Hash] = identity
+	ids.byID[identity.ID] = identity
+	ids.mu.Unlock()
+	return identity, nil
+}
@@ -0,0 +1,52 @@
+package identities
+
+import (
+	"errors"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+func TestExportImportIdentity(t *testing.T) {
+	source, err := NewIdentities("publickey", storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+	identity, err := source.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+
+	data, err := source.ExportIdentity("test-id", "secret")
+	if err != nil {
+		t.Fatalf("Error exporting identity: %v", err)
+	}
+
+	target, err := NewIdentities("publickey", storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+	if _, err := target.ImportIdentity(data, "wrong"); !errors.Is(err, identitytypes.ErrDecryption) {
+		t.Fatalf("Expected ErrDecryption for a wrong password, got %v", err)
+	}
+
+	imported, err := target.ImportIdentity(data, "secret")
+	if err != nil {
+		t.Fatalf("Error importing identity: %v", err)
+	}
+	if imported.Hash != identity.Hash {
+		t.Errorf("Expected identity %s, got %s", identity.Hash, imported.Hash)
+	}
+
+	// The imported key signs for the same identity
+	signature, err := target.Sign(imported.ID, []byte("data"))
+	if err != nil {
+		t.Fatalf("Error signing with imported key: %v", err)
+	}
+	if !source.Verify(signature, identity, []byte("data")) {
+		t.Error("Expected signature from the imported key to verify")
+	}
+	if found, _ := target.GetIdentity(identity.Hash); found == nil {
+		t.Error("Expected imported identity to be stored")
+	}
+}
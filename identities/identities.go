@@ -2,25 +2,37 @@ package identities
 
 import (
 	"crypto/ecdsa"
-	"crypto/elliptic"
-	"encoding/hex"
 	"errors"
-	"math/big"
+	"fmt"
 	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/identities/providers"
 	"orbitdb/go-orbitdb/keystore"
 	"orbitdb/go-orbitdb/storage"
+	"sort"
+	"sync"
 )
 
 // Identities manages a collection of identities
 type Identities struct {
-	storage  map[string]*identitytypes.Identity
-	provider Provider
-	keystore *keystore.KeyStore
+	storage    map[string]*identitytypes.Identity
+	byID       map[string]*identitytypes.Identity
+	links      map[string][]KeyLink
+	provider   Provider
+	keystore   *keystore.KeyStore
+	backend    storage.Storage
+	serializer Serializer
+	mu         sync.RWMutex
+	idLocks    map[string]*sync.Mutex
+	idLockMu   sync.Mutex
 }
 
 // NewIdentities initializes the identities manager with a specific provider and a KeyStore.
-func NewIdentities(providerType string, storageBackend storage.Storage) (*Identities, error) {
+// Created identities are saved to storageBackend, encoded by the Serializer.
+func NewIdentities(providerType string, storageBackend storage.Storage, opts ...Option) (*Identities, error) {
+	if storageBackend == nil {
+		storageBackend = storage.NewMemoryStorage()
+	}
+
 	// Initialize a KeyStore instance
 	ks := keystore.NewKeyStore(storageBackend)
 
@@ -29,19 +41,39 @@ func NewIdentities(providerType string, storageBackend storage.Storage) (*Identi
 	switch providerType {
 	case "publickey":
 		provider = providers.NewPublicKeyProvider(ks)
+	case "ed25519":
+		provider = providers.NewEd25519Provider(storageBackend)
+	case "ethereum":
+		provider = providers.NewEthereumProvider(storageBackend)
+	case "DID":
+		provider = providers.NewDIDProvider(providers.NewEd25519Provider(storageBackend))
 	default:
 		return nil, errors.New("unsupported provider type")
 	}
 
-	return &Identities{
-		storage:  make(map[string]*identitytypes.Identity),
-		provider: provider,
-		keystore: ks,
-	}, nil
+	ids := &Identities{
+		storage:    make(map[string]*identitytypes.Identity),
+		byID:       make(map[string]*identitytypes.Identity),
+		links:      make(map[string][]KeyLink),
+		provider:   provider,
+		idLocks:    make(map[string]*sync.Mutex),
+		keystore:   ks,
+		backend:    storageBackend,
+		serializer: CBORSerializer{},
+	}
+	for _, opt := range opts {
+		opt(ids)
+	}
+	return ids, nil
 }
 
-// ClearAll clears all keys from the KeyStore
+// ClearAll clears all keys from the KeyStore along with the identities created from them
 func (ids *Identities) ClearAll() {
+	ids.mu.Lock()
+	ids.storage = make(map[string]*identitytypes.Identity)
+	ids.byID = make(map[string]*identitytypes.Identity)
+	ids.links = make(map[string][]KeyLink)
+	ids.mu.Unlock()
 	ids.keystore.Clear()
 }
 
@@ -51,7 +83,20 @@ func (ids *Identities) AddManualKey(id string, privateKey *ecdsa.PrivateKey) err
 }
 
 // CreateIdentity generates a new identity using the selected provider.
+// Creation is serialized per id and idempotent: if an identity for the id
+// already exists it is returned instead of creating a new one.
 func (ids *Identities) CreateIdentity(id string) (*identitytypes.Identity, error) {
+	lock := ids.lockFor(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	ids.mu.RLock()
+	existing := ids.byID[id]
+	ids.mu.RUnlock()
+	if existing != nil {
+		return existing, nil
+	}
+
 	identity, err := ids.provider.CreateIdentity(id)
 	if err != nil {
 		return nil, err
@@ -60,14 +105,71 @@ func (ids *Identities) CreateIdentity(id string) (*identitytypes.Identity, error
 	if !identitytypes.IsIdentity(identity) {
 		return nil, errors.New("invalid identity created")
 	}
+	if err := ids.persist(identity); err != nil {
+		return nil, fmt.Errorf("failed to store identity: %w", err)
+	}
 
 	// Store the identity in the storage map
+	ids.mu.Lock()
 	ids.storage[identity.Hash] = identity
+	ids.byID[id] = identity
+	ids.mu.Unlock()
 	return identity, nil
 }
 
+// lockFor returns the mutex serializing identity creation for an id.
+func (ids *Identities) lockFor(id string) *sync.Mutex {
+	ids.idLockMu.Lock()
+	defer ids.idLockMu.Unlock()
+
+	lock, ok := ids.idLocks[id]
+	if !ok {
+		lock = &sync.Mutex{}
+		ids.idLocks[id] = lock
+	}
+	return lock
+}
+
+// GetIdentity returns the identity with the given hash, loading it from
+// storage when it was created by another Identities instance. It returns
+// nil when the identity is unknown.
 func (ids *Identities) GetIdentity(identityID string) (*identitytypes.Identity, error) {
-	return ids.storage[identityID], nil
+	ids.mu.RLock()
+	identity := ids.storage[identityID]
+	ids.mu.RUnlock()
+	if identity != nil {
+		return identity, nil
+	}
+
+	identity, err := ids.load(identityID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load identity %s: %w", identityID, err)
+	}
+
+	ids.mu.Lock()
+	ids.storage[identity.Hash] = identity
+	ids.mu.Unlock()
+	return identity, nil
+}
+
+// AllIdentities returns the identities known to this instance, created or
+// loaded by GetIdentity, sorted by hash. Identities persisted by other
+// instances are not listed until they are loaded.
+func (ids *Identities) AllIdentities() []*identitytypes.Identity {
+	ids.mu.RLock()
+	defer ids.mu.RUnlock()
+
+	all := make([]*identitytypes.Identity, 0, len(ids.storage))
+	for _, identity := range ids.storage {
+		all = append(all, identity)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Hash < all[j].Hash
+	})
+	return all
 }
 
 // VerifyIdentity verifies the provided identity.
@@ -76,35 +178,51 @@ func (ids *Identities) VerifyIdentity(identity *identitytypes.Identity) bool {
 	return verified
 }
 
-// Sign signs the provided data using the identity's private key from the KeyStore.
+// VerifyMany verifies a batch of identities in parallel. The result holds one
+// entry per input identity, nil when that identity is valid.
+func (ids *Identities) VerifyMany(identities []*identitytypes.Identity) []error {
+	results := make([]error, len(identities))
+
+	var wg sync.WaitGroup
+	for i, identity := range identities {
+		wg.Add(1)
+		go func(i int, identity *identitytypes.Identity) {
+			defer wg.Done()
+			verified, err := ids.provider.VerifyIdentity(identity)
+			if err == nil && !verified {
+				err = errors.New("identity verification failed")
+			}
+			results[i] = err
+		}(i, identity)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Sign signs the provided data using the identity's private key held by the provider.
 func (ids *Identities) Sign(id string, data []byte) (string, error) {
-	// Use KeyStore to sign the data
-	return ids.keystore.SignMessage(id, data)
+	return ids.provider.Sign(id, data)
 }
 
-// Verify verifies the provided signature against the data and public key.
+// Verify verifies the provided signature against the data and the identity's public key.
 func (ids *Identities) Verify(signature string, identity *identitytypes.Identity, data []byte) bool {
-	// Decode the public key from the identity's hex-encoded string
-	publicKeyBytes, err := hex.DecodeString(identity.PublicKey)
-	if err != nil || len(publicKeyBytes) < 64 {
-		return false
-	}
-
-	// Reconstruct the ecdsa.PublicKey from the byte slice
-	pubKey := ecdsa.PublicKey{
-		Curve: elliptic.P256(),
-		X:     new(big.Int).SetBytes(publicKeyBytes[:len(publicKeyBytes)/2]),
-		Y:     new(big.Int).SetBytes(publicKeyBytes[len(publicKeyBytes)/2:]),
-	}
-
-	// Use VerifyMessage from KeyStore to verify the signature
-	verified, err := ids.keystore.VerifyMessage(pubKey, data, signature)
+	verified, err := ids.provider.Verify(signature, identity.PublicKey, data)
 	return err == nil && verified
 }
 
-// init registers the default provider.
+// init registers the default providers.
 func init() {
 	lruStorage, _ := storage.NewLRUStorage(100)
 	ks := keystore.NewKeyStore(lruStorage)
-	RegisterProvider(providers.NewPublicKeyProvider(ks))
+	for _, provider := range []Provider{
+		providers.NewPublicKeyProvider(ks),
+		providers.NewEd25519Provider(storage.NewMemoryStorage()),
+		providers.NewEthereumProvider(storage.NewMemoryStorage()),
+		providers.NewDIDProvider(providers.NewEd25519Provider(storage.NewMemoryStorage())),
+	} {
+		if err := RegisterProvider(provider); err != nil {
+			panic(err)
+		}
+	}
 }
@@ -1,7 +1,10 @@
 package identities
 
 import (
+	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/storage"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -57,6 +60,47 @@ func TestCreateIdentity(t *testing.T) {
 	}
 }
 
+func TestGetIdentityAndAllIdentities(t *testing.T) {
+	identities, err := setupIdentities(storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	first, err := identities.CreateIdentity("first")
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	second, err := identities.CreateIdentity("second")
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+
+	got, err := identities.GetIdentity(second.Hash)
+	if err != nil || got == nil {
+		t.Fatalf("Expected identity %s, got %v (%v)", second.Hash, got, err)
+	}
+	if got.ID != "second" {
+		t.Errorf("Expected identity 'second', got %s", got.ID)
+	}
+
+	if missing, err := identities.GetIdentity("unknown-hash"); err != nil || missing != nil {
+		t.Errorf("Expected no identity for an unknown hash, got %v (%v)", missing, err)
+	}
+
+	all := identities.AllIdentities()
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 identities, got %d", len(all))
+	}
+	if all[0].Hash > all[1].Hash {
+		t.Error("Expected identities sorted by hash")
+	}
+	for _, identity := range []*identitytypes.Identity{first, second} {
+		if all[0] != identity && all[1] != identity {
+			t.Errorf("Expected identity %s to be listed", identity.ID)
+		}
+	}
+}
+
 func TestVerifyIdentity(t *testing.T) {
 	// Initialize an LRUStorage backend for testing
 	lruStorage, err := storage.NewLRUStorage(100)
@@ -120,3 +164,167 @@ func TestSignAndVerify(t *testing.T) {
 		t.Fatal("Expected verification to fail with tampered data")
 	}
 }
+
+func TestVerifyMany(t *testing.T) {
+	// Initialize an LRUStorage backend for testing
+	lruStorage, err := storage.NewLRUStorage(100)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	identities, err := setupIdentities(lruStorage)
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	valid1, err := identities.CreateIdentity("valid-1")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	valid2, err := identities.CreateIdentity("valid-2")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	tampered, err := identities.CreateIdentity("tampered")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	tampered.ID = "tampered-id"
+
+	results := identities.VerifyMany([]*identitytypes.Identity{valid1, tampered, nil, valid2})
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	expectValid := []bool{true, false, false, true}
+	for i, err := range results {
+		if expectValid[i] && err != nil {
+			t.Errorf("Expected identity %d to verify, got %v", i, err)
+		}
+		if !expectValid[i] && err == nil {
+			t.Errorf("Expected identity %d to fail verification", i)
+		}
+	}
+}
+
+func TestCreateIdentityConcurrent(t *testing.T) {
+	// Initialize an LRUStorage backend for testing
+	lruStorage, err := storage.NewLRUStorage(100)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	identities, err := setupIdentities(lruStorage)
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	const workers = 20
+	results := make([]*identitytypes.Identity, workers)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = identities.CreateIdentity("alice")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Worker %d failed to create identity: %v", i, err)
+		}
+		if results[i] != results[0] {
+			t.Fatalf("Worker %d got a different identity instance", i)
+		}
+	}
+
+	if len(identities.storage) != 1 {
+		t.Fatalf("Expected a single stored identity, got %d", len(identities.storage))
+	}
+}
+
+func TestEd25519Identities(t *testing.T) {
+	identities, err := NewIdentities("ed25519", storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	identity, err := identities.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	if identity.Type != "ed25519" || !identities.VerifyIdentity(identity) {
+		t.Fatalf("Expected a valid ed25519 identity, got %+v", identity)
+	}
+
+	data := []byte("test data")
+	signature, err := identities.Sign(identity.ID, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing data, got %v", err)
+	}
+	if !identities.Verify(signature, identity, data) {
+		t.Fatal("Expected valid signature verification to succeed")
+	}
+	if identities.Verify(signature, identity, []byte("tampered data")) {
+		t.Fatal("Expected verification to fail with tampered data")
+	}
+
+	if _, err := GetProvider("ed25519"); err != nil {
+		t.Fatalf("Expected ed25519 provider to be registered: %v", err)
+	}
+}
+
+func TestEthereumIdentities(t *testing.T) {
+	identities, err := NewIdentities("ethereum", storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	identity, err := identities.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	if identity.Type != "ethereum" || !identities.VerifyIdentity(identity) {
+		t.Fatalf("Expected a valid ethereum identity, got %+v", identity)
+	}
+
+	data := []byte("test data")
+	signature, err := identities.Sign(identity.ID, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing data, got %v", err)
+	}
+	if !identities.Verify(signature, identity, data) {
+		t.Fatal("Expected valid signature verification to succeed")
+	}
+	if identities.Verify(signature, identity, []byte("tampered data")) {
+		t.Fatal("Expected verification to fail with tampered data")
+	}
+}
+
+func TestDIDIdentities(t *testing.T) {
+	identities, err := NewIdentities("DID", storage.NewMemoryStorage())
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	identity, err := identities.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	if identity.Type != "DID" || !strings.HasPrefix(identity.ID, "did:key:") || !identities.VerifyIdentity(identity) {
+		t.Fatalf("Expected a valid DID identity, got %+v", identity)
+	}
+
+	data := []byte("test data")
+	signature, err := identities.Sign(identity.ID, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing data, got %v", err)
+	}
+	if !identities.Verify(signature, identity, data) {
+		t.Fatal("Expected valid signature verification to succeed")
+	}
+}
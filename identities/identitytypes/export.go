@@ -0,0 +1,113 @@
+package identitytypes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrDecryption is returned by ImportIdentity when the private key cannot be
+// decrypted, usually because the password is wrong.
+var ErrDecryption = errors.New("failed to decrypt identity key: wrong password or corrupted export")
+
+// scrypt parameters used to derive the export encryption key.
+const (
+	exportScryptN = 1 << 15
+	exportScryptR = 8
+	exportScryptP = 1
+	exportKeyLen  = 32
+	exportSaltLen = 16
+)
+
+// identityExport is the serialized form of an exported identity. The
+// identity itself is public and stored in its JSON form; only the private
+// key is encrypted, with the identity hash as additional data so the key
+// cannot be paired with a different identity.
+type identityExport struct {
+	Identity json.RawMessage `json:"identity"`
+	Salt     []byte          `json:"salt"`
+	Nonce    []byte          `json:"nonce"`
+	Key      []byte          `json:"key"`
+}
+
+// Export serializes the identity together with its private key, encrypting
+// the key with AES-GCM under a key derived from password with scrypt. The
+// private key format is up to the caller; ImportIdentity returns it as is.
+func (i *Identity) Export(privateKey []byte, password string) ([]byte, error) {
+	if !IsIdentity(i) {
+		return nil, errors.New("identity is missing required fields")
+	}
+	if password == "" {
+		return nil, errors.New("password is required")
+	}
+
+	identityJSON, err := i.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, exportSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := exportCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(identityExport{
+		Identity: identityJSON,
+		Salt:     salt,
+		Nonce:    nonce,
+		Key:      gcm.Seal(nil, nonce, privateKey, []byte(i.Hash)),
+	})
+}
+
+// ImportIdentity reverses Export, returning the identity and its decrypted
+// private key. A wrong password returns ErrDecryption.
+func ImportIdentity(data []byte, password string) (*Identity, []byte, error) {
+	var export identityExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, nil, fmt.Errorf("invalid identity export: %w", err)
+	}
+
+	identity, err := UnmarshalIdentityJSON(export.Identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gcm, err := exportCipher(password, export.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(export.Nonce) != gcm.NonceSize() {
+		return nil, nil, errors.New("invalid identity export: bad nonce")
+	}
+	privateKey, err := gcm.Open(nil, export.Nonce, export.Key, []byte(identity.Hash))
+	if err != nil {
+		return nil, nil, ErrDecryption
+	}
+	return identity, privateKey, nil
+}
+
+// exportCipher derives the AES-GCM cipher for a password and salt.
+func exportCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, exportScryptN, exportScryptR, exportScryptP, exportKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
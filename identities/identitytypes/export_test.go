@@ -0,0 +1,63 @@
+package identitytypes
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func newExportTestIdentity(t *testing.T) *Identity {
+	t.Helper()
+	identity := &Identity{
+		ID:         "test-id",
+		PublicKey:  "test-public-key",
+		Signatures: map[string]string{SignatureID: "id-signature", SignaturePublicKey: "public-key-signature"},
+		Type:       "publickey",
+	}
+	hash, encoded, err := EncodeIdentity(*identity)
+	if err != nil {
+		t.Fatalf("Failed to encode identity: %v", err)
+	}
+	identity.Hash, identity.Bytes = hash, encoded
+	return identity
+}
+
+func TestIdentityExportImport(t *testing.T) {
+	identity := newExportTestIdentity(t)
+	privateKey := []byte("private key material")
+
+	data, err := identity.Export(privateKey, "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to export identity: %v", err)
+	}
+	if bytes.Contains(data, privateKey) {
+		t.Fatal("Expected the private key to be encrypted in the export")
+	}
+
+	imported, key, err := ImportIdentity(data, "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to import identity: %v", err)
+	}
+	if !IsEqual(imported, identity) || imported.Hash != identity.Hash {
+		t.Errorf("Expected imported identity %+v, got %+v", identity, imported)
+	}
+	if !bytes.Equal(key, privateKey) {
+		t.Errorf("Expected private key %q, got %q", privateKey, key)
+	}
+}
+
+func TestIdentityImportWrongPassword(t *testing.T) {
+	identity := newExportTestIdentity(t)
+
+	data, err := identity.Export([]byte("private key material"), "correct horse")
+	if err != nil {
+		t.Fatalf("Failed to export identity: %v", err)
+	}
+
+	if _, _, err := ImportIdentity(data, "wrong password"); !errors.Is(err, ErrDecryption) {
+		t.Errorf("Expected ErrDecryption for a wrong password, got %v", err)
+	}
+	if _, err := identity.Export([]byte("key"), ""); err == nil {
+		t.Error("Expected an empty password to be rejected")
+	}
+}
@@ -2,6 +2,7 @@ package identitytypes
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/ipfs/go-cid"
@@ -10,6 +11,13 @@ import (
 	"github.com/ipld/go-ipld-prime/node/basicnode"
 	"github.com/multiformats/go-multibase"
 	mh "github.com/multiformats/go-multihash"
+	"strings"
+)
+
+// Keys of the two signatures every identity carries.
+const (
+	SignatureID        = "id"
+	SignaturePublicKey = "publicKey"
 )
 
 // Identity represents a basic identity structure.
@@ -30,6 +38,17 @@ type EncodedIdentity struct {
 	Hash     string
 }
 
+// IDSigningPayload returns the bytes signed for the "id" signature: the raw identity ID.
+func IDSigningPayload(identity *Identity) []byte {
+	return []byte(identity.ID)
+}
+
+// PublicKeySigningPayload returns the bytes signed for the "publicKey" signature:
+// the hex-encoded public key exactly as stored in the identity.
+func PublicKeySigningPayload(identity *Identity) []byte {
+	return []byte(identity.PublicKey)
+}
+
 // IsIdentity Checks if an identity has all required fields populated.
 func IsIdentity(identity *Identity) bool {
 	return identity != nil &&
@@ -38,8 +57,8 @@ func IsIdentity(identity *Identity) bool {
 		identity.Bytes != nil &&
 		identity.PublicKey != "" &&
 		identity.Signatures != nil &&
-		identity.Signatures["id"] != "" &&
-		identity.Signatures["publicKey"] != "" &&
+		identity.Signatures[SignatureID] != "" &&
+		identity.Signatures[SignaturePublicKey] != "" &&
 		identity.Type != ""
 }
 
@@ -78,6 +97,20 @@ func IsEqual(a, b *Identity) bool {
 	return equal
 }
 
+// IsEqualByPublicKey reports whether two identities name the same signer:
+// the same ID, type and public key. Unlike IsEqual it ignores the hash,
+// bytes and signatures, which change with the encoding or when the
+// identity is re-signed. Hex public keys are compared case-insensitively.
+func IsEqualByPublicKey(a, b *Identity) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.ID == b.ID &&
+		a.Type == b.Type &&
+		a.PublicKey != "" &&
+		strings.EqualFold(a.PublicKey, b.PublicKey)
+}
+
 // EncodeIdentity encodes an Identity instance into CBOR format and returns hash, bytes, and error.
 func EncodeIdentity(identity Identity) (string, []byte, error) {
 	// Initialize a basic map node for encoding with canonical field order
@@ -92,6 +125,7 @@ func EncodeIdentity(identity Identity) (string, []byte, error) {
 	ma.AssembleValue().AssignString(identity.PublicKey)
 
 	ma.AssembleKey().AssignString("signatures")
+	// dagcbor sorts map keys, so the signatures encode in a fixed order
 	sigMap, _ := ma.AssembleValue().BeginMap(int64(len(identity.Signatures)))
 	for k, v := range identity.Signatures {
 		sigMap.AssembleKey().AssignString(k)
@@ -212,3 +246,56 @@ func DecodeIdentity(encodedData []byte) (*Identity, error) {
 
 	return &identity, nil
 }
+
+// identityJSON is the JSON representation of an Identity. It carries only
+// public data; Bytes is rebuilt from the other fields on decode.
+type identityJSON struct {
+	ID         string            `json:"id"`
+	PublicKey  string            `json:"publicKey"`
+	Signatures map[string]string `json:"signatures"`
+	Type       string            `json:"type"`
+	Hash       string            `json:"hash"`
+}
+
+// MarshalJSON encodes the identity's ID, PublicKey, Signatures, Type and Hash.
+func (i Identity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(identityJSON{
+		ID:         i.ID,
+		PublicKey:  i.PublicKey,
+		Signatures: i.Signatures,
+		Type:       i.Type,
+		Hash:       i.Hash,
+	})
+}
+
+// UnmarshalIdentityJSON decodes an identity produced by MarshalJSON. The
+// encoded bytes are recomputed from the decoded fields and must hash to the
+// transmitted Hash; identities missing required fields are rejected.
+func UnmarshalIdentityJSON(b []byte) (*Identity, error) {
+	var data identityJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("invalid identity JSON: %w", err)
+	}
+
+	identity := Identity{
+		ID:         data.ID,
+		PublicKey:  data.PublicKey,
+		Signatures: data.Signatures,
+		Type:       data.Type,
+		Hash:       data.Hash,
+	}
+
+	hash, encodedBytes, err := EncodeIdentity(identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Bytes = encodedBytes
+
+	if !IsIdentity(&identity) {
+		return nil, errors.New("identity is missing required fields")
+	}
+	if hash != identity.Hash {
+		return nil, fmt.Errorf("identity hash mismatch: expected %s, got %s", hash, identity.Hash)
+	}
+	return &identity, nil
+}
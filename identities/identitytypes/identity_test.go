@@ -1,6 +1,7 @@
 package identitytypes
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -89,6 +90,44 @@ func TestIsEqual(t *testing.T) {
 	}
 }
 
+// TestIsEqualByPublicKey checks that identities match on their key material
+// regardless of how they were signed and encoded.
+func TestIsEqualByPublicKey(t *testing.T) {
+	identityA, err := createTestIdentity("test-id", "test-type")
+	if err != nil {
+		t.Fatalf("Failed to create test identity A: %v", err)
+	}
+
+	// The same key, signed and encoded independently
+	identityB := &Identity{
+		ID:         identityA.ID,
+		PublicKey:  identityA.PublicKey,
+		Signatures: map[string]string{"id": "other-id-signature", "publicKey": "other-publicKey-signature"},
+		Type:       identityA.Type,
+	}
+	if identityB.Hash, identityB.Bytes, err = EncodeIdentity(*identityB); err != nil {
+		t.Fatalf("Failed to encode identity B: %v", err)
+	}
+	if identityA.Hash == identityB.Hash {
+		t.Fatal("Expected independently encoded identities to have different hashes")
+	}
+	if !IsEqualByPublicKey(identityA, identityB) {
+		t.Error("Expected identities with the same key to be equal")
+	}
+
+	// A different key is a different signer
+	identityC, err := createTestIdentity("test-id", "test-type")
+	if err != nil {
+		t.Fatalf("Failed to create test identity C: %v", err)
+	}
+	if IsEqualByPublicKey(identityA, identityC) {
+		t.Error("Expected identities with different keys not to be equal")
+	}
+	if IsEqualByPublicKey(identityA, nil) {
+		t.Error("Expected a nil identity not to be equal")
+	}
+}
+
 // TestEncodeDecodeIdentity verifies encoding and decoding of an identity.
 func TestEncodeDecodeIdentity(t *testing.T) {
 	identity, err := createTestIdentity("test-id", "test-type")
@@ -119,3 +158,82 @@ func TestEncodeDecodeIdentity(t *testing.T) {
 		t.Fatal("Expected decoded identity to be equal to the original")
 	}
 }
+
+// TestEncodeIdentityDeterministic verifies that signature order does not affect the hash.
+// TestEncodeIdentityCanonical pins the encoding of an identity whose
+// signatures map is built in different insertion orders.
+func TestEncodeIdentityCanonical(t *testing.T) {
+	const (
+		expectedHash  = "zdpuB333WrXWPiyeM9M2xRRACDDVtwskDkzqEdBdXRvuj9WXH"
+		expectedBytes = "a462696467746573742d6964647479706569746573742d74797065697075626c69634b657964303461626a7369676e617475726573a3626964667369672d6964656578747261697369672d6578747261697075626c69634b6579667369672d706b"
+	)
+	signatures := [][2]string{{"id", "sig-id"}, {"publicKey", "sig-pk"}, {"extra", "sig-extra"}}
+	orders := [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}}
+
+	for _, order := range orders {
+		identity := Identity{ID: "test-id", PublicKey: "04ab", Type: "test-type", Signatures: make(map[string]string)}
+		for _, i := range order {
+			identity.Signatures[signatures[i][0]] = signatures[i][1]
+		}
+
+		hash, data, err := EncodeIdentity(identity)
+		if err != nil {
+			t.Fatalf("Failed to encode identity: %v", err)
+		}
+		if hash != expectedHash {
+			t.Errorf("Insertion order %v: expected hash %s, got %s", order, expectedHash, hash)
+		}
+		if hex.EncodeToString(data) != expectedBytes {
+			t.Errorf("Insertion order %v: expected bytes %s, got %x", order, expectedBytes, data)
+		}
+	}
+}
+
+// TestIdentityJSON verifies the JSON round trip and rejection of incomplete identities.
+func TestIdentityJSON(t *testing.T) {
+	identity, err := createTestIdentity("test-id", "test-type")
+	if err != nil {
+		t.Fatalf("Failed to create test identity: %v", err)
+	}
+
+	data, err := identity.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal identity: %v", err)
+	}
+
+	decoded, err := UnmarshalIdentityJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal identity: %v", err)
+	}
+	if !IsEqual(identity, decoded) || decoded.Type != identity.Type {
+		t.Fatal("Expected unmarshaled identity to be equal to the original")
+	}
+	if !bytes.Equal(decoded.Bytes, identity.Bytes) {
+		t.Error("Expected unmarshaled identity bytes to match the original")
+	}
+
+	missing := []string{
+		`{"publicKey":"ab","signatures":{"id":"a","publicKey":"b"},"type":"t","hash":"h"}`,
+		`{"id":"x","signatures":{"id":"a","publicKey":"b"},"type":"t","hash":"h"}`,
+		`{"id":"x","publicKey":"ab","signatures":{"id":"a"},"type":"t","hash":"h"}`,
+		`{"id":"x","publicKey":"ab","signatures":{"id":"a","publicKey":"b"},"hash":"h"}`,
+		`{"id":"x","publicKey":"ab","signatures":{"id":"a","publicKey":"b"},"type":"t"}`,
+		`not json`,
+	}
+	for _, input := range missing {
+		if _, err := UnmarshalIdentityJSON([]byte(input)); err == nil {
+			t.Errorf("Expected incomplete identity %s to be rejected", input)
+		}
+	}
+
+	// A tampered field no longer matches the transmitted hash
+	tampered := *identity
+	tampered.ID = "other-id"
+	data, err = tampered.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal identity: %v", err)
+	}
+	if _, err := UnmarshalIdentityJSON(data); err == nil {
+		t.Error("Expected identity with a mismatched hash to be rejected")
+	}
+}
@@ -2,7 +2,10 @@ package identities
 
 import (
 	"errors"
+	"fmt"
 	"orbitdb/go-orbitdb/identities/identitytypes"
+	"sort"
+	"sync"
 )
 
 // Provider defines an interface for identity providers.
@@ -10,21 +13,64 @@ type Provider interface {
 	Type() string
 	CreateIdentity(id string) (*identitytypes.Identity, error)
 	VerifyIdentity(identity *identitytypes.Identity) (bool, error)
+	Sign(id string, data []byte) (string, error)
+	Verify(signature string, publicKey string, data []byte) (bool, error)
 }
 
 // providerRegistry stores available providers.
-var providerRegistry = make(map[string]Provider)
+var (
+	providerRegistry   = make(map[string]Provider)
+	providerRegistryMu sync.RWMutex
+)
+
+// RegisterProvider registers a new provider for creating identities. A
+// provider whose type is already registered is rejected; unregister the
+// existing one first to replace it.
+func RegisterProvider(provider Provider) error {
+	providerRegistryMu.Lock()
+	defer providerRegistryMu.Unlock()
+
+	providerType := provider.Type()
+	if _, exists := providerRegistry[providerType]; exists {
+		return fmt.Errorf("provider type %q is already registered", providerType)
+	}
+	providerRegistry[providerType] = provider
+	return nil
+}
+
+// UnregisterProvider removes the provider registered for a type.
+func UnregisterProvider(providerType string) error {
+	providerRegistryMu.Lock()
+	defer providerRegistryMu.Unlock()
 
-// RegisterProvider registers a new provider for creating identities.
-func RegisterProvider(provider Provider) {
-	providerRegistry[provider.Type()] = provider
+	if _, exists := providerRegistry[providerType]; !exists {
+		return fmt.Errorf("provider type %q is not registered", providerType)
+	}
+	delete(providerRegistry, providerType)
+	return nil
 }
 
 // GetProvider retrieves a provider by type.
 func GetProvider(providerType string) (Provider, error) {
+	providerRegistryMu.RLock()
+	defer providerRegistryMu.RUnlock()
+
 	provider, exists := providerRegistry[providerType]
 	if !exists {
 		return nil, errors.New("provider not found")
 	}
 	return provider, nil
 }
+
+// ListProviders returns the types of all registered providers, sorted.
+func ListProviders() []string {
+	providerRegistryMu.RLock()
+	defer providerRegistryMu.RUnlock()
+
+	types := make([]string, 0, len(providerRegistry))
+	for providerType := range providerRegistry {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,84 @@
+package identities
+
+import (
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"testing"
+)
+
+// stubProvider is a minimal provider registered under a test type.
+type stubProvider struct {
+	providerType string
+}
+
+func (p stubProvider) Type() string { return p.providerType }
+
+func (p stubProvider) CreateIdentity(id string) (*identitytypes.Identity, error) { return nil, nil }
+
+func (p stubProvider) VerifyIdentity(identity *identitytypes.Identity) (bool, error) {
+	return false, nil
+}
+
+func (p stubProvider) Sign(id string, data []byte) (string, error) { return "", nil }
+
+func (p stubProvider) Verify(signature string, publicKey string, data []byte) (bool, error) {
+	return false, nil
+}
+
+func contains(types []string, providerType string) bool {
+	for _, registered := range types {
+		if registered == providerType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProviderRegistry(t *testing.T) {
+	for _, builtin := range []string{"DID", "ed25519", "ethereum", "publickey"} {
+		if !contains(ListProviders(), builtin) {
+			t.Errorf("Expected built-in provider %q to be listed", builtin)
+		}
+	}
+
+	if err := RegisterProvider(stubProvider{providerType: "stub"}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if !contains(ListProviders(), "stub") {
+		t.Error("Expected registered provider to be listed")
+	}
+	if _, err := GetProvider("stub"); err != nil {
+		t.Errorf("Expected registered provider to be found, got %v", err)
+	}
+
+	// A duplicate type is rejected and the original provider is kept
+	if err := RegisterProvider(stubProvider{providerType: "stub"}); err == nil {
+		t.Error("Expected duplicate provider type to be rejected")
+	}
+	if err := RegisterProvider(stubProvider{providerType: "publickey"}); err == nil {
+		t.Error("Expected built-in provider type to be protected from overwriting")
+	}
+	if provider, err := GetProvider("publickey"); err != nil {
+		t.Errorf("Expected publickey provider, got %v", err)
+	} else if _, isStub := provider.(stubProvider); isStub {
+		t.Error("Expected built-in publickey provider to remain registered")
+	}
+
+	if err := UnregisterProvider("stub"); err != nil {
+		t.Fatalf("Failed to unregister provider: %v", err)
+	}
+	if contains(ListProviders(), "stub") {
+		t.Error("Expected unregistered provider to be removed from the list")
+	}
+	if _, err := GetProvider("stub"); err == nil {
+		t.Error("Expected unregistered provider to be gone")
+	}
+	if err := UnregisterProvider("stub"); err == nil {
+		t.Error("Expected unregistering an unknown type to fail")
+	}
+
+	// The type can be registered again once removed
+	if err := RegisterProvider(stubProvider{providerType: "stub"}); err != nil {
+		t.Errorf("Expected re-registration to succeed, got %v", err)
+	}
+	UnregisterProvider("stub")
+}
@@ -0,0 +1,217 @@
+package providers
+
+import (
+	"crypto/elliptic"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/keystore"
+	"strings"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/multiformats/go-multibase"
+)
+
+// DIDKeyPrefix is the prefix of did:key identifiers.
+const DIDKeyPrefix = "did:key:"
+
+// Multicodec codes of the public key types did:key identifiers can encode.
+const (
+	codecEd25519   = 0xed
+	codecSecp256k1 = 0xe7
+	codecP256      = 0x1200
+)
+
+// KeyProvider is the part of an identity provider a DIDProvider wraps: it
+// owns the keys, while the DIDProvider names identities after them.
+type KeyProvider interface {
+	Type() string
+	CreateIdentity(id string) (*identitytypes.Identity, error)
+	Sign(id string, data []byte) (string, error)
+	Verify(signature string, publicKey string, data []byte) (bool, error)
+}
+
+// DIDProvider creates identities whose ID is the did:key identifier of the
+// key held by a wrapped provider, so they can be matched with DID identities
+// of other OrbitDB implementations. The public key and signatures keep the
+// wrapped provider's format. Keys of the "publickey", "ed25519" and
+// "ethereum" providers are supported.
+type DIDProvider struct {
+	keys KeyProvider
+	ids  map[string]string // DID to the id the key was created under
+	mu   sync.RWMutex
+}
+
+// NewDIDProvider creates a new DIDProvider using the keys of provider.
+func NewDIDProvider(provider KeyProvider) *DIDProvider {
+	return &DIDProvider{keys: provider, ids: make(map[string]string)}
+}
+
+func (p *DIDProvider) Type() string {
+	return "DID"
+}
+
+// CreateIdentity creates a key for id with the wrapped provider and returns
+// an identity named by its DID, signing the DID and public key.
+func (p *DIDProvider) CreateIdentity(id string) (*identitytypes.Identity, error) {
+	keyIdentity, err := p.keys.CreateIdentity(id)
+	if err != nil {
+		return nil, err
+	}
+	did, err := DIDKey(p.keys.Type(), keyIdentity.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.ids[did] = id
+	p.mu.Unlock()
+
+	// Create the identity instance
+	identity := &identitytypes.Identity{
+		ID:         did,
+		PublicKey:  keyIdentity.PublicKey,
+		Signatures: make(map[string]string),
+		Type:       p.Type(),
+	}
+
+	// Sign the canonical ID and public key payloads
+	if identity.Signatures[identitytypes.SignatureID], err = p.keys.Sign(id, identitytypes.IDSigningPayload(identity)); err != nil {
+		return nil, err
+	}
+	if identity.Signatures[identitytypes.SignaturePublicKey], err = p.keys.Sign(id, identitytypes.PublicKeySigningPayload(identity)); err != nil {
+		return nil, err
+	}
+
+	// Encode identity to generate hash and bytes representation
+	hash, bytes, err := identitytypes.EncodeIdentity(*identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Hash = hash
+	identity.Bytes = bytes
+
+	return identity, nil
+}
+
+// VerifyIdentity checks that the identity has all required fields, that its
+// DID encodes its public key, and that both signatures are valid.
+func (p *DIDProvider) VerifyIdentity(identity *identitytypes.Identity) (bool, error) {
+	if !identitytypes.IsIdentity(identity) {
+		return false, errors.New("identity is missing required fields")
+	}
+
+	keyType, publicKey, err := ParseDIDKey(identity.ID)
+	if err != nil {
+		return false, err
+	}
+	if keyType != p.keys.Type() || !strings.EqualFold(publicKey, identity.PublicKey) {
+		return false, errors.New("DID does not encode the identity's public key")
+	}
+
+	if ok, err := p.Verify(identity.Signatures[identitytypes.SignatureID], identity.PublicKey, identitytypes.IDSigningPayload(identity)); err != nil || !ok {
+		return false, errors.New("invalid ID signature")
+	}
+	if ok, err := p.Verify(identity.Signatures[identitytypes.SignaturePublicKey], identity.PublicKey, identitytypes.PublicKeySigningPayload(identity)); err != nil || !ok {
+		return false, errors.New("invalid public key signature")
+	}
+
+	return true, nil
+}
+
+// Sign signs data with the key of id, which may be a DID created by this
+// provider or the id passed to CreateIdentity. DIDs are remembered in memory
+// only, so after a restart sign with the original id.
+func (p *DIDProvider) Sign(id string, data []byte) (string, error) {
+	p.mu.RLock()
+	if keyID, ok := p.ids[id]; ok {
+		id = keyID
+	}
+	p.mu.RUnlock()
+	return p.keys.Sign(id, data)
+}
+
+// Verify checks a signature with the wrapped provider.
+func (p *DIDProvider) Verify(signature string, publicKey string, data []byte) (bool, error) {
+	return p.keys.Verify(signature, publicKey, data)
+}
+
+// DIDKey returns the did:key identifier of a hex public key in the format of
+// the given provider type.
+func DIDKey(keyType string, publicKey string) (string, error) {
+	var codec uint64
+	var keyBytes []byte
+	switch keyType {
+	case "publickey":
+		pubKey, err := keystore.ReconstructPublicKeyFromHex(publicKey)
+		if err != nil {
+			return "", err
+		}
+		codec, keyBytes = codecP256, elliptic.MarshalCompressed(elliptic.P256(), pubKey.X, pubKey.Y)
+	case "ed25519":
+		raw, err := hex.DecodeString(publicKey)
+		if err != nil || len(raw) != 32 {
+			return "", errors.New("invalid ed25519 public key")
+		}
+		codec, keyBytes = codecEd25519, raw
+	case "ethereum":
+		pubKey, err := parseEthereumPublicKey(publicKey)
+		if err != nil {
+			return "", err
+		}
+		codec, keyBytes = codecSecp256k1, pubKey.SerializeCompressed()
+	default:
+		return "", fmt.Errorf("unsupported key type %q for did:key", keyType)
+	}
+
+	encoded, err := multibase.Encode(multibase.Base58BTC, append(binary.AppendUvarint(nil, codec), keyBytes...))
+	if err != nil {
+		return "", err
+	}
+	return DIDKeyPrefix + encoded, nil
+}
+
+// ParseDIDKey decodes a did:key identifier into the provider type and hex
+// public key it encodes, the inverse of DIDKey.
+func ParseDIDKey(did string) (keyType string, publicKey string, err error) {
+	if !strings.HasPrefix(did, DIDKeyPrefix) {
+		return "", "", errors.New("not a did:key identifier")
+	}
+	encoding, data, err := multibase.Decode(strings.TrimPrefix(did, DIDKeyPrefix))
+	if err != nil || encoding != multibase.Base58BTC {
+		return "", "", errors.New("invalid did:key encoding")
+	}
+	codec, n := binary.Uvarint(data)
+	if n <= 0 {
+		return "", "", errors.New("invalid did:key multicodec")
+	}
+	keyBytes := data[n:]
+
+	switch codec {
+	case codecP256:
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), keyBytes)
+		if x == nil {
+			return "", "", errors.New("invalid P-256 key in did:key")
+		}
+		raw := make([]byte, 64)
+		x.FillBytes(raw[:32])
+		y.FillBytes(raw[32:])
+		return "publickey", hex.EncodeToString(raw), nil
+	case codecEd25519:
+		if len(keyBytes) != 32 {
+			return "", "", errors.New("invalid ed25519 key in did:key")
+		}
+		return "ed25519", hex.EncodeToString(keyBytes), nil
+	case codecSecp256k1:
+		pubKey, err := secp256k1.ParsePubKey(keyBytes)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid secp256k1 key in did:key: %w", err)
+		}
+		return "ethereum", hex.EncodeToString(pubKey.SerializeUncompressed()[1:]), nil
+	default:
+		return "", "", fmt.Errorf("unsupported did:key multicodec 0x%x", codec)
+	}
+}
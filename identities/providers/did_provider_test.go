@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/storage"
+	"strings"
+	"testing"
+)
+
+// The did:key test vector for the ed25519 key with an all-zero seed.
+const (
+	didTestSeed = "0000000000000000000000000000000000000000000000000000000000000000"
+	didTestKey  = "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"
+)
+
+func TestDIDProviderType(t *testing.T) {
+	provider := NewDIDProvider(NewEd25519Provider(nil))
+	if provider.Type() != "DID" {
+		t.Fatalf("Expected provider type 'DID', got %s", provider.Type())
+	}
+}
+
+func TestDIDKnownKey(t *testing.T) {
+	keys := storage.NewMemoryStorage()
+	if err := keys.Put("ed25519_test-id", []byte(didTestSeed)); err != nil {
+		t.Fatalf("Failed to store seed: %v", err)
+	}
+	provider := NewDIDProvider(NewEd25519Provider(keys))
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.ID != didTestKey {
+		t.Fatalf("Expected ID %s, got %s", didTestKey, identity.ID)
+	}
+	if identity.Type != "DID" {
+		t.Fatalf("Expected type 'DID', got %s", identity.Type)
+	}
+
+	valid, err := provider.VerifyIdentity(identity)
+	if err != nil || !valid {
+		t.Fatalf("Expected identity to verify, got %v (%v)", valid, err)
+	}
+
+	// The DID and the original id both sign with the key
+	for _, id := range []string{identity.ID, "test-id"} {
+		signature, err := provider.Sign(id, []byte("data"))
+		if err != nil {
+			t.Fatalf("Failed to sign with %s: %v", id, err)
+		}
+		if ok, err := provider.Verify(signature, identity.PublicKey, []byte("data")); err != nil || !ok {
+			t.Errorf("Expected signature by %s to verify, got %v (%v)", id, ok, err)
+		}
+	}
+}
+
+func TestDIDVerifyRejectsMismatchedKey(t *testing.T) {
+	provider := NewDIDProvider(NewEd25519Provider(storage.NewMemoryStorage()))
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	other, err := provider.CreateIdentity("other-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A DID naming another key does not match the signing key
+	tampered := *identity
+	tampered.ID = other.ID
+	if valid, _ := provider.VerifyIdentity(&tampered); valid {
+		t.Fatal("Expected an identity whose DID encodes another key to fail verification")
+	}
+
+	tampered = *identity
+	tampered.ID = "not-a-did"
+	if valid, _ := provider.VerifyIdentity(&tampered); valid {
+		t.Fatal("Expected an identity without a DID to fail verification")
+	}
+}
+
+func TestDIDKeyRoundTrip(t *testing.T) {
+	for _, keys := range []KeyProvider{
+		NewPublicKeyProvider(keystore.NewKeyStore(storage.NewMemoryStorage())),
+		NewEd25519Provider(nil),
+		NewEthereumProvider(nil),
+	} {
+		identity, err := keys.CreateIdentity("test-id")
+		if err != nil {
+			t.Fatalf("%s: failed to create identity: %v", keys.Type(), err)
+		}
+
+		did, err := DIDKey(keys.Type(), identity.PublicKey)
+		if err != nil || !strings.HasPrefix(did, DIDKeyPrefix+"z") {
+			t.Fatalf("%s: expected a did:key, got %q (%v)", keys.Type(), did, err)
+		}
+		keyType, publicKey, err := ParseDIDKey(did)
+		if err != nil {
+			t.Fatalf("%s: failed to parse %s: %v", keys.Type(), did, err)
+		}
+		if keyType != keys.Type() || publicKey != identity.PublicKey {
+			t.Errorf("%s: expected %s, got %s %s", keys.Type(), identity.PublicKey, keyType, publicKey)
+		}
+
+		// Identities built on each key type verify
+		provider := NewDIDProvider(keys)
+		didIdentity, err := provider.CreateIdentity("did-id")
+		if err != nil {
+			t.Fatalf("%s: failed to create DID identity: %v", keys.Type(), err)
+		}
+		if valid, err := provider.VerifyIdentity(didIdentity); err != nil || !valid {
+			t.Errorf("%s: expected DID identity to verify, got %v (%v)", keys.Type(), valid, err)
+		}
+	}
+
+	if _, _, err := ParseDIDKey("did:web:example.com"); err == nil {
+		t.Error("Expected an error for a non did:key identifier")
+	}
+}
@@ -0,0 +1,126 @@
+package providers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"sync"
+)
+
+// Ed25519Provider is a provider using Ed25519 keys. Private keys are kept as
+// hex-encoded seeds in the given storage.
+type Ed25519Provider struct {
+	storage storage.Storage
+	mu      sync.Mutex
+}
+
+// NewEd25519Provider creates a new Ed25519Provider storing keys in storageBackend.
+// A nil storage defaults to memory storage.
+func NewEd25519Provider(storageBackend storage.Storage) *Ed25519Provider {
+	if storageBackend == nil {
+		storageBackend = storage.NewMemoryStorage()
+	}
+	return &Ed25519Provider{storage: storageBackend}
+}
+
+func (p *Ed25519Provider) Type() string {
+	return "ed25519"
+}
+
+// key returns the private key for id, generating and storing one if needed.
+func (p *Ed25519Provider) key(id string, create bool) (ed25519.PrivateKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if seedHex, err := p.storage.Get("ed25519_" + id); err == nil {
+		seed, err := hex.DecodeString(string(seedHex))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, errors.New("invalid stored ed25519 key")
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !create {
+		return nil, errors.New("key not found")
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.storage.Put("ed25519_"+id, []byte(hex.EncodeToString(privateKey.Seed()))); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// CreateIdentity generates a new identity, signing the ID and public key.
+func (p *Ed25519Provider) CreateIdentity(id string) (*identitytypes.Identity, error) {
+	privateKey, err := p.key(id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the identity instance
+	identity := &identitytypes.Identity{
+		ID:         id,
+		PublicKey:  hex.EncodeToString(privateKey.Public().(ed25519.PublicKey)),
+		Signatures: make(map[string]string),
+		Type:       p.Type(),
+	}
+
+	// Sign the canonical ID and public key payloads
+	identity.Signatures[identitytypes.SignatureID] = hex.EncodeToString(ed25519.Sign(privateKey, identitytypes.IDSigningPayload(identity)))
+	identity.Signatures[identitytypes.SignaturePublicKey] = hex.EncodeToString(ed25519.Sign(privateKey, identitytypes.PublicKeySigningPayload(identity)))
+
+	// Encode identity to generate hash and bytes representation
+	hash, bytes, err := identitytypes.EncodeIdentity(*identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Hash = hash
+	identity.Bytes = bytes
+
+	return identity, nil
+}
+
+// VerifyIdentity checks that the identity has all required fields and that
+// both of its signatures are valid.
+func (p *Ed25519Provider) VerifyIdentity(identity *identitytypes.Identity) (bool, error) {
+	if !identitytypes.IsIdentity(identity) {
+		return false, errors.New("identity is missing required fields")
+	}
+
+	if ok, err := p.Verify(identity.Signatures[identitytypes.SignatureID], identity.PublicKey, identitytypes.IDSigningPayload(identity)); err != nil || !ok {
+		return false, errors.New("invalid ID signature")
+	}
+	if ok, err := p.Verify(identity.Signatures[identitytypes.SignaturePublicKey], identity.PublicKey, identitytypes.PublicKeySigningPayload(identity)); err != nil || !ok {
+		return false, errors.New("invalid public key signature")
+	}
+
+	return true, nil
+}
+
+// Sign signs data with the key stored for id.
+func (p *Ed25519Provider) Sign(id string, data []byte) (string, error) {
+	privateKey, err := p.key(id, false)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(ed25519.Sign(privateKey, data)), nil
+}
+
+// Verify checks a hex signature over data against a hex-encoded public key.
+func (p *Ed25519Provider) Verify(signature string, publicKey string, data []byte) (bool, error) {
+	pubKey, err := hex.DecodeString(publicKey)
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return false, errors.New("invalid public key encoding")
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false, errors.New("invalid signature encoding")
+	}
+	return ed25519.Verify(pubKey, data, sig), nil
+}
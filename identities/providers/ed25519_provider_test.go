@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+func TestEd25519ProviderType(t *testing.T) {
+	provider := NewEd25519Provider(nil)
+	if provider.Type() != "ed25519" {
+		t.Fatalf("Expected provider type 'ed25519', got %s", provider.Type())
+	}
+}
+
+func TestEd25519CreateAndVerifyIdentity(t *testing.T) {
+	provider := NewEd25519Provider(storage.NewMemoryStorage())
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.Type != "ed25519" || identity.Hash == "" || len(identity.PublicKey) != 64 {
+		t.Fatalf("Unexpected identity %+v", identity)
+	}
+
+	valid, err := provider.VerifyIdentity(identity)
+	if err != nil || !valid {
+		t.Fatalf("Expected identity to verify, got %v (%v)", valid, err)
+	}
+
+	// Tampering with the ID invalidates the ID signature
+	tampered := *identity
+	tampered.ID = "other-id"
+	if valid, _ := provider.VerifyIdentity(&tampered); valid {
+		t.Fatal("Expected tampered identity to fail verification")
+	}
+}
+
+func TestEd25519SignAndVerify(t *testing.T) {
+	provider := NewEd25519Provider(storage.NewMemoryStorage())
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := []byte("test data")
+	signature, err := provider.Sign(identity.ID, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing data, got %v", err)
+	}
+
+	valid, err := provider.Verify(signature, identity.PublicKey, data)
+	if err != nil || !valid {
+		t.Fatalf("Expected signature to verify, got %v (%v)", valid, err)
+	}
+
+	valid, err = provider.Verify(signature, identity.PublicKey, []byte("tampered data"))
+	if err != nil || valid {
+		t.Fatalf("Expected tampered payload to fail verification, got %v (%v)", valid, err)
+	}
+
+	if _, err := provider.Verify("zz", identity.PublicKey, data); err == nil {
+		t.Fatal("Expected error for a malformed signature")
+	}
+
+	if _, err := provider.Sign("unknown-id", data); err == nil {
+		t.Fatal("Expected error signing with an unknown id")
+	}
+}
+
+func TestEd25519ReusesStoredKey(t *testing.T) {
+	store := storage.NewMemoryStorage()
+
+	first, err := NewEd25519Provider(store).CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := NewEd25519Provider(store).CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first.PublicKey != second.PublicKey {
+		t.Fatalf("Expected persisted key to be reused, got %s and %s", first.PublicKey, second.PublicKey)
+	}
+	if !identitytypes.IsEqual(first, second) {
+		t.Fatal("Expected identities created from the same key to be equal")
+	}
+}
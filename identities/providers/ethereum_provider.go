@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"encoding/hex"
+	"errors"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// EthereumProvider is a provider using secp256k1 keys, as Ethereum wallets
+// do. The identity ID is the EIP-55 checksummed address of the key, and
+// signatures follow personal_sign (EIP-191) so external tools can verify
+// them. Private keys are kept hex-encoded in the given storage, under both
+// the requested id and the derived address.
+type EthereumProvider struct {
+	storage storage.Storage
+	mu      sync.Mutex
+}
+
+// NewEthereumProvider creates a new EthereumProvider storing keys in
+// storageBackend. A nil storage defaults to memory storage.
+func NewEthereumProvider(storageBackend storage.Storage) *EthereumProvider {
+	if storageBackend == nil {
+		storageBackend = storage.NewMemoryStorage()
+	}
+	return &EthereumProvider{storage: storageBackend}
+}
+
+func (p *EthereumProvider) Type() string {
+	return "ethereum"
+}
+
+// key returns the private key for id, generating and storing one if needed.
+func (p *EthereumProvider) key(id string, create bool) (*secp256k1.PrivateKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if keyHex, err := p.storage.Get("ethereum_" + id); err == nil {
+		keyBytes, err := hex.DecodeString(strings.TrimPrefix(string(keyHex), "0x"))
+		if err != nil || len(keyBytes) != secp256k1.PrivKeyBytesLen {
+			return nil, errors.New("invalid stored ethereum key")
+		}
+		return secp256k1.PrivKeyFromBytes(keyBytes), nil
+	}
+	if !create {
+		return nil, errors.New("key not found")
+	}
+
+	privateKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.storage.Put("ethereum_"+id, []byte(hex.EncodeToString(privateKey.Serialize()))); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// CreateIdentity generates a new identity whose ID is the key's address,
+// signing the ID and public key.
+func (p *EthereumProvider) CreateIdentity(id string) (*identitytypes.Identity, error) {
+	privateKey, err := p.key(id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the key reachable by address as well, for Sign(identity.ID, ...)
+	address := EthereumAddress(privateKey.PubKey())
+	if err := p.storage.Put("ethereum_"+address, []byte(hex.EncodeToString(privateKey.Serialize()))); err != nil {
+		return nil, err
+	}
+
+	// Create the identity instance
+	identity := &identitytypes.Identity{
+		ID:         address,
+		PublicKey:  hex.EncodeToString(privateKey.PubKey().SerializeUncompressed()[1:]),
+		Signatures: make(map[string]string),
+		Type:       p.Type(),
+	}
+
+	// Sign the canonical ID and public key payloads
+	identity.Signatures[identitytypes.SignatureID] = signEthereum(privateKey, identitytypes.IDSigningPayload(identity))
+	identity.Signatures[identitytypes.SignaturePublicKey] = signEthereum(privateKey, identitytypes.PublicKeySigningPayload(identity))
+
+	// Encode identity to generate hash and bytes representation
+	hash, bytes, err := identitytypes.EncodeIdentity(*identity)
+	if err != nil {
+		return nil, err
+	}
+	identity.Hash = hash
+	identity.Bytes = bytes
+
+	return identity, nil
+}
+
+// VerifyIdentity checks that the identity has all required fields, that its
+// public key belongs to its address, and that both signatures recover to
+// that address.
+func (p *EthereumProvider) VerifyIdentity(identity *identitytypes.Identity) (bool, error) {
+	if !identitytypes.IsIdentity(identity) {
+		return false, errors.New("identity is missing required fields")
+	}
+
+	publicKey, err := parseEthereumPublicKey(identity.PublicKey)
+	if err != nil {
+		return false, err
+	}
+	if !strings.EqualFold(EthereumAddress(publicKey), identity.ID) {
+		return false, errors.New("public key does not match identity address")
+	}
+
+	if address, err := RecoverEthereumAddress(identity.Signatures[identitytypes.SignatureID], identitytypes.IDSigningPayload(identity)); err != nil || !strings.EqualFold(address, identity.ID) {
+		return false, errors.New("invalid ID signature")
+	}
+	if address, err := RecoverEthereumAddress(identity.Signatures[identitytypes.SignaturePublicKey], identitytypes.PublicKeySigningPayload(identity)); err != nil || !strings.EqualFold(address, identity.ID) {
+		return false, errors.New("invalid public key signature")
+	}
+
+	return true, nil
+}
+
+// Sign signs data with the key stored for id, which may be the id passed to
+// CreateIdentity or the identity's address.
+func (p *EthereumProvider) Sign(id string, data []byte) (string, error) {
+	privateKey, err := p.key(id, false)
+	if err != nil {
+		return "", err
+	}
+	return signEthereum(privateKey, data), nil
+}
+
+// Verify checks a personal_sign signature over data against a hex-encoded
+// uncompressed public key by recovering the signer's address.
+func (p *EthereumProvider) Verify(signature string, publicKey string, data []byte) (bool, error) {
+	return VerifyEthereumSignature(publicKey, data, signature)
+}
+
+// VerifyEthereumSignature reports whether signature over data recovers to
+// the address of the hex-encoded uncompressed public key.
+func VerifyEthereumSignature(publicKey string, data []byte, signature string) (bool, error) {
+	pubKey, err := parseEthereumPublicKey(publicKey)
+	if err != nil {
+		return false, err
+	}
+	address, err := RecoverEthereumAddress(signature, data)
+	if err != nil {
+		return false, err
+	}
+	return address == EthereumAddress(pubKey), nil
+}
+
+// EthereumAddress returns the EIP-55 checksummed address of a public key:
+// the last 20 bytes of the Keccak-256 of its uncompressed coordinates.
+func EthereumAddress(publicKey *secp256k1.PublicKey) string {
+	hash := keccak256(publicKey.SerializeUncompressed()[1:])
+	return checksumAddress(hex.EncodeToString(hash[12:]))
+}
+
+// RecoverEthereumAddress recovers the address that produced a hex
+// r||s||v personal_sign signature over data.
+func RecoverEthereumAddress(signature string, data []byte) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", errors.New("invalid signature encoding")
+	}
+
+	// Wallets emit v as 27/28, some tools as 0/1
+	v := sig[64]
+	if v < 27 {
+		v += 27
+	}
+	if v != 27 && v != 28 {
+		return "", errors.New("invalid signature recovery id")
+	}
+
+	// The compact format expected by RecoverCompact is v||r||s
+	compact := make([]byte, 65)
+	compact[0] = v
+	copy(compact[1:], sig[:64])
+
+	publicKey, _, err := ecdsa.RecoverCompact(compact, personalMessageHash(data))
+	if err != nil {
+		return "", err
+	}
+	return EthereumAddress(publicKey), nil
+}
+
+// signEthereum produces a hex r||s||v personal_sign signature over data.
+func signEthereum(privateKey *secp256k1.PrivateKey, data []byte) string {
+	compact := ecdsa.SignCompact(privateKey, personalMessageHash(data), false)
+
+	// Reorder v||r||s into the r||s||v layout used by Ethereum
+	sig := make([]byte, 65)
+	copy(sig, compact[1:])
+	sig[64] = compact[0]
+	return hex.EncodeToString(sig)
+}
+
+// personalMessageHash is the EIP-191 hash signed by personal_sign.
+func personalMessageHash(data []byte) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(data))
+	return keccak256([]byte(prefix), data)
+}
+
+// parseEthereumPublicKey parses 64 bytes of hex-encoded X and Y coordinates.
+func parseEthereumPublicKey(publicKey string) (*secp256k1.PublicKey, error) {
+	keyBytes, err := hex.DecodeString(publicKey)
+	if err != nil || len(keyBytes) != 64 {
+		return nil, errors.New("invalid public key encoding")
+	}
+	return secp256k1.ParsePubKey(append([]byte{0x04}, keyBytes...))
+}
+
+// checksumAddress applies the EIP-55 mixed-case checksum to a lowercase hex address.
+func checksumAddress(address string) string {
+	hash := hex.EncodeToString(keccak256([]byte(address)))
+
+	checksummed := []byte(address)
+	for i, c := range checksummed {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed)
+}
+
+func keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		hash.Write(d)
+	}
+	return hash.Sum(nil)
+}
@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"strings"
+	"testing"
+)
+
+// Test vector from the web3.js documentation for web3.eth.accounts.sign.
+const (
+	ethereumTestKey       = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	ethereumTestAddress   = "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+	ethereumTestMessage   = "Some data"
+	ethereumTestSignature = "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+)
+
+// setupEthereumTestVector returns a provider holding the test vector key under "test-id".
+func setupEthereumTestVector(t *testing.T) *EthereumProvider {
+	keys := storage.NewMemoryStorage()
+	if err := keys.Put("ethereum_test-id", []byte(ethereumTestKey)); err != nil {
+		t.Fatalf("Failed to store key: %v", err)
+	}
+	return NewEthereumProvider(keys)
+}
+
+func TestEthereumProviderType(t *testing.T) {
+	provider := NewEthereumProvider(nil)
+	if provider.Type() != "ethereum" {
+		t.Fatalf("Expected provider type 'ethereum', got %s", provider.Type())
+	}
+}
+
+func TestEthereumTestVector(t *testing.T) {
+	provider := setupEthereumTestVector(t)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.ID != ethereumTestAddress {
+		t.Fatalf("Expected address %s, got %s", ethereumTestAddress, identity.ID)
+	}
+
+	signature, err := provider.Sign("test-id", []byte(ethereumTestMessage))
+	if err != nil {
+		t.Fatalf("Failed to sign message: %v", err)
+	}
+	if signature != ethereumTestSignature {
+		t.Fatalf("Expected signature %s, got %s", ethereumTestSignature, signature)
+	}
+
+	// The key is also reachable by address
+	if byAddress, err := provider.Sign(identity.ID, []byte(ethereumTestMessage)); err != nil || byAddress != signature {
+		t.Errorf("Expected signing by address to match, got %s (%v)", byAddress, err)
+	}
+
+	address, err := RecoverEthereumAddress("0x"+ethereumTestSignature, []byte(ethereumTestMessage))
+	if err != nil || address != ethereumTestAddress {
+		t.Fatalf("Expected to recover %s, got %s (%v)", ethereumTestAddress, address, err)
+	}
+
+	ok, err := provider.Verify(ethereumTestSignature, identity.PublicKey, []byte(ethereumTestMessage))
+	if err != nil || !ok {
+		t.Fatalf("Expected test vector signature to verify, got %v (%v)", ok, err)
+	}
+	if ok, _ := provider.Verify(ethereumTestSignature, identity.PublicKey, []byte("other data")); ok {
+		t.Error("Expected signature over other data to fail")
+	}
+}
+
+func TestEthereumCreateAndVerifyIdentity(t *testing.T) {
+	provider := NewEthereumProvider(storage.NewMemoryStorage())
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity.Type != "ethereum" || identity.Hash == "" || len(identity.PublicKey) != 128 || !strings.HasPrefix(identity.ID, "0x") {
+		t.Fatalf("Unexpected identity %+v", identity)
+	}
+
+	valid, err := provider.VerifyIdentity(identity)
+	if err != nil || !valid {
+		t.Fatalf("Expected identity to verify, got %v (%v)", valid, err)
+	}
+
+	// A signature from another key recovers to a different address
+	other, err := provider.CreateIdentity("other-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tampered := *identity
+	tampered.Signatures = map[string]string{
+		identitytypes.SignatureID:        other.Signatures[identitytypes.SignatureID],
+		identitytypes.SignaturePublicKey: identity.Signatures[identitytypes.SignaturePublicKey],
+	}
+	if valid, _ := provider.VerifyIdentity(&tampered); valid {
+		t.Error("Expected identity with a foreign ID signature to fail verification")
+	}
+
+	// The public key must belong to the address
+	mismatched := *identity
+	mismatched.PublicKey = other.PublicKey
+	if valid, _ := provider.VerifyIdentity(&mismatched); valid {
+		t.Error("Expected identity with another public key to fail verification")
+	}
+}
@@ -1,13 +1,10 @@
 package providers
 
 import (
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"encoding/hex"
 	"errors"
-	"math/big"
 	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/storage"
 )
 
 // PublicKeyProvider is a provider using public key-based identities and a KeyStore.
@@ -16,7 +13,12 @@ type PublicKeyProvider struct {
 }
 
 // NewPublicKeyProvider creates a new PublicKeyProvider with a KeyStore.
+// A nil keystore defaults to an in-memory one; pass a persistent keystore
+// such as keystore.NewFSKeystore to reuse keys across restarts.
 func NewPublicKeyProvider(ks *keystore.KeyStore) *PublicKeyProvider {
+	if ks == nil {
+		ks = keystore.NewKeyStore(storage.NewMemoryStorage())
+	}
 	return &PublicKeyProvider{keystore: ks}
 }
 
@@ -41,29 +43,29 @@ func (p *PublicKeyProvider) CreateIdentity(id string) (*identitytypes.Identity,
 	}
 
 	// Generate the public key as a hex-encoded string
-	publicKey := hex.EncodeToString(append(privateKey.PublicKey.X.Bytes(), privateKey.PublicKey.Y.Bytes()...))
+	publicKey := keystore.PublicKeyToHex(&privateKey.PublicKey)
+
+	// Create the identity instance
+	identity := &identitytypes.Identity{
+		ID:         id,
+		PublicKey:  publicKey,
+		Signatures: make(map[string]string),
+		Type:       p.Type(),
+	}
 
-	// Sign the ID and public key
-	idSignature, err := p.keystore.SignMessage(id, []byte(id))
+	// Sign the canonical ID and public key payloads
+	idSignature, err := p.keystore.SignMessage(id, identitytypes.IDSigningPayload(identity))
 	if err != nil {
 		return nil, err
 	}
 
-	publicKeySignature, err := p.keystore.SignMessage(id, []byte(publicKey))
+	publicKeySignature, err := p.keystore.SignMessage(id, identitytypes.PublicKeySigningPayload(identity))
 	if err != nil {
 		return nil, err
 	}
 
-	// Create the identity instance
-	identity := &identitytypes.Identity{
-		ID:        id,
-		PublicKey: publicKey,
-		Signatures: map[string]string{
-			"id":        idSignature,
-			"publicKey": publicKeySignature,
-		},
-		Type: p.Type(),
-	}
+	identity.Signatures[identitytypes.SignatureID] = idSignature
+	identity.Signatures[identitytypes.SignaturePublicKey] = publicKeySignature
 
 	// Encode identity to generate hash and bytes representation
 	hash, bytes, err := identitytypes.EncodeIdentity(*identity)
@@ -84,30 +86,37 @@ func (p *PublicKeyProvider) VerifyIdentity(identity *identitytypes.Identity) (bo
 		return false, errors.New("identity is missing required fields")
 	}
 
-	// Decode the public key from the hex-encoded string
-	publicKeyBytes, err := hex.DecodeString(identity.PublicKey)
-	if err != nil || len(publicKeyBytes) < 64 {
+	// Decode the public key, which must be exactly 64 bytes of X and Y
+	pubKey, err := keystore.ReconstructPublicKeyFromHex(identity.PublicKey)
+	if err != nil {
 		return false, errors.New("invalid public key encoding")
 	}
 
-	// Reconstruct the ecdsa.PublicKey
-	pubKey := ecdsa.PublicKey{
-		Curve: elliptic.P256(),
-		X:     new(big.Int).SetBytes(publicKeyBytes[:len(publicKeyBytes)/2]),
-		Y:     new(big.Int).SetBytes(publicKeyBytes[len(publicKeyBytes)/2:]),
-	}
-
 	// Verify the ID signature using the KeyStore's VerifyMessage method
-	idVerified, err := p.keystore.VerifyMessage(pubKey, []byte(identity.ID), identity.Signatures["id"])
+	idVerified, err := p.keystore.VerifyMessage(*pubKey, identitytypes.IDSigningPayload(identity), identity.Signatures[identitytypes.SignatureID])
 	if err != nil || !idVerified {
 		return false, errors.New("invalid ID signature")
 	}
 
 	// Verify the public key signature using the KeyStore's VerifyMessage method
-	publicKeyVerified, err := p.keystore.VerifyMessage(pubKey, []byte(identity.PublicKey), identity.Signatures["publicKey"])
+	publicKeyVerified, err := p.keystore.VerifyMessage(*pubKey, identitytypes.PublicKeySigningPayload(identity), identity.Signatures[identitytypes.SignaturePublicKey])
 	if err != nil || !publicKeyVerified {
 		return false, errors.New("invalid public key signature")
 	}
 
 	return true, nil
 }
+
+// Sign signs data with the key stored for id.
+func (p *PublicKeyProvider) Sign(id string, data []byte) (string, error) {
+	return p.keystore.SignMessage(id, data)
+}
+
+// Verify checks a signature over data against a hex-encoded public key.
+func (p *PublicKeyProvider) Verify(signature string, publicKey string, data []byte) (bool, error) {
+	pubKey, err := keystore.ReconstructPublicKeyFromHex(publicKey)
+	if err != nil {
+		return false, err
+	}
+	return p.keystore.VerifyMessage(*pubKey, data, signature)
+}
@@ -1,13 +1,20 @@
 package providers
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/hex"
 	"math/big"
+	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/keystore"
 	"orbitdb/go-orbitdb/storage"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
 )
 
 func setupKeyStore() *keystore.KeyStore {
@@ -100,3 +107,213 @@ func TestVerifyIdentity(t *testing.T) {
 		t.Fatal("Expected VerifyIdentity to return false for a tampered identity")
 	}
 }
+
+func TestVerifyIdentityFlippedPublicKeyByte(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	publicKey, err := hex.DecodeString(identity.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to decode public key: %v", err)
+	}
+	publicKey[len(publicKey)-1] ^= 0x01
+	identity.PublicKey = hex.EncodeToString(publicKey)
+
+	valid, err := provider.VerifyIdentity(identity)
+	if valid || err == nil {
+		t.Fatal("Expected VerifyIdentity to return false for a flipped public key byte")
+	}
+}
+
+func TestVerifyIdentityPaddedPublicKey(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Zero-padding X and Y keeps the same key; even re-signed by its owner the
+	// padded encoding must not verify as a second identity
+	identity.PublicKey = "00" + identity.PublicKey[:64] + "00" + identity.PublicKey[64:]
+	if identity.Signatures[identitytypes.SignaturePublicKey], err = ks.SignMessage("test-id", identitytypes.PublicKeySigningPayload(identity)); err != nil {
+		t.Fatalf("Failed to sign padded public key: %v", err)
+	}
+
+	valid, err := provider.VerifyIdentity(identity)
+	if valid || err == nil {
+		t.Fatal("Expected VerifyIdentity to reject a zero-padded public key")
+	}
+}
+
+func TestCanonicalSigningPayloads(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	privateKey, err := ks.GetKey("test-id")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+
+	// Both signatures must cover exactly the documented payloads
+	payloads := map[string][]byte{
+		identitytypes.SignatureID:        identitytypes.IDSigningPayload(identity),
+		identitytypes.SignaturePublicKey: identitytypes.PublicKeySigningPayload(identity),
+	}
+	for key, payload := range payloads {
+		valid, err := ks.VerifyMessage(privateKey.PublicKey, payload, identity.Signatures[key])
+		if err != nil || !valid {
+			t.Fatalf("Expected %q signature to verify over its canonical payload", key)
+		}
+	}
+
+	// A decoded copy of a fresh identity verifies with the same provider
+	decoded, err := identitytypes.DecodeIdentity(identity.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to decode identity: %v", err)
+	}
+	if decoded.Hash != identity.Hash {
+		t.Fatalf("Expected decoded hash %s, got %s", identity.Hash, decoded.Hash)
+	}
+	valid, err := provider.VerifyIdentity(decoded)
+	if err != nil || !valid {
+		t.Fatalf("Expected decoded identity to verify, got %v", err)
+	}
+}
+
+func TestEncodedIdentityExcludesPrivateKey(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	privateKey, err := ks.GetKey("test-id")
+	if err != nil {
+		t.Fatalf("Failed to get key: %v", err)
+	}
+
+	// Neither the raw nor the hex-encoded private scalar may appear in the encoding
+	d := privateKey.D.FillBytes(make([]byte, 32))
+	if bytes.Contains(identity.Bytes, d) || bytes.Contains(identity.Bytes, []byte(hex.EncodeToString(d))) {
+		t.Fatal("Encoded identity contains private key material")
+	}
+
+	// The encoding holds exactly the public fields
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := dagcbor.Decode(nb, bytes.NewReader(identity.Bytes)); err != nil {
+		t.Fatalf("Failed to decode identity bytes: %v", err)
+	}
+	var fields []string
+	iter := nb.Build().MapIterator()
+	for !iter.Done() {
+		key, _, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Failed to iterate identity fields: %v", err)
+		}
+		name, _ := key.AsString()
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	if strings.Join(fields, ",") != "id,publicKey,signatures,type" {
+		t.Fatalf("Unexpected identity fields: %v", fields)
+	}
+}
+
+func TestCreateIdentityUniqueKeys(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	alice, err := provider.CreateIdentity("alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bob, err := provider.CreateIdentity("bob")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if alice.PublicKey == bob.PublicKey {
+		t.Fatal("Expected identities with different IDs to have different public keys")
+	}
+
+	// Neither identity's signatures verify under the other's key
+	impostor := *bob
+	impostor.Signatures = alice.Signatures
+	if valid, _ := provider.VerifyIdentity(&impostor); valid {
+		t.Fatal("Expected signatures from one identity to be rejected for another")
+	}
+}
+
+func TestCreateIdentityReusesPersistedKey(t *testing.T) {
+	dir := t.TempDir()
+
+	ks1, err := keystore.NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	first, err := NewPublicKeyProvider(ks1).CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A provider over a fresh keystore on the same path reuses the key
+	ks2, err := keystore.NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	provider := NewPublicKeyProvider(ks2)
+	second, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first.PublicKey != second.PublicKey {
+		t.Fatal("Expected the persisted key to be reused")
+	}
+	if valid, err := provider.VerifyIdentity(first); err != nil || !valid {
+		t.Fatalf("Expected identity from the first process to verify, got %v", err)
+	}
+}
+
+func TestVerifyIdentityAfterJSONRoundTrip(t *testing.T) {
+	ks := setupKeyStore()
+	provider := NewPublicKeyProvider(ks)
+
+	identity, err := provider.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, err := identity.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal identity: %v", err)
+	}
+	if strings.Contains(string(data), "private") {
+		t.Fatalf("Expected JSON to carry no private key material, got %s", data)
+	}
+
+	decoded, err := identitytypes.UnmarshalIdentityJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal identity: %v", err)
+	}
+
+	// Verify with a provider that has never seen the identity's key
+	verified, err := NewPublicKeyProvider(setupKeyStore()).VerifyIdentity(decoded)
+	if err != nil || !verified {
+		t.Fatalf("Expected unmarshaled identity to verify, got %v", err)
+	}
+}
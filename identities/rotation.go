@@ -0,0 +1,136 @@
+package identities
+
+import (
+	"errors"
+	"fmt"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/keystore"
+)
+
+// KeyLink records that the holder of the From public key authorized the To
+// public key as its successor. Signature is made by From over To.
+type KeyLink struct {
+	From      string
+	To        string
+	Signature string
+}
+
+// RotateKey replaces the key of an existing identity with a new one and
+// returns the identity for the new key. The old key signs a KeyLink to the
+// new key, so entries signed before the rotation still verify through
+// VerifyChain.
+func (ids *Identities) RotateKey(id string) (*identitytypes.Identity, error) {
+	lock := ids.lockFor(id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	ids.mu.RLock()
+	current := ids.byID[id]
+	ids.mu.RUnlock()
+	if current == nil {
+		return nil, fmt.Errorf("no identity for id %s", id)
+	}
+
+	oldKey, err := ids.keystore.GetKey(id)
+	if err != nil {
+		return nil, err
+	}
+	newKey, err := ids.keystore.RotateKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	newPublicKey := keystore.PublicKeyToHex(&newKey.PublicKey)
+	signature, err := keystore.Sign(oldKey, []byte(newPublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := ids.provider.CreateIdentity(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ids.persist(identity); err != nil {
+		return nil, fmt.Errorf("failed to store identity: %w", err)
+	}
+
+	ids.mu.Lock()
+	ids.links[id] = append(ids.links[id], KeyLink{From: current.PublicKey, To: newPublicKey, Signature: signature})
+	ids.storage[identity.Hash] = identity
+	ids.byID[id] = identity
+	ids.mu.Unlock()
+	return identity, nil
+}
+
+// KeyHistory returns every public key the identity id has held, oldest first.
+func (ids *Identities) KeyHistory(id string) []string {
+	ids.mu.RLock()
+	defer ids.mu.RUnlock()
+
+	links := ids.links[id]
+	if len(links) == 0 {
+		if identity := ids.byID[id]; identity != nil {
+			return []string{identity.PublicKey}
+		}
+		return nil
+	}
+
+	keys := []string{links[0].From}
+	for _, link := range links {
+		keys = append(keys, link.To)
+	}
+	return keys
+}
+
+// VerifyChain verifies the identity and every key link from its original key
+// to its current one.
+func (ids *Identities) VerifyChain(identity *identitytypes.Identity) (bool, error) {
+	verified, err := ids.provider.VerifyIdentity(identity)
+	if err != nil || !verified {
+		return false, err
+	}
+
+	ids.mu.RLock()
+	links := append([]KeyLink(nil), ids.links[identity.ID]...)
+	ids.mu.RUnlock()
+
+	if len(links) == 0 {
+		return true, nil
+	}
+
+	for i, link := range links {
+		if i > 0 && links[i-1].To != link.From {
+			return false, fmt.Errorf("key link %d does not continue the chain", i)
+		}
+
+		fromKey, err := keystore.ReconstructPublicKeyFromHex(link.From)
+		if err != nil {
+			return false, fmt.Errorf("invalid key in link %d: %w", i, err)
+		}
+		valid, err := ids.keystore.VerifyMessage(*fromKey, []byte(link.To), link.Signature)
+		if err != nil || !valid {
+			return false, fmt.Errorf("invalid signature on key link %d", i)
+		}
+	}
+
+	if links[len(links)-1].To != identity.PublicKey {
+		return false, errors.New("key chain does not end at the identity's current key")
+	}
+	return true, nil
+}
+
+// VerifyKey reports whether publicKey is one of the identity's historically
+// valid keys, after verifying the identity's key chain.
+func (ids *Identities) VerifyKey(identity *identitytypes.Identity, publicKey string) (bool, error) {
+	valid, err := ids.VerifyChain(identity)
+	if err != nil || !valid {
+		return false, err
+	}
+
+	for _, key := range ids.KeyHistory(identity.ID) {
+		if key == publicKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
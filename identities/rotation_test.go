@@ -0,0 +1,72 @@
+package identities
+
+import (
+	"orbitdb/go-orbitdb/oplog"
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+func TestVerifyChainAcrossRotation(t *testing.T) {
+	// Initialize an LRUStorage backend for testing
+	lruStorage, err := storage.NewLRUStorage(100)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	identities, err := setupIdentities(lruStorage)
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	original, err := identities.CreateIdentity("alice")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	before, err := oplog.NewEntry(identities.keystore, original, "log", "before rotation", oplog.NewClock("alice", 1), nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating entry: %v", err)
+	}
+
+	rotated, err := identities.RotateKey("alice")
+	if err != nil {
+		t.Fatalf("Error rotating key: %v", err)
+	}
+	if rotated.PublicKey == original.PublicKey {
+		t.Fatal("Expected rotation to produce a new public key")
+	}
+	after, err := oplog.NewEntry(identities.keystore, rotated, "log", "after rotation", oplog.NewClock("alice", 2), nil, nil)
+	if err != nil {
+		t.Fatalf("Error creating entry: %v", err)
+	}
+
+	valid, err := identities.VerifyChain(rotated)
+	if err != nil || !valid {
+		t.Fatalf("Expected key chain to verify, got %v", err)
+	}
+
+	// Entries signed with either key verify through the chain
+	for _, entry := range []oplog.EncodedEntry{before, after} {
+		if !oplog.VerifyEntrySignature(identities.keystore, entry) {
+			t.Fatalf("Expected entry %q signature to verify", entry.Payload)
+		}
+		valid, err := identities.VerifyKey(rotated, entry.Key)
+		if err != nil || !valid {
+			t.Fatalf("Expected key of entry %q to be valid through the chain, got %v", entry.Payload, err)
+		}
+	}
+
+	// A key outside the chain is rejected
+	stranger, err := identities.CreateIdentity("mallory")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+	if valid, _ := identities.VerifyKey(rotated, stranger.PublicKey); valid {
+		t.Fatal("Expected a foreign key to be rejected")
+	}
+
+	// A forged link breaks the chain
+	identities.links["alice"][0].Signature = identities.links["alice"][0].Signature[2:] + "00"
+	if valid, _ := identities.VerifyChain(rotated); valid {
+		t.Fatal("Expected a forged key link to fail verification")
+	}
+}
@@ -0,0 +1,87 @@
+package identities
+
+import (
+	"errors"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// identityPrefix is the prefix of the storage keys identities are saved
+// under, followed by the identity hash.
+const identityPrefix = "identity_"
+
+// Serializer converts identities to and from the bytes kept in storage. The
+// identity hash is always computed over the canonical CBOR encoding, so the
+// storage format does not affect it.
+type Serializer interface {
+	Marshal(identity *identitytypes.Identity) ([]byte, error)
+	Unmarshal(data []byte) (*identitytypes.Identity, error)
+}
+
+// CBORSerializer stores identities in their canonical CBOR encoding. It is
+// the default.
+type CBORSerializer struct{}
+
+// Marshal implements Serializer.
+func (CBORSerializer) Marshal(identity *identitytypes.Identity) ([]byte, error) {
+	_, data, err := identitytypes.EncodeIdentity(*identity)
+	return data, err
+}
+
+// Unmarshal implements Serializer.
+func (CBORSerializer) Unmarshal(data []byte) (*identitytypes.Identity, error) {
+	return identitytypes.DecodeIdentity(data)
+}
+
+// JSONSerializer stores identities as JSON, for debugging or interop with
+// JavaScript implementations.
+type JSONSerializer struct{}
+
+// Marshal implements Serializer.
+func (JSONSerializer) Marshal(identity *identitytypes.Identity) ([]byte, error) {
+	if identity == nil {
+		return nil, errors.New("identity is required")
+	}
+	return identity.MarshalJSON()
+}
+
+// Unmarshal implements Serializer.
+func (JSONSerializer) Unmarshal(data []byte) (*identitytypes.Identity, error) {
+	return identitytypes.UnmarshalIdentityJSON(data)
+}
+
+// Option configures optional behaviour of Identities.
+type Option func(*Identities)
+
+// WithSerializer sets how identities are encoded in storage. The default is
+// CBORSerializer.
+func WithSerializer(serializer Serializer) Option {
+	return func(ids *Identities) {
+		ids.serializer = serializer
+	}
+}
+
+// persist saves an identity to the backing storage under its hash.
+func (ids *Identities) persist(identity *identitytypes.Identity) error {
+	data, err := ids.serializer.Marshal(identity)
+	if err != nil {
+		return err
+	}
+	return ids.backend.Put(identityPrefix+identity.Hash, data)
+}
+
+// load reads an identity saved by persist, checking it is stored under its
+// own hash.
+func (ids *Identities) load(hash string) (*identitytypes.Identity, error) {
+	data, err := ids.backend.Get(identityPrefix + hash)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := ids.serializer.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Hash != hash {
+		return nil, errors.New("stored identity does not match its hash")
+	}
+	return identity, nil
+}
@@ -0,0 +1,84 @@
+package identities
+
+import (
+	"encoding/json"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+func TestIdentitiesJSONSerializer(t *testing.T) {
+	backend := storage.NewMemoryStorage()
+	ids, err := NewIdentities("publickey", backend, WithSerializer(JSONSerializer{}))
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	identity, err := ids.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+
+	// The stored form is JSON, but the hash is still computed over CBOR
+	data, err := backend.Get(identityPrefix + identity.Hash)
+	if err != nil {
+		t.Fatalf("Expected identity to be stored: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatalf("Expected identity to be stored as JSON, got %q", data)
+	}
+	hash, _, err := identitytypes.EncodeIdentity(*identity)
+	if err != nil || hash != identity.Hash {
+		t.Fatalf("Expected hash %s to match the CBOR encoding, got %s (%v)", identity.Hash, hash, err)
+	}
+
+	// A second instance over the same storage reloads it
+	reopened, err := NewIdentities("publickey", backend, WithSerializer(JSONSerializer{}))
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+	loaded, err := reopened.GetIdentity(identity.Hash)
+	if err != nil {
+		t.Fatalf("Error loading identity: %v", err)
+	}
+	if loaded == nil || !identitytypes.IsEqual(loaded, identity) {
+		t.Fatalf("Expected reloaded identity to equal the original, got %+v", loaded)
+	}
+	if !reopened.VerifyIdentity(loaded) {
+		t.Error("Expected reloaded identity to verify")
+	}
+
+	if missing, err := reopened.GetIdentity("unknown"); err != nil || missing != nil {
+		t.Errorf("Expected no identity for an unknown hash, got %v (%v)", missing, err)
+	}
+}
+
+func TestIdentitiesCBORSerializerDefault(t *testing.T) {
+	backend := storage.NewMemoryStorage()
+	ids, err := NewIdentities("publickey", backend)
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+
+	identity, err := ids.CreateIdentity("test-id")
+	if err != nil {
+		t.Fatalf("Error creating identity: %v", err)
+	}
+
+	data, err := backend.Get(identityPrefix + identity.Hash)
+	if err != nil {
+		t.Fatalf("Expected identity to be stored: %v", err)
+	}
+	if string(data) != string(identity.Bytes) {
+		t.Error("Expected identity to be stored in its CBOR encoding")
+	}
+
+	// Mismatched serializers fail to load rather than return a wrong identity
+	reopened, err := NewIdentities("publickey", backend, WithSerializer(JSONSerializer{}))
+	if err != nil {
+		t.Fatalf("Error initializing identities: %v", err)
+	}
+	if _, err := reopened.GetIdentity(identity.Hash); err == nil {
+		t.Error("Expected loading CBOR with the JSON serializer to fail")
+	}
+}
@@ -0,0 +1,216 @@
+package keystore
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+const (
+	privateKeyPrefix  = "private_"
+	archivedKeyPrefix = "archived_"
+	archiveDir        = "archive"
+	pemExtension      = ".pem"
+	pemBlockType      = "EC PRIVATE KEY"
+)
+
+// NewFSKeystore creates a KeyStore that persists each private key as a
+// PEM-encoded EC private key file under path, creating the directory if it
+// does not exist. Keys survive process restarts and can be read by any
+// keystore opened on the same path.
+func NewFSKeystore(path string) (*KeyStore, error) {
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, fmt.Errorf("%w: failed to create keystore directory: %w", storage.ErrStorageUnavailable, err)
+	}
+	return NewKeyStore(&pemFileStorage{dir: path}), nil
+}
+
+// pemFileStorage stores serialized private keys as one PEM file per key ID.
+// Archived keys are kept in the archive subdirectory and are not listed by
+// Iterator.
+type pemFileStorage struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// fileFor maps a storage key to its PEM file.
+func (s *pemFileStorage) fileFor(key string) (string, error) {
+	if name, ok := strings.CutPrefix(key, archivedKeyPrefix); ok {
+		return filepath.Join(s.dir, archiveDir, url.PathEscape(name)+pemExtension), nil
+	}
+	id, ok := strings.CutPrefix(key, privateKeyPrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported key %q", key)
+	}
+	return filepath.Join(s.dir, url.PathEscape(id)+pemExtension), nil
+}
+
+// Put converts a serialized private key to PEM and writes it to disk.
+func (s *pemFileStorage) Put(key string, value []byte) error {
+	file, err := s.fileFor(key)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := DeserializePrivateKey(value)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: pemBlockType, Bytes: der}), 0o600)
+}
+
+// Get reads a PEM file and returns the serialized private key.
+func (s *pemFileStorage) Get(key string) ([]byte, error) {
+	file, err := s.fileFor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	data, err := os.ReadFile(file)
+	s.mu.RUnlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemBlockType {
+		return nil, fmt.Errorf("invalid PEM key file %s", file)
+	}
+	privateKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM key file %s: %w", file, err)
+	}
+	return SerializePrivateKey(privateKey)
+}
+
+// Has reports whether a PEM file exists for the key.
+func (s *pemFileStorage) Has(key string) (bool, error) {
+	file, err := s.fileFor(key)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, err := os.Stat(file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes a key file.
+func (s *pemFileStorage) Delete(key string) error {
+	file, err := s.fileFor(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ids lists the key IDs with a file in the directory.
+func (s *pemFileStorage) ids() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*"+pemExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(files))
+	for _, file := range files {
+		id, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(file), pemExtension))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Iterator yields every stored key with its serialized private key.
+func (s *pemFileStorage) Iterator() (<-chan [2]string, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan [2]string)
+	go func() {
+		defer close(ch)
+		for _, id := range ids {
+			value, err := s.Get(privateKeyPrefix + id)
+			if err != nil {
+				continue
+			}
+			ch <- [2]string{privateKeyPrefix + id, string(value)}
+		}
+	}()
+	return ch, nil
+}
+
+// Merge copies all keys from another storage instance.
+func (s *pemFileStorage) Merge(other storage.Storage) error {
+	iter, err := other.Iterator()
+	if err != nil {
+		return err
+	}
+
+	for kv := range iter {
+		if err := s.Put(kv[0], []byte(kv[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear removes every key file, including archived keys.
+func (s *pemFileStorage) Clear() error {
+	ids, err := s.ids()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.Delete(privateKeyPrefix + id); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.RemoveAll(filepath.Join(s.dir, archiveDir))
+}
+
+// Close is a no-op; files are written synchronously.
+func (s *pemFileStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,115 @@
+package keystore
+
+import (
+	"encoding/pem"
+	"errors"
+	"orbitdb/go-orbitdb/storage"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSKeystorePersistence(t *testing.T) {
+	// The directory does not exist yet and must be created
+	dir := filepath.Join(t.TempDir(), "nested", "keys")
+	id := "test/id"
+
+	first, err := NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	if first.HasKey(id) {
+		t.Fatal("Expected HasKey to return false for nonexistent key")
+	}
+
+	created, err := first.CreateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error creating key, got %v", err)
+	}
+
+	// Keys are stored as PEM files
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected a single PEM file, got %v (%v)", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Failed to read key file: %v", err)
+	}
+	if block, _ := pem.Decode(data); block == nil || block.Type != "EC PRIVATE KEY" {
+		t.Fatal("Expected key file to hold a PEM EC private key")
+	}
+
+	// A second keystore on the same path sees the same key
+	second, err := NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	if !second.HasKey(id) {
+		t.Fatal("Expected key to be visible from a second keystore")
+	}
+	retrieved, err := second.GetKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error retrieving key, got %v", err)
+	}
+	if retrieved.D.Cmp(created.D) != 0 || retrieved.X.Cmp(created.X) != 0 {
+		t.Fatal("Expected retrieved key to match the created key")
+	}
+
+	if err := second.Clear(); err != nil {
+		t.Fatalf("Expected no error clearing keystore, got %v", err)
+	}
+	if first.HasKey(id) {
+		t.Fatal("Expected key to be removed after clearing")
+	}
+}
+
+func TestFSKeystoreUnavailable(t *testing.T) {
+	// A regular file cannot be used as the keystore directory
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if _, err := NewFSKeystore(file); !errors.Is(err, storage.ErrStorageUnavailable) {
+		t.Fatalf("Expected ErrStorageUnavailable, got %v", err)
+	}
+}
+
+func TestFSKeystoreArchivedKeys(t *testing.T) {
+	dir := t.TempDir()
+	id := "test/id"
+
+	first, err := NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	created, err := first.CreateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error creating key, got %v", err)
+	}
+	if _, err := first.RotateKey(id); err != nil {
+		t.Fatalf("Expected no error rotating key, got %v", err)
+	}
+
+	// Archived keys persist, but only current keys are listed
+	second, err := NewFSKeystore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create FS keystore: %v", err)
+	}
+	archived, err := second.GetArchivedKeys(id)
+	if err != nil || len(archived) != 1 || archived[0].D.Cmp(created.D) != 0 {
+		t.Fatalf("Expected the original key to be archived on disk, got %d keys (%v)", len(archived), err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected a single current key file, got %v (%v)", files, err)
+	}
+
+	if err := second.Clear(); err != nil {
+		t.Fatalf("Expected no error clearing keystore, got %v", err)
+	}
+	if archived, err := first.GetArchivedKeys(id); err != nil || len(archived) != 0 {
+		t.Fatalf("Expected archived keys to be removed after clearing, got %d (%v)", len(archived), err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"math/big"
 	"orbitdb/go-orbitdb/storage"
+	"strconv"
 	"sync"
 )
 
@@ -64,10 +65,110 @@ func (ks *KeyStore) CreateKey(id string) (*ecdsa.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// RotateKey replaces the key stored under an existing ID with a freshly
+// generated one and returns the new key. The replaced key is archived, so
+// signatures made before the rotation can still be checked against it; see
+// GetArchivedKeys.
+func (ks *KeyStore) RotateKey(id string) (*ecdsa.PrivateKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if !ks.HasKey(id) {
+		return nil, errors.New("key not found")
+	}
+	if err := ks.archive(id); err != nil {
+		return nil, err
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeyBytes, err := SerializePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ks.storage.Put("private_"+id, privateKeyBytes); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// RevokeKey archives the key stored under id and removes it, so it can no
+// longer sign. Signatures made before the revocation can still be checked
+// against the archived key.
+func (ks *KeyStore) RevokeKey(id string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if !ks.HasKey(id) {
+		return errors.New("key not found")
+	}
+	if err := ks.archive(id); err != nil {
+		return err
+	}
+	return ks.storage.Delete("private_" + id)
+}
+
+// GetArchivedKeys returns the keys previously stored under id that were
+// replaced by RotateKey or removed by RevokeKey, oldest first.
+func (ks *KeyStore) GetArchivedKeys(id string) ([]*ecdsa.PrivateKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	var keys []*ecdsa.PrivateKey
+	for n := 0; ; n++ {
+		privateKeyBytes, err := ks.storage.Get(archivedKey(id, n))
+		if errors.Is(err, storage.ErrNotFound) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := DeserializePrivateKey(privateKeyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archived key %d for %s: %w", n, id, err)
+		}
+		keys = append(keys, privateKey)
+	}
+}
+
+// archive copies the current key for id to the next free archive slot.
+// The caller must hold ks.mu.
+func (ks *KeyStore) archive(id string) error {
+	privateKeyBytes, err := ks.storage.Get("private_" + id)
+	if err != nil {
+		return errors.New("key not found")
+	}
+
+	n := 0
+	for {
+		ok, err := ks.storage.Has(archivedKey(id, n))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		n++
+	}
+	if err := ks.storage.Put(archivedKey(id, n), privateKeyBytes); err != nil {
+		return fmt.Errorf("failed to archive key: %w", err)
+	}
+	return nil
+}
+
+// archivedKey is the storage key of the n-th archived key for id.
+func archivedKey(id string, n int) string {
+	return "archived_" + id + "/" + strconv.Itoa(n)
+}
+
 // HasKey checks if a key exists for a given ID.
 func (ks *KeyStore) HasKey(id string) bool {
-	_, err := ks.storage.Get("private_" + id)
-	return err == nil
+	ok, err := ks.storage.Has("private_" + id)
+	return err == nil && ok
 }
 
 // AddKey adds a private key to the keystore (e.g., for imported keys).
@@ -117,31 +218,71 @@ func (ks *KeyStore) SignMessage(id string, data []byte) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return Sign(privateKey, data)
+}
 
+// Sign signs data with the given private key, returning the hex r||s signature
+// with s in its low (canonical) form.
+func Sign(privateKey *ecdsa.PrivateKey, data []byte) (string, error) {
 	hash := sha256.Sum256(data)
 	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
 	if err != nil {
 		return "", err
 	}
+	s = lowS(privateKey.Curve, s)
 
-	signature := append(r.Bytes(), s.Bytes()...)
+	// Encode r and s as fixed-width halves so VerifyMessage can split them
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
 	return hex.EncodeToString(signature), nil
 }
 
 // VerifyMessage verifies the signature against the data using the public key.
 func (ks *KeyStore) VerifyMessage(publicKey ecdsa.PublicKey, data []byte, signatureHex string) (bool, error) {
+	return Verify(publicKey, data, signatureHex)
+}
+
+// Verify verifies a hex r||s signature produced by Sign against the data.
+// Signatures with a high s value, or in any encoding other than the 64-byte
+// lowercase hex Sign emits, are rejected.
+func Verify(publicKey ecdsa.PublicKey, data []byte, signatureHex string) (bool, error) {
 	sigBytes, err := hex.DecodeString(signatureHex)
-	if err != nil || len(sigBytes) < 64 {
+	if err != nil {
 		return false, err
 	}
+	// Only the exact encoding Sign emits is accepted, so padding the halves
+	// or changing the hex case cannot produce a second valid signature
+	if len(sigBytes) != 64 || hex.EncodeToString(sigBytes) != signatureHex {
+		return false, nil
+	}
 
-	r := new(big.Int).SetBytes(sigBytes[:len(sigBytes)/2])
-	s := new(big.Int).SetBytes(sigBytes[len(sigBytes)/2:])
+	r := new(big.Int).SetBytes(sigBytes[:32])
+	s := new(big.Int).SetBytes(sigBytes[32:])
+	if !isLowS(publicKey.Curve, s) {
+		return false, nil
+	}
 
 	hash := sha256.Sum256(data)
 	return ecdsa.Verify(&publicKey, hash[:], r, s), nil
 }
 
+// lowS returns the canonical form of s. (r, s) and (r, n-s) are both valid
+// signatures for the same message; only the one with s <= n/2 is accepted,
+// so an entry cannot be re-signed into a different CID by a third party.
+func lowS(curve elliptic.Curve, s *big.Int) *big.Int {
+	if isLowS(curve, s) {
+		return s
+	}
+	return new(big.Int).Sub(curve.Params().N, s)
+}
+
+// isLowS reports whether s is at most half the curve order.
+func isLowS(curve elliptic.Curve, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+	return s.Cmp(halfOrder) <= 0
+}
+
 // SerializePrivateKey serializes an ECDSA private key to a JSON-encoded byte slice.
 func SerializePrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
 	data := PrivateKeyData{
@@ -183,6 +324,16 @@ func DeserializePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
 	}, nil
 }
 
+// PublicKeyToHex encodes the X and Y coordinates of a public key as 64 bytes of hex.
+func PublicKeyToHex(publicKey *ecdsa.PublicKey) string {
+	pubKeyBytes := make([]byte, 64)
+	publicKey.X.FillBytes(pubKeyBytes[:32])
+	publicKey.Y.FillBytes(pubKeyBytes[32:])
+	return hex.EncodeToString(pubKeyBytes)
+}
+
+// ReconstructPublicKeyFromHex decodes a P-256 public key encoded with
+// PublicKeyToHex.
 func ReconstructPublicKeyFromHex(pubKeyHex string) (*ecdsa.PublicKey, error) {
 	pubKeyBytes, err := hex.DecodeString(pubKeyHex)
 	if err != nil {
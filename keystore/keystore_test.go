@@ -4,8 +4,11 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"orbitdb/go-orbitdb/storage"
+	"strings"
 	"testing"
 )
 
@@ -204,6 +207,129 @@ func TestVerifyMessage(t *testing.T) {
 	}
 }
 
+func TestVerifyRejectsHighS(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	data := []byte("test-data")
+	halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+
+	// Signing is randomized; every signature must come out canonical
+	for i := 0; i < 20; i++ {
+		signature, err := Sign(privateKey, data)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		sigBytes, _ := hex.DecodeString(signature)
+		s := new(big.Int).SetBytes(sigBytes[32:])
+		if s.Cmp(halfOrder) > 0 {
+			t.Fatalf("Expected a low-s signature, got s=%x", s)
+		}
+
+		valid, err := Verify(privateKey.PublicKey, data, signature)
+		if err != nil || !valid {
+			t.Fatalf("Expected the canonical signature to verify, got %v, %v", valid, err)
+		}
+
+		// (r, n-s) is accepted by plain ECDSA but must be rejected here
+		highS := new(big.Int).Sub(elliptic.P256().Params().N, s)
+		highS.FillBytes(sigBytes[32:])
+		valid, err = Verify(privateKey.PublicKey, data, hex.EncodeToString(sigBytes))
+		if err != nil {
+			t.Fatalf("Expected no error for a high-s signature, got %v", err)
+		}
+		if valid {
+			t.Fatal("Expected the high-s signature to be rejected")
+		}
+	}
+}
+
+func TestVerifyRejectsNonCanonicalEncoding(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	data := []byte("test-data")
+
+	signature, err := Sign(privateKey, data)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if valid, err := Verify(privateKey.PublicKey, data, signature); err != nil || !valid {
+		t.Fatalf("Expected the signature to verify, got %v, %v", valid, err)
+	}
+
+	// Zero-padding both halves keeps r and s unchanged
+	padded := "00" + signature[:64] + "00" + signature[64:]
+	if valid, err := Verify(privateKey.PublicKey, data, padded); err != nil || valid {
+		t.Errorf("Expected a zero-padded signature to be rejected, got %v, %v", valid, err)
+	}
+
+	upper := strings.ToUpper(signature)
+	if upper == signature {
+		t.Fatal("Expected the signature to contain hex letters")
+	}
+	if valid, err := Verify(privateKey.PublicKey, data, upper); err != nil || valid {
+		t.Errorf("Expected an uppercased signature to be rejected, got %v, %v", valid, err)
+	}
+}
+
+func TestSignFixedWidth(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	data := []byte("test-data")
+
+	// Sign until r or s has a leading zero byte, which a variable-width
+	// encoding would drop
+	for i := 0; i < 5000; i++ {
+		signature, err := Sign(privateKey, data)
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		if len(signature) != 128 {
+			t.Fatalf("Expected a 64-byte hex signature, got %d characters", len(signature))
+		}
+		if signature[:2] != "00" && signature[64:66] != "00" {
+			continue
+		}
+		if valid, err := Verify(privateKey.PublicKey, data, signature); err != nil || !valid {
+			t.Fatalf("Expected a signature with a leading zero byte to verify, got %v, %v", valid, err)
+		}
+		return
+	}
+	t.Fatal("Expected a signature with a leading zero byte within 5000 attempts")
+}
+
+func TestPublicKeyToHex(t *testing.T) {
+	// Generate keys until X or Y has a leading zero byte
+	for i := 0; i < 5000; i++ {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		encoded := PublicKeyToHex(&privateKey.PublicKey)
+		if len(encoded) != 128 {
+			t.Fatalf("Expected a 64-byte hex public key, got %d characters", len(encoded))
+		}
+		if encoded[:2] != "00" && encoded[64:66] != "00" {
+			continue
+		}
+
+		decoded, err := ReconstructPublicKeyFromHex(encoded)
+		if err != nil {
+			t.Fatalf("Failed to reconstruct public key: %v", err)
+		}
+		if decoded.X.Cmp(privateKey.X) != 0 || decoded.Y.Cmp(privateKey.Y) != 0 {
+			t.Fatal("Expected the public key to round-trip")
+		}
+		return
+	}
+	t.Fatal("Expected a public key with a leading zero byte within 5000 attempts")
+}
+
 func TestSerializePrivateKey(t *testing.T) {
 	// Generate a test private key
 	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
@@ -302,3 +428,105 @@ func TestSerializeAndDeserializePrivateKey(t *testing.T) {
 		t.Error("Private scalar D mismatch after deserialization")
 	}
 }
+
+func TestRotateKey(t *testing.T) {
+	ks := newTestKeyStore(t)
+	id := "test-id"
+
+	// Rotating requires an existing key
+	if _, err := ks.RotateKey(id); err == nil {
+		t.Fatal("Expected error rotating a non-existent key, got nil")
+	}
+
+	oldKey, err := ks.CreateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	newKey, err := ks.RotateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error rotating key, got %v", err)
+	}
+	if newKey.D.Cmp(oldKey.D) == 0 {
+		t.Fatal("Expected rotated key to differ from the original")
+	}
+
+	// Messages are now signed with the new key
+	data := []byte("test-data")
+	signature, err := ks.SignMessage(id, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing message, got %v", err)
+	}
+	if valid, _ := ks.VerifyMessage(newKey.PublicKey, data, signature); !valid {
+		t.Fatal("Expected signature to verify with the rotated key")
+	}
+	if valid, _ := ks.VerifyMessage(oldKey.PublicKey, data, signature); valid {
+		t.Fatal("Expected signature not to verify with the original key")
+	}
+}
+
+func TestRotateKeyArchivesOldKey(t *testing.T) {
+	ks := newTestKeyStore(t)
+	id := "test-id"
+
+	oldKey, err := ks.CreateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data := []byte("signed before rotation")
+	signature, err := ks.SignMessage(id, data)
+	if err != nil {
+		t.Fatalf("Expected no error signing message, got %v", err)
+	}
+
+	newKey, err := ks.RotateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error rotating key, got %v", err)
+	}
+	current, err := ks.GetKey(id)
+	if err != nil || current.D.Cmp(newKey.D) != 0 {
+		t.Fatalf("Expected GetKey to return the rotated key, got %v", err)
+	}
+
+	archived, err := ks.GetArchivedKeys(id)
+	if err != nil {
+		t.Fatalf("Expected no error listing archived keys, got %v", err)
+	}
+	if len(archived) != 1 || archived[0].D.Cmp(oldKey.D) != 0 {
+		t.Fatalf("Expected the original key to be archived, got %d keys", len(archived))
+	}
+	if valid, _ := ks.VerifyMessage(archived[0].PublicKey, data, signature); !valid {
+		t.Fatal("Expected the earlier signature to verify against the archived key")
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	ks := newTestKeyStore(t)
+	id := "test-id"
+
+	if err := ks.RevokeKey(id); err == nil {
+		t.Fatal("Expected error revoking a non-existent key, got nil")
+	}
+
+	key, err := ks.CreateKey(id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := ks.RevokeKey(id); err != nil {
+		t.Fatalf("Expected no error revoking key, got %v", err)
+	}
+
+	if ks.HasKey(id) {
+		t.Fatal("Expected the revoked key to be removed")
+	}
+	if _, err := ks.SignMessage(id, []byte("test-data")); err == nil {
+		t.Fatal("Expected signing with a revoked key to fail")
+	}
+	archived, err := ks.GetArchivedKeys(id)
+	if err != nil || len(archived) != 1 || archived[0].D.Cmp(key.D) != 0 {
+		t.Fatalf("Expected the revoked key to be archived, got %d keys (%v)", len(archived), err)
+	}
+	if keys, err := ks.GetArchivedKeys("other-id"); err != nil || len(keys) != 0 {
+		t.Errorf("Expected no archived keys for an unknown id, got %d (%v)", len(keys), err)
+	}
+}
@@ -0,0 +1,204 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ipfs/go-cid"
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Manifest describes a database: its name, its type and the access
+// controller its log entries are checked against. Write is the write list
+// handed to that controller, for types such as "ipfs" that use one. Admin is
+// the public key of the identity allowed to publish manifest updates;
+// manifests without an admin cannot be updated.
+type Manifest struct {
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	AccessController string   `json:"accessController"`
+	Write            []string `json:"write,omitempty"`
+	Admin            string   `json:"admin,omitempty"`
+}
+
+// EncodeManifest encodes a manifest into CBOR and returns its hash and bytes.
+func EncodeManifest(m Manifest) (string, []byte, error) {
+	if m.Name == "" || m.Type == "" {
+		return "", nil, errors.New("manifest requires a name and a type")
+	}
+
+	nb := basicnode.Prototype__Map{}.NewBuilder()
+	if err := assembleManifest(nb, m); err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := dagcbor.Encode(nb.Build(), &buf); err != nil {
+		return "", nil, err
+	}
+
+	hash, err := hashBytes(buf.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	return hash, buf.Bytes(), nil
+}
+
+// CreateManifest encodes a new manifest and returns its CID and bytes. The
+// CID is the root of the database address.
+func CreateManifest(name, dbType, accessController string) (cid.Cid, []byte, error) {
+	_, data, err := EncodeManifest(Manifest{Name: name, Type: dbType, AccessController: accessController})
+	if err != nil {
+		return cid.Undef, nil, err
+	}
+	c, err := cidOf(data)
+	if err != nil {
+		return cid.Undef, nil, err
+	}
+	return c, data, nil
+}
+
+// ReadManifest decodes manifest bytes, as returned by CreateManifest.
+func ReadManifest(data []byte) (*Manifest, error) {
+	return DecodeManifest(data)
+}
+
+// assembleManifest writes the manifest fields as a map. The write and admin
+// fields are omitted when empty so manifests without them keep their hash.
+func assembleManifest(na datamodel.NodeAssembler, m Manifest) error {
+	fields := int64(3)
+	if len(m.Write) > 0 {
+		fields++
+	}
+	if m.Admin != "" {
+		fields++
+	}
+
+	ma, err := na.BeginMap(fields)
+	if err != nil {
+		return err
+	}
+	if err := assembleStringField(ma, "name", m.Name); err != nil {
+		return err
+	}
+	if err := assembleStringField(ma, "type", m.Type); err != nil {
+		return err
+	}
+	if err := assembleStringField(ma, "accessController", m.AccessController); err != nil {
+		return err
+	}
+	if len(m.Write) > 0 {
+		if err := ma.AssembleKey().AssignString("write"); err != nil {
+			return err
+		}
+		la, err := ma.AssembleValue().BeginList(int64(len(m.Write)))
+		if err != nil {
+			return err
+		}
+		for _, id := range m.Write {
+			if err := la.AssembleValue().AssignString(id); err != nil {
+				return err
+			}
+		}
+		if err := la.Finish(); err != nil {
+			return err
+		}
+	}
+	if m.Admin != "" {
+		if err := assembleStringField(ma, "admin", m.Admin); err != nil {
+			return err
+		}
+	}
+	return ma.Finish()
+}
+
+// hashBytes returns the base58btc CID of CBOR-encoded bytes.
+func hashBytes(data []byte) (string, error) {
+	c, err := cidOf(data)
+	if err != nil {
+		return "", err
+	}
+
+	// Encode CID to base58btc for hash string
+	return c.StringOfBase(multibase.Base58BTC)
+}
+
+// cidOf calculates the CID of CBOR-encoded bytes.
+func cidOf(data []byte) (cid.Cid, error) {
+	hash, err := mh.Sum(data, mh.SHA2_256, -1)
+	if err != nil {
+		return cid.Undef, err
+	}
+	return cid.NewCidV1(cid.DagCBOR, hash), nil
+}
+
+// DecodeManifest decodes CBOR-encoded bytes back into a Manifest.
+func DecodeManifest(data []byte) (*Manifest, error) {
+	if len(data) == 0 {
+		return nil, errors.New("invalid or empty input data")
+	}
+
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := dagcbor.Decode(nb, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return manifestFromNode(nb.Build())
+}
+
+// manifestFromNode reads the manifest fields from a decoded map node.
+func manifestFromNode(node datamodel.Node) (*Manifest, error) {
+	var m Manifest
+	var err error
+	if m.Name, err = getString(node, "name"); err != nil || m.Name == "" {
+		return nil, errors.New("invalid or missing 'name' field")
+	}
+	if m.Type, err = getString(node, "type"); err != nil || m.Type == "" {
+		return nil, errors.New("invalid or missing 'type' field")
+	}
+	if m.AccessController, err = getString(node, "accessController"); err != nil {
+		return nil, errors.New("invalid or missing 'accessController' field")
+	}
+	if writeNode, err := node.LookupByString("write"); err == nil {
+		it := writeNode.ListIterator()
+		if it == nil {
+			return nil, errors.New("invalid 'write' field")
+		}
+		for !it.Done() {
+			_, idNode, err := it.Next()
+			if err != nil {
+				return nil, errors.New("invalid 'write' field")
+			}
+			id, err := idNode.AsString()
+			if err != nil {
+				return nil, errors.New("invalid 'write' field")
+			}
+			m.Write = append(m.Write, id)
+		}
+	}
+	if adminNode, err := node.LookupByString("admin"); err == nil {
+		if m.Admin, err = adminNode.AsString(); err != nil {
+			return nil, errors.New("invalid 'admin' field")
+		}
+	}
+
+	return &m, nil
+}
+
+func assembleStringField(ma datamodel.MapAssembler, key string, value string) error {
+	if err := ma.AssembleKey().AssignString(key); err != nil {
+		return err
+	}
+	return ma.AssembleValue().AssignString(value)
+}
+
+func getString(node datamodel.Node, key string) (string, error) {
+	childNode, err := node.LookupByString(key)
+	if err != nil {
+		return "", err
+	}
+	return childNode.AsString()
+}
@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/multiformats/go-multibase"
+)
+
+func TestEncodeDecodeManifest(t *testing.T) {
+	m := Manifest{Name: "test-db", Type: "keyvalue", AccessController: "ipfs"}
+
+	hash, data, err := EncodeManifest(m)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	if hash == "" || len(data) == 0 {
+		t.Fatal("Expected hash and bytes to be populated")
+	}
+
+	decoded, err := DecodeManifest(data)
+	if err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, m) {
+		t.Errorf("Expected decoded manifest %+v, got %+v", m, *decoded)
+	}
+}
+
+func TestCreateManifest(t *testing.T) {
+	c, data, err := CreateManifest("test-db", "keyvalue", "ipfs")
+	if err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+
+	m, err := ReadManifest(data)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if !reflect.DeepEqual(*m, Manifest{Name: "test-db", Type: "keyvalue", AccessController: "ipfs"}) {
+		t.Errorf("Unexpected manifest %+v", *m)
+	}
+
+	// Identical inputs give the same CID, which matches EncodeManifest's hash
+	again, _, err := CreateManifest("test-db", "keyvalue", "ipfs")
+	if err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+	if !c.Equals(again) {
+		t.Errorf("Expected stable CID, got %s and %s", c, again)
+	}
+	hash, _, err := EncodeManifest(*m)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	if encoded, _ := c.StringOfBase(multibase.Base58BTC); encoded != hash {
+		t.Errorf("Expected CID %s to match hash %s", encoded, hash)
+	}
+
+	other, _, err := CreateManifest("other-db", "keyvalue", "ipfs")
+	if err != nil {
+		t.Fatalf("Failed to create manifest: %v", err)
+	}
+	if c.Equals(other) {
+		t.Error("Expected different manifests to have different CIDs")
+	}
+}
+
+func TestEncodeManifestRequiresNameAndType(t *testing.T) {
+	if _, _, err := EncodeManifest(Manifest{Type: "events"}); err == nil {
+		t.Error("Expected error for manifest without a name")
+	}
+	if _, _, err := EncodeManifest(Manifest{Name: "test-db"}); err == nil {
+		t.Error("Expected error for manifest without a type")
+	}
+}
+
+func TestManifestAdmin(t *testing.T) {
+	without := Manifest{Name: "test-db", Type: "keyvalue", AccessController: "ipfs"}
+	with := without
+	with.Admin = "ab"
+
+	hashWithout, _, err := EncodeManifest(without)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	hashWith, data, err := EncodeManifest(with)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	if hashWith == hashWithout {
+		t.Error("Expected the admin to change the manifest hash")
+	}
+
+	decoded, err := DecodeManifest(data)
+	if err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, with) {
+		t.Errorf("Expected decoded manifest %+v, got %+v", with, *decoded)
+	}
+}
+
+func TestManifestWrite(t *testing.T) {
+	without := Manifest{Name: "test-db", Type: "keyvalue", AccessController: "ipfs"}
+	with := without
+	with.Write = []string{"writer-1", "writer-2"}
+
+	hashWithout, _, err := EncodeManifest(without)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	hashWith, data, err := EncodeManifest(with)
+	if err != nil {
+		t.Fatalf("Failed to encode manifest: %v", err)
+	}
+	if hashWith == hashWithout {
+		t.Error("Expected the write list to change the manifest hash")
+	}
+
+	decoded, err := DecodeManifest(data)
+	if err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, with) {
+		t.Errorf("Expected decoded manifest %+v, got %+v", with, *decoded)
+	}
+}
@@ -0,0 +1,112 @@
+package manifest
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+)
+
+// Update replaces the manifest of an existing database. The database keeps
+// its address, which is the hash of its original manifest, while Previous
+// links the update being replaced, so the history can be walked back even
+// when a manifest is adopted again.
+// Updates are signed by the admin named in the previous manifest.
+type Update struct {
+	Address   string   // Address of the database being updated
+	Previous  string   // Hash of the update being replaced, or of the original manifest for the first update
+	Manifest  Manifest // Replacement manifest
+	Signature string   // Admin signature over SigningPayload
+}
+
+// SigningPayload returns the bytes the admin signs: the encoded update
+// without its signature.
+func SigningPayload(u Update) ([]byte, error) {
+	u.Signature = ""
+	_, data, err := EncodeUpdate(u)
+	return data, err
+}
+
+// EncodeUpdate encodes an update into CBOR and returns its hash and bytes.
+func EncodeUpdate(u Update) (string, []byte, error) {
+	if u.Address == "" || u.Previous == "" {
+		return "", nil, errors.New("update requires an address and a previous manifest")
+	}
+	if u.Manifest.Name == "" || u.Manifest.Type == "" {
+		return "", nil, errors.New("manifest requires a name and a type")
+	}
+
+	nb := basicnode.Prototype__Map{}.NewBuilder()
+	ma, err := nb.BeginMap(4)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := assembleStringField(ma, "address", u.Address); err != nil {
+		return "", nil, err
+	}
+	if err := assembleStringField(ma, "previous", u.Previous); err != nil {
+		return "", nil, err
+	}
+	if err := ma.AssembleKey().AssignString("manifest"); err != nil {
+		return "", nil, err
+	}
+	if err := assembleManifest(ma.AssembleValue(), u.Manifest); err != nil {
+		return "", nil, err
+	}
+	if err := assembleStringField(ma, "sig", u.Signature); err != nil {
+		return "", nil, err
+	}
+	if err := ma.Finish(); err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := dagcbor.Encode(nb.Build(), &buf); err != nil {
+		return "", nil, err
+	}
+
+	hash, err := hashBytes(buf.Bytes())
+	if err != nil {
+		return "", nil, err
+	}
+	return hash, buf.Bytes(), nil
+}
+
+// DecodeUpdate decodes CBOR-encoded bytes back into an Update.
+func DecodeUpdate(data []byte) (*Update, error) {
+	if len(data) == 0 {
+		return nil, errors.New("invalid or empty input data")
+	}
+
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := dagcbor.Decode(nb, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	node := nb.Build()
+
+	var u Update
+	var err error
+	if u.Address, err = getString(node, "address"); err != nil || u.Address == "" {
+		return nil, errors.New("invalid or missing 'address' field")
+	}
+	if u.Previous, err = getString(node, "previous"); err != nil || u.Previous == "" {
+		return nil, errors.New("invalid or missing 'previous' field")
+	}
+	if u.Signature, err = getString(node, "sig"); err != nil {
+		return nil, errors.New("invalid or missing 'sig' field")
+	}
+
+	var manifestNode datamodel.Node
+	if manifestNode, err = node.LookupByString("manifest"); err != nil {
+		return nil, errors.New("invalid or missing 'manifest' field")
+	}
+	m, err := manifestFromNode(manifestNode)
+	if err != nil {
+		return nil, err
+	}
+	u.Manifest = *m
+
+	return &u, nil
+}
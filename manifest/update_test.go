@@ -0,0 +1,57 @@
+package manifest
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecodeUpdate(t *testing.T) {
+	u := Update{
+		Address:   "/orbitdb/zdpuA",
+		Previous:  "zdpuB",
+		Manifest:  Manifest{Name: "renamed-db", Type: "keyvalue", AccessController: "ipfs", Admin: "ab"},
+		Signature: "signature",
+	}
+
+	hash, data, err := EncodeUpdate(u)
+	if err != nil {
+		t.Fatalf("Failed to encode update: %v", err)
+	}
+	if hash == "" || len(data) == 0 {
+		t.Fatal("Expected hash and bytes to be populated")
+	}
+
+	decoded, err := DecodeUpdate(data)
+	if err != nil {
+		t.Fatalf("Failed to decode update: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, u) {
+		t.Errorf("Expected decoded update %+v, got %+v", u, *decoded)
+	}
+
+	// The signing payload covers everything but the signature
+	payload, err := SigningPayload(u)
+	if err != nil {
+		t.Fatalf("Failed to build signing payload: %v", err)
+	}
+	u.Signature = "other"
+	other, err := SigningPayload(u)
+	if err != nil {
+		t.Fatalf("Failed to build signing payload: %v", err)
+	}
+	if !bytes.Equal(payload, other) {
+		t.Error("Expected signing payload to ignore the signature")
+	}
+	u.Manifest.AccessController = "open"
+	if changed, _ := SigningPayload(u); bytes.Equal(payload, changed) {
+		t.Error("Expected signing payload to cover the manifest")
+	}
+
+	if _, _, err := EncodeUpdate(Update{Address: "/orbitdb/zdpuA", Manifest: u.Manifest}); err == nil {
+		t.Error("Expected error for update without a previous manifest")
+	}
+	if _, err := DecodeUpdate(nil); err == nil {
+		t.Error("Expected error for empty update data")
+	}
+}
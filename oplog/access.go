@@ -0,0 +1,132 @@
+package oplog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// Operations recorded by the access audit and metrics.
+const (
+	OpAppend = "append"
+	OpJoin   = "join"
+)
+
+// AccessController decides whether an entry may be written to a log.
+// The identity is the resolved writer identity when known, nil otherwise.
+// Type names the controller, as recorded in database manifests.
+type AccessController interface {
+	Type() string
+	CanAppend(entry EncodedEntry, identity *identitytypes.Identity) (bool, error)
+}
+
+// AuditRecord describes a single access-control decision.
+type AuditRecord struct {
+	Identity string    `json:"identity"`
+	Op       string    `json:"op"`
+	Allowed  bool      `json:"allowed"`
+	Reason   string    `json:"reason"`
+	Time     time.Time `json:"time"`
+}
+
+// AccessAudit receives every access-control decision made by a log.
+type AccessAudit interface {
+	Record(record AuditRecord)
+}
+
+// NoopAudit discards all audit records. It is the default for new logs.
+type NoopAudit struct{}
+
+// Record implements AccessAudit.
+func (NoopAudit) Record(AuditRecord) {}
+
+// RingAudit keeps the most recent audit records in a fixed-size ring buffer.
+type RingAudit struct {
+	records []AuditRecord
+	next    int
+	full    bool
+	mu      sync.Mutex
+}
+
+// NewRingAudit creates a RingAudit holding at most capacity records.
+func NewRingAudit(capacity int) *RingAudit {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &RingAudit{records: make([]AuditRecord, capacity)}
+}
+
+// Record stores a record, overwriting the oldest one when the buffer is full.
+func (r *RingAudit) Record(record AuditRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Records returns a copy of the buffered records, oldest first.
+func (r *RingAudit) Records() []AuditRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]AuditRecord(nil), r.records[:r.next]...)
+	}
+	out := make([]AuditRecord, 0, len(r.records))
+	out = append(out, r.records[r.next:]...)
+	return append(out, r.records[:r.next]...)
+}
+
+// Replay calls fn for every buffered record, oldest first.
+func (r *RingAudit) Replay(fn func(AuditRecord)) {
+	for _, record := range r.Records() {
+		fn(record)
+	}
+}
+
+// SetAccessController replaces the controller consulted for later appends
+// and joins. Entries already in the log are not re-checked.
+func (l *Log) SetAccessController(ac AccessController) {
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+	l.access = ac
+}
+
+// canAppend consults the access controller and records the decision.
+// A log without an access controller allows every write.
+func (l *Log) canAppend(op string, entry EncodedEntry, identity *identitytypes.Identity) error {
+	allowed, reason := true, ""
+	if l.access != nil {
+		ok, err := l.access.CanAppend(entry, identity)
+		allowed = ok && err == nil
+		switch {
+		case err != nil:
+			reason = err.Error()
+		case !ok:
+			reason = "denied by access controller"
+		}
+	}
+
+	who := entry.Identity
+	if identity != nil {
+		who = identity.ID
+	}
+	l.audit.Record(AuditRecord{
+		Identity: who,
+		Op:       op,
+		Allowed:  allowed,
+		Reason:   reason,
+		Time:     time.Now(),
+	})
+
+	if !allowed {
+		return fmt.Errorf("%s of entry %s not allowed: %s", op, entry.Hash, reason)
+	}
+	return nil
+}
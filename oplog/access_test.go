@@ -0,0 +1,149 @@
+package oplog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// payloadController denies any entry whose payload is listed as forbidden.
+type payloadController struct {
+	forbidden map[string]bool
+}
+
+func (c payloadController) Type() string { return "payload" }
+
+func (c payloadController) CanAppend(entry EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	if c.forbidden[entry.Payload] {
+		return false, errors.New("forbidden payload")
+	}
+	return true, nil
+}
+
+func TestLog_AccessAudit(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	audit := NewRingAudit(10)
+	controller := payloadController{forbidden: map[string]bool{"denied": true}}
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks,
+		WithAccessController(controller), WithAccessAudit(audit))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	if _, err := log.Append("first"); err != nil {
+		t.Fatalf("Expected first append to be allowed: %v", err)
+	}
+	if _, err := log.Append("denied"); err == nil {
+		t.Fatal("Expected denied append to fail")
+	}
+	if _, err := log.Append("second"); err != nil {
+		t.Fatalf("Expected second append to be allowed: %v", err)
+	}
+
+	expected := []AuditRecord{
+		{Identity: identity.ID, Op: OpAppend, Allowed: true},
+		{Identity: identity.ID, Op: OpAppend, Allowed: false, Reason: "forbidden payload"},
+		{Identity: identity.ID, Op: OpAppend, Allowed: true},
+	}
+
+	records := audit.Records()
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d audit records, got %d", len(expected), len(records))
+	}
+	for i, record := range records {
+		want := expected[i]
+		if record.Identity != want.Identity || record.Op != want.Op ||
+			record.Allowed != want.Allowed || record.Reason != want.Reason {
+			t.Errorf("Record %d mismatch: expected %+v, got %+v", i, want, record)
+		}
+		if record.Time.IsZero() {
+			t.Errorf("Record %d has no timestamp", i)
+		}
+	}
+
+	// The denied append must not advance the clock
+	if log.Clock.Time != 2 {
+		t.Errorf("Expected clock time 2 after two allowed appends, got %d", log.Clock.Time)
+	}
+}
+
+func TestLog_SetAccessController(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if _, err := log.Append("denied"); err != nil {
+		t.Fatalf("Expected append without a controller to succeed: %v", err)
+	}
+
+	log.SetAccessController(payloadController{forbidden: map[string]bool{"denied": true}})
+	if _, err := log.Append("denied"); err == nil {
+		t.Error("Expected append to be denied by the new controller")
+	}
+	if _, err := log.Append("allowed"); err != nil {
+		t.Errorf("Expected allowed append to succeed: %v", err)
+	}
+}
+
+// denyAllController rejects every entry.
+type denyAllController struct{}
+
+func (denyAllController) Type() string { return "deny-all" }
+
+func (denyAllController) CanAppend(entry EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	return false, nil
+}
+
+func TestLog_DenyAllAccessController(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithAccessController(denyAllController{}))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	_, err = log.Append("payload")
+	if err == nil || !strings.Contains(err.Error(), "append of entry") || !strings.Contains(err.Error(), "denied by access controller") {
+		t.Fatalf("Expected a descriptive denial, got %v", err)
+	}
+	if log.Head != nil {
+		t.Error("Expected head to be unchanged after a denied append")
+	}
+
+	// Joined entries are checked by the same controller
+	other, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if _, err := other.Append("payload"); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if err := log.Join(other); err == nil || !strings.Contains(err.Error(), "join of entry") {
+		t.Errorf("Expected join to be denied, got %v", err)
+	}
+	if log.Head != nil {
+		t.Error("Expected head to be unchanged after a denied join")
+	}
+}
+
+func TestRingAudit_Overwrite(t *testing.T) {
+	audit := NewRingAudit(2)
+	for _, op := range []string{"a", "b", "c"} {
+		audit.Record(AuditRecord{Op: op})
+	}
+
+	var replayed []string
+	audit.Replay(func(record AuditRecord) {
+		replayed = append(replayed, record.Op)
+	})
+
+	if len(replayed) != 2 || replayed[0] != "b" || replayed[1] != "c" {
+		t.Errorf("Expected replay [b c], got %v", replayed)
+	}
+}
@@ -1,5 +1,18 @@
 package oplog
 
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxClockTime is the largest clock time a log will produce or accept in
+// strict mode. It matches the largest integer JavaScript peers can represent.
+const MaxClockTime = 1<<53 - 1
+
+// ErrClockOverflow is returned when advancing a clock would exceed MaxClockTime.
+var ErrClockOverflow = errors.New("clock time overflow")
+
 type Clock struct {
 	ID   string `json:"id"`
 	Time int    `json:"time"`
@@ -12,25 +25,85 @@ func NewClock(id string, time int) Clock {
 	}
 }
 
-func CompareClocks(a Clock, b Clock) (res int) {
-	dist := a.Time - b.Time
-	res = dist
-
-	if dist == 0 && a.ID != b.ID {
-		if a.ID < b.ID {
-			res = -1
-		} else {
-			res = 1
-		}
+// CompareClocks orders clocks by time, breaking ties by comparing ids
+// lexicographically. It returns -1, 0 or 1, and 0 only for identical clocks.
+func CompareClocks(a Clock, b Clock) int {
+	switch {
+	case a.Time < b.Time:
+		return -1
+	case a.Time > b.Time:
+		return 1
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
 	}
+	return 0
+}
 
-	return
+// SafeTickClock returns the next clock, or ErrClockOverflow when the clock
+// has already reached MaxClockTime.
+func SafeTickClock(c Clock) (Clock, error) {
+	if c.Time >= MaxClockTime {
+		return c, ErrClockOverflow
+	}
+	return TickClock(c), nil
 }
 
 func TickClock(c Clock) Clock {
+	return c.Tick()
+}
+
+// Tick returns a clock with the same id and the time advanced by one.
+func (c Clock) Tick() Clock {
 	return Clock{ID: c.ID, Time: c.Time + 1}
 }
 
-func (c *Clock) Tick() {
-	c.Time += 1
+// Merge returns a clock with c's id and the later of the two times, so the
+// next tick is ordered after everything other has seen.
+func (c Clock) Merge(other Clock) Clock {
+	return Clock{ID: c.ID, Time: max(c.Time, other.Time)}
+}
+
+// ToMap returns the clock in its IPLD map representation ({id, time}).
+func (c Clock) ToMap() map[string]any {
+	return map[string]any{
+		"id":   c.ID,
+		"time": c.Time,
+	}
+}
+
+// ClockFromMap builds a Clock from its IPLD map representation. Numeric
+// times decoded by other tools (int64, uint64, float64) are accepted as
+// long as they are non-negative integers.
+func ClockFromMap(m map[string]any) (Clock, error) {
+	id, ok := m["id"].(string)
+	if !ok || id == "" {
+		return Clock{}, errors.New("clock map requires a non-empty string 'id'")
+	}
+
+	var time int
+	switch v := m["time"].(type) {
+	case int:
+		time = v
+	case int64:
+		time = int(v)
+	case uint64:
+		if v > MaxClockTime {
+			return Clock{}, fmt.Errorf("clock time %d out of range", v)
+		}
+		time = int(v)
+	case float64:
+		if v != math.Trunc(v) || v > MaxClockTime {
+			return Clock{}, fmt.Errorf("clock time %v is not an integer in range", v)
+		}
+		time = int(v)
+	default:
+		return Clock{}, fmt.Errorf("clock map requires an integer 'time', got %T", m["time"])
+	}
+	if time < 0 || time > MaxClockTime {
+		return Clock{}, fmt.Errorf("clock time %d out of range", time)
+	}
+
+	return Clock{ID: id, Time: time}, nil
 }
@@ -68,7 +68,7 @@ func TestClockTime1LessThanTime2By10(t *testing.T) {
 	c1 := NewClock("a", 1)
 	c2 := NewClock("a", 11)
 
-	expected := -10
+	expected := -1
 	actual := CompareClocks(c1, c2)
 
 	if actual != expected {
@@ -80,7 +80,7 @@ func TestClockTime1GreaterThanTime2By10(t *testing.T) {
 	c1 := NewClock("a", 11)
 	c2 := NewClock("a", 1)
 
-	expected := 10
+	expected := 1
 	actual := CompareClocks(c1, c2)
 
 	if actual != expected {
@@ -100,3 +100,94 @@ func TestTickClock(t *testing.T) {
 		t.Errorf("expected '%d' but got '%d'", expected, actual)
 	}
 }
+
+func TestClockTick(t *testing.T) {
+	c := NewClock("a", 1)
+
+	ticked := c.Tick()
+	if ticked != NewClock("a", 2) {
+		t.Errorf("expected '%v' but got '%v'", NewClock("a", 2), ticked)
+	}
+	if c.Time != 1 {
+		t.Errorf("expected Tick to leave the original clock unchanged, got '%d'", c.Time)
+	}
+}
+
+func TestClockMerge(t *testing.T) {
+	c := NewClock("a", 3)
+
+	if merged := c.Merge(NewClock("b", 5)); merged != NewClock("a", 5) {
+		t.Errorf("expected '%v' but got '%v'", NewClock("a", 5), merged)
+	}
+	if merged := c.Merge(NewClock("b", 1)); merged != c {
+		t.Errorf("expected merging an older clock to keep '%v', got '%v'", c, merged)
+	}
+	if merged := c.Merge(Clock{}); merged != c {
+		t.Errorf("expected merging a zero clock to keep '%v', got '%v'", c, merged)
+	}
+}
+
+func TestCompareClocksTable(t *testing.T) {
+	tests := []struct {
+		a, b     Clock
+		expected int
+	}{
+		{NewClock("a", 1), NewClock("a", 1), 0},
+		{NewClock("", 1), NewClock("", 1), 0},
+		{NewClock("a", 1), NewClock("b", 1), -1},
+		{NewClock("b", 1), NewClock("a", 1), 1},
+		{NewClock("", 1), NewClock("a", 1), -1},
+		{NewClock("ab", 1), NewClock("b", 1), -1},
+		{NewClock("b", 1), NewClock("a", 2), -1},
+		{NewClock("a", 5), NewClock("z", 1), 1},
+	}
+
+	for _, tt := range tests {
+		if actual := CompareClocks(tt.a, tt.b); actual != tt.expected {
+			t.Errorf("CompareClocks(%v, %v): expected '%d' but got '%d'", tt.a, tt.b, tt.expected, actual)
+		}
+		if actual := CompareClocks(tt.b, tt.a); actual != -tt.expected {
+			t.Errorf("CompareClocks(%v, %v): expected '%d' but got '%d'", tt.b, tt.a, -tt.expected, actual)
+		}
+	}
+}
+
+func TestClockMapRoundTrip(t *testing.T) {
+	c := NewClock("a", 42)
+
+	m := c.ToMap()
+	if m["id"] != "a" || m["time"] != 42 {
+		t.Fatalf("unexpected map representation %v", m)
+	}
+
+	actual, err := ClockFromMap(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual != c {
+		t.Errorf("expected '%v' but got '%v'", c, actual)
+	}
+
+	// Times decoded from JSON arrive as float64
+	actual, err = ClockFromMap(map[string]any{"id": "a", "time": float64(42)})
+	if err != nil || actual != c {
+		t.Errorf("expected '%v' but got '%v' (%v)", c, actual, err)
+	}
+}
+
+func TestClockFromMapInvalid(t *testing.T) {
+	invalid := []map[string]any{
+		{"time": 1},
+		{"id": "", "time": 1},
+		{"id": "a"},
+		{"id": "a", "time": "1"},
+		{"id": "a", "time": -1},
+		{"id": "a", "time": 1.5},
+	}
+
+	for _, m := range invalid {
+		if _, err := ClockFromMap(m); err == nil {
+			t.Errorf("expected error for %v", m)
+		}
+	}
+}
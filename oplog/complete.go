@@ -0,0 +1,59 @@
+package oplog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// EntryFetcher retrieves an entry by its hash, typically from a remote peer.
+type EntryFetcher func(ctx context.Context, hash string) (*EncodedEntry, error)
+
+// EnsureComplete fetches and joins the ancestors reported by MissingAncestors
+// until none remain, so queries over the log see its full history. Every
+// fetched entry is decoded from its bytes, checked against the requested
+// hash and joined through JoinEntry under the log's write lock, which is
+// released between entries and while fetching. It stops early when ctx is
+// cancelled or a fetch fails.
+func (l *Log) EnsureComplete(ctx context.Context, fetcher EntryFetcher) error {
+	if fetcher == nil {
+		return errors.New("entry fetcher is required")
+	}
+
+	processed := make(map[string]bool)
+	for {
+		missing := l.MissingAncestors()
+		if len(missing) == 0 {
+			return nil
+		}
+
+		for _, hash := range missing {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			fetched, err := fetcher(ctx, hash)
+			if err != nil {
+				return fmt.Errorf("failed to fetch entry %s: %w", hash, err)
+			}
+			if fetched == nil {
+				return fmt.Errorf("fetcher returned no entry for %s", hash)
+			}
+
+			// Trust only the bytes, not the fetched struct
+			entry, err := Decode(fetched.Bytes)
+			if err != nil {
+				return fmt.Errorf("failed to decode fetched entry %s: %w", hash, err)
+			}
+			if entry.Hash != hash {
+				return fmt.Errorf("fetcher returned entry %s for %s", entry.Hash, hash)
+			}
+			l.Mu.Lock()
+			err = l.JoinEntry(&entry, processed)
+			l.Mu.Unlock()
+			if err != nil {
+				return fmt.Errorf("failed to join entry %s: %w", hash, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package oplog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+// storageFetcher serves entries from a log's storage and counts the fetches.
+func storageFetcher(source *Log, fetches *int) EntryFetcher {
+	return func(ctx context.Context, hash string) (*EncodedEntry, error) {
+		*fetches++
+		return source.Get(hash)
+	}
+}
+
+func TestLog_EnsureComplete(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+	var head *EncodedEntry
+	for _, payload := range []string{"entry1", "entry2", "entry3", "entry4"} {
+		if head, err = source.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	// A thin client holding only the head
+	partial, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create partial log: %v", err)
+	}
+	if err := partial.JoinEntry(head, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join head entry: %v", err)
+	}
+	if len(partial.MissingAncestors()) == 0 {
+		t.Fatal("Expected the partial log to have missing ancestors")
+	}
+
+	fetches := 0
+	if err := partial.EnsureComplete(context.Background(), storageFetcher(source, &fetches)); err != nil {
+		t.Fatalf("EnsureComplete failed: %v", err)
+	}
+	if missing := partial.MissingAncestors(); len(missing) != 0 {
+		t.Errorf("Expected no missing ancestors, got %v", missing)
+	}
+	if fetches != 3 {
+		t.Errorf("Expected 3 fetches, got %d", fetches)
+	}
+	got, err := partial.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if payloads := fmt.Sprint(payloadsOf(got)); payloads != "[entry1 entry2 entry3 entry4]" {
+		t.Errorf("Expected every entry after EnsureComplete, got %v", payloads)
+	}
+
+	// A complete log fetches nothing
+	fetches = 0
+	if err := partial.EnsureComplete(context.Background(), storageFetcher(source, &fetches)); err != nil || fetches != 0 {
+		t.Errorf("Expected no fetches for a complete log, got %d (%v)", fetches, err)
+	}
+}
+
+func TestLog_EnsureCompleteConcurrentAppend(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+	var head *EncodedEntry
+	for i := 0; i < 20; i++ {
+		if head, err = source.Append(fmt.Sprintf("entry%d", i)); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	partial, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create partial log: %v", err)
+	}
+	if err := partial.JoinEntry(head, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join head entry: %v", err)
+	}
+
+	// Local appends race with the joins of fetched ancestors
+	done := make(chan error, 1)
+	go func() {
+		done <- partial.EnsureComplete(context.Background(), func(ctx context.Context, hash string) (*EncodedEntry, error) {
+			return source.Get(hash)
+		})
+	}()
+	for i := 0; i < 10; i++ {
+		if _, err := partial.Append(fmt.Sprintf("local%d", i)); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("EnsureComplete failed: %v", err)
+	}
+
+	got, err := partial.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(got) != 30 {
+		t.Errorf("Expected 30 entries, got %d", len(got))
+	}
+	if missing := partial.MissingAncestors(); len(missing) != 0 {
+		t.Errorf("Expected no missing ancestors, got %v", missing)
+	}
+}
+
+func TestLog_EnsureCompleteErrors(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+	first, err := source.Append("entry1")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	head, err := source.Append("entry2")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	newPartial := func() *Log {
+		partial, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+		if err != nil {
+			t.Fatalf("Failed to create partial log: %v", err)
+		}
+		if err := partial.JoinEntry(head, make(map[string]bool)); err != nil {
+			t.Fatalf("Failed to join head entry: %v", err)
+		}
+		return partial
+	}
+
+	// A cancelled context stops before fetching
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetches := 0
+	if err := newPartial().EnsureComplete(ctx, storageFetcher(source, &fetches)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if fetches != 0 {
+		t.Errorf("Expected no fetches after cancellation, got %d", fetches)
+	}
+
+	// Fetch failures are reported
+	failing := func(ctx context.Context, hash string) (*EncodedEntry, error) {
+		return nil, errors.New("peer unavailable")
+	}
+	if err := newPartial().EnsureComplete(context.Background(), failing); err == nil {
+		t.Error("Expected fetch failure to be reported")
+	}
+
+	// An entry that does not match the requested hash is rejected
+	wrong := func(ctx context.Context, hash string) (*EncodedEntry, error) {
+		forged := *head
+		forged.Hash = first.Hash
+		return &forged, nil
+	}
+	if err := newPartial().EnsureComplete(context.Background(), wrong); err == nil {
+		t.Error("Expected a mismatched entry to be rejected")
+	}
+}
@@ -0,0 +1,34 @@
+package oplog
+
+// WithPayloadDedup makes Append return the existing entry instead of storing
+// a duplicate when an append would produce an entry identical to one already
+// in the log: same author, payload, clock, next and refs. Signatures are not
+// deterministic, so entries are matched on their ContentID. Because
+// the clock advances on every append, this only deduplicates truly identical
+// entries, such as appends replayed against a rewound clock and head.
+func WithPayloadDedup() LogOption {
+	return func(l *Log) {
+		l.dedup = make(map[string]string)
+	}
+}
+
+// duplicateOf returns the stored entry whose content matches entry, if any.
+func (l *Log) duplicateOf(entry EncodedEntry) (*EncodedEntry, bool) {
+	key, err := ContentID(entry.Entry)
+	if err != nil {
+		return nil, false
+	}
+	hash, ok := l.dedup[key]
+	if !ok {
+		return nil, false
+	}
+	data, err := l.Entries.Get(hash)
+	if err != nil {
+		return nil, false
+	}
+	existing, err := Decode(data)
+	if err != nil || existing.Hash != hash {
+		return nil, false
+	}
+	return &existing, true
+}
@@ -0,0 +1,109 @@
+package oplog
+
+import (
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+func countEntries(t *testing.T, s storage.Storage) int {
+	t.Helper()
+	ch, err := s.Iterator()
+	if err != nil {
+		t.Fatalf("Failed to iterate storage: %v", err)
+	}
+	count := 0
+	for range ch {
+		count++
+	}
+	return count
+}
+
+// rewind resets the log so the next append reuses the given clock and head.
+func rewind(log *Log, clock Clock, head *EncodedEntry) {
+	log.Clock = clock
+	log.Head = head
+	log.heads, log.headsKnown = nil, true
+	if head != nil {
+		log.heads = []string{head.Hash}
+	}
+}
+
+func TestLog_PayloadDedup(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks, WithPayloadDedup())
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	start := log.Clock
+	first, err := log.Append("same")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// Force the next append to use an equal clock and the same next
+	rewind(log, start, nil)
+	second, err := log.Append("same")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	if second.Hash != first.Hash {
+		t.Fatalf("Expected duplicate append to return %s, got %s", first.Hash, second.Hash)
+	}
+	if n := countEntries(t, log.Entries); n != 1 {
+		t.Fatalf("Expected 1 stored entry, got %d", n)
+	}
+
+	// A different payload at the same clock is not a duplicate
+	rewind(log, start, nil)
+	other, err := log.Append("different")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if other.Hash == first.Hash {
+		t.Fatal("Expected a different payload to produce a new entry")
+	}
+	if n := countEntries(t, log.Entries); n != 2 {
+		t.Fatalf("Expected 2 stored entries, got %d", n)
+	}
+
+	// Normal appends advance the clock and are never deduplicated
+	rewind(log, first.Clock, first)
+	third, err := log.Append("same")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if third.Hash == first.Hash {
+		t.Fatal("Expected an append with an advanced clock to produce a new entry")
+	}
+}
+
+func TestLog_WithoutPayloadDedup(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	start := log.Clock
+	first, err := log.Append("same")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	rewind(log, start, nil)
+	second, err := log.Append("same")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// Signatures differ, so without dedup both entries are stored
+	if second.Hash == first.Hash {
+		t.Fatal("Expected distinct entries without payload dedup")
+	}
+	if n := countEntries(t, log.Entries); n != 2 {
+		t.Fatalf("Expected 2 stored entries, got %d", n)
+	}
+}
@@ -2,28 +2,47 @@ package oplog
 
 import (
 	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
 	"github.com/ipfs/go-cid"
 	"github.com/ipld/go-ipld-prime/codec/dagcbor"
 	"github.com/ipld/go-ipld-prime/datamodel"
 	"github.com/ipld/go-ipld-prime/node/basicnode"
 	"github.com/multiformats/go-multibase"
 	mh "github.com/multiformats/go-multihash"
+	"io"
 	"log"
 	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/keystore"
 	"sort"
+	"unicode/utf8"
 )
 
+// EntryVersion is the schema version of the entries NewEntry creates. It is
+// bumped whenever the encoded fields of an entry change. Version 3 added
+// Meta, version 4 PayloadData and version 5 SigAlgo.
+const EntryVersion = 5
+
+// MaxEntryMetaSize is the maximum combined length in bytes of the keys and
+// values of an entry's Meta. NewEntryWithMeta, Decode and ValidateEntry
+// reject entries exceeding it.
+var MaxEntryMetaSize = 4096
+
 type Entry struct {
-	ID        string   `json:"ID"`
-	Payload   string   `json:"payload"`
-	Next      []string `json:"next"`
-	Refs      []string `json:"refs"`
-	Clock     Clock    `json:"clock"`
-	V         int      `json:"v"`
-	Key       string   `json:"key"`
-	Identity  string   `json:"identity"`
-	Signature string   `json:"sig"`
+	ID          string            `json:"ID"`
+	Payload     string            `json:"payload"`
+	PayloadData []byte            `json:"payloadData,omitempty"`
+	Next        []string          `json:"next"`
+	Refs        []string          `json:"refs"`
+	Clock       Clock             `json:"clock"`
+	V           int               `json:"v"`
+	Meta        map[string]string `json:"meta,omitempty"`
+	Key         string            `json:"key"`
+	Identity    string            `json:"identity"`
+	SigAlgo     string            `json:"sigAlgo,omitempty"`
+	Signature   string            `json:"sig"`
 }
 
 type EncodedEntry struct {
@@ -39,13 +58,57 @@ func (e EncodedEntry) GetBase58CID() string {
 	return cidBase58
 }
 
+// ContentID returns the deterministic identifier of an entry: the base58btc
+// CID of its canonical form with the signature removed. ECDSA signatures are
+// randomized, so Hash, which covers the signed block and is the storage key,
+// differs each time the same entry is signed. ContentID does not. Entry
+// identity is per author: Key and Identity stay in the canonical form, so two
+// authors writing the same payload at the same clock get different IDs.
+func ContentID(e Entry) (string, error) {
+	e.Signature = ""
+	encoded, err := Encode(e)
+	if err != nil {
+		return "", err
+	}
+	return encoded.Hash, nil
+}
+
 // NewEntry creates a new log entry, signing it with the KeyStore.
-func NewEntry(ks *keystore.KeyStore, identity *identitytypes.Identity, id string, payload string, clock Clock, next []string, refs []string) EncodedEntry {
+func NewEntry(ks *keystore.KeyStore, identity *identitytypes.Identity, id string, payload string, clock Clock, next []string, refs []string) (EncodedEntry, error) {
+	return NewEntryWithMeta(ks, identity, id, payload, clock, next, refs, nil)
+}
+
+// NewEntryWithMeta creates a new log entry carrying application metadata,
+// such as a content type, alongside its payload. Meta is covered by the
+// signature and limited to MaxEntryMetaSize bytes.
+func NewEntryWithMeta(ks *keystore.KeyStore, identity *identitytypes.Identity, id string, payload string, clock Clock, next []string, refs []string, meta map[string]string) (EncodedEntry, error) {
+	if payload == "" {
+		return EncodedEntry{}, errors.New("entry requires an ID and payload")
+	}
+	return newEntry(ks, identity, id, payload, nil, clock, next, refs, meta)
+}
+
+// NewEntryWithData creates a new log entry whose payload is binary data,
+// such as a value encoded with EncodePayload, instead of a string. The
+// entry's Payload is empty and its PayloadData is covered by the signature.
+func NewEntryWithData(ks *keystore.KeyStore, identity *identitytypes.Identity, id string, data []byte, clock Clock, next []string, refs []string) (EncodedEntry, error) {
+	if len(data) == 0 {
+		return EncodedEntry{}, errors.New("entry requires an ID and payload")
+	}
+	return newEntry(ks, identity, id, "", data, clock, next, refs, nil)
+}
+
+// newEntry creates and signs an entry with a string payload, binary payload
+// data, or both.
+func newEntry(ks *keystore.KeyStore, identity *identitytypes.Identity, id string, payload string, data []byte, clock Clock, next []string, refs []string, meta map[string]string) (EncodedEntry, error) {
 	if identity == nil {
-		panic("Identity is required, cannot create entry")
+		return EncodedEntry{}, errors.New("identity is required, cannot create entry")
 	}
-	if id == "" || payload == "" {
-		panic("Entry requires an ID and payload")
+	if id == "" || (payload == "" && len(data) == 0) {
+		return EncodedEntry{}, errors.New("entry requires an ID and payload")
+	}
+	if err := validateMeta(meta); err != nil {
+		return EncodedEntry{}, err
 	}
 	// Initialize next and refs as empty slices if nil
 	if next == nil {
@@ -61,21 +124,24 @@ func NewEntry(ks *keystore.KeyStore, identity *identitytypes.Identity, id string
 
 	// Create an entry without Key, Identity, and Signature
 	entry := Entry{
-		ID:      id,
-		Payload: payload,
-		Next:    next,
-		Refs:    refs,
-		Clock:   clockOrDefault(clock, identity),
-		V:       2,
+		ID:          id,
+		Payload:     payload,
+		PayloadData: data,
+		Next:        next,
+		Refs:        refs,
+		Clock:       clockOrDefault(clock, identity),
+		V:           EntryVersion,
+		Meta:        meta,
+	}
+
+	// Sign the canonical signing payload
+	payloadBytes, err := SigningPayload(entry)
+	if err != nil {
+		return EncodedEntry{}, err
 	}
-
-	// Encode the entry to CBOR
-	encodedEntry := Encode(entry)
-
-	// Sign the encoded entry data
-	signature, err := ks.SignMessage(identity.ID, encodedEntry.Bytes)
+	signature, err := ks.SignMessage(identity.ID, payloadBytes)
 	if err != nil {
-		panic(err)
+		return EncodedEntry{}, fmt.Errorf("failed to sign entry: %w", err)
 	}
 
 	// Now assign Key, Identity, and Signature fields
@@ -86,43 +152,128 @@ func NewEntry(ks *keystore.KeyStore, identity *identitytypes.Identity, id string
 	return Encode(entry)
 }
 
-// VerifyEntrySignature verifies the signature on an entry using KeyStore.
+// SigningPayload returns the bytes an entry signature covers: the CBOR
+// encoding of the entry with empty Key, Identity and Signature fields. It
+// covers ID, Payload, Next, Refs, Clock, V and, when present, PayloadData,
+// Meta and SigAlgo. Key and Identity are excluded because they describe the signer and are set after
+// signing; nil Next and Refs encode as empty lists. NewEntry signs these bytes
+// and verification recomputes them, so both sides sign and verify identical
+// bytes regardless of the entry's stored encoding.
+func SigningPayload(entry Entry) ([]byte, error) {
+	unsigned := Entry{
+		ID:          entry.ID,
+		Payload:     entry.Payload,
+		PayloadData: entry.PayloadData,
+		Next:        entry.Next,
+		Refs:        entry.Refs,
+		Clock:       entry.Clock,
+		V:           entry.V,
+		Meta:        entry.Meta,
+		SigAlgo:     entry.SigAlgo,
+	}
+	if unsigned.Next == nil {
+		unsigned.Next = []string{}
+	}
+	if unsigned.Refs == nil {
+		unsigned.Refs = []string{}
+	}
+
+	encoded, err := Encode(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return encoded.Bytes, nil
+}
+
+// VerifyEntrySignature verifies the signature on an entry against its Key,
+// with the scheme named by the entry's SigAlgo. Entries without a SigAlgo
+// predate it, so every registered signature scheme accepting the key is
+// tried; see RegisterSignatureScheme.
 func VerifyEntrySignature(ks *keystore.KeyStore, encodedEntry EncodedEntry) bool {
-	// Recreate the encodedEntry data without Signature, Key, and Identity fields
-	entryData := Entry{
-		ID:      encodedEntry.Entry.ID,
-		Payload: encodedEntry.Entry.Payload,
-		Next:    encodedEntry.Entry.Next,
-		Refs:    encodedEntry.Entry.Refs,
-		Clock:   encodedEntry.Entry.Clock,
-		V:       encodedEntry.Entry.V,
+	return verifyEntrySignature("", encodedEntry)
+}
+
+// verifyEntrySignature checks the entry signature with the scheme of the
+// given identity type, or with any matching scheme when the type is empty.
+// The entry's SigAlgo, which the writer chooses, selects the scheme only when
+// the type is empty or names the same algorithm; otherwise it is ignored, so
+// an entry cannot pick a weaker scheme than its identity's. A SigAlgo of
+// "DID" names no algorithm and is ignored too.
+func verifyEntrySignature(identityType string, encodedEntry EncodedEntry) bool {
+	if algo := encodedEntry.SigAlgo; algo != "" && algo != didIdentityType && (identityType == "" || sameSignatureAlgo(algo, identityType)) {
+		identityType = algo
 	}
 
-	// Ensure that Next and Refs are initialized as empty slices if nil
-	if entryData.Next == nil {
-		entryData.Next = []string{}
+	payload, err := SigningPayload(encodedEntry.Entry)
+	if err != nil {
+		log.Printf("Error encoding entry: %v\n", err)
+		return false
 	}
-	if entryData.Refs == nil {
-		entryData.Refs = []string{}
+
+	return verifySignature(identityType, encodedEntry.Key, payload, encodedEntry.Signature)
+}
+
+// VerifyEntryFull verifies the entry signature and that the entry's Key and
+// Identity fields both belong to the given writer identity. The identity hash
+// is recomputed from the identity record rather than trusted, so a valid
+// signature cannot be paired with an unrelated identity.
+func VerifyEntryFull(ks *keystore.KeyStore, e EncodedEntry, identity *identitytypes.Identity) error {
+	if identity == nil {
+		return errors.New("identity is required")
 	}
+	if !verifyEntrySignature(signatureType(identity), e) {
+		return fmt.Errorf("invalid signature for entry %s", e.Hash)
+	}
+	return verifyEntryIdentity(e, identity)
+}
 
-	// Encode the encodedEntry data without the Key, Identity, and Signature fields
-	reconstructedEncodedEntry := Encode(entryData)
+// verifyEntryIdentity checks that the entry's Key and Identity fields belong
+// to the identity.
+func verifyEntryIdentity(e EncodedEntry, identity *identitytypes.Identity) error {
+	if identity.PublicKey != e.Key {
+		return fmt.Errorf("entry %s key does not match identity %s", e.Hash, identity.ID)
+	}
 
-	pubKey, err := keystore.ReconstructPublicKeyFromHex(encodedEntry.Entry.Key)
+	hash, _, err := identitytypes.EncodeIdentity(*identity)
 	if err != nil {
-		log.Printf("Error reconstructing public key: %v\n", err)
-		return false
+		return fmt.Errorf("failed to encode identity: %w", err)
+	}
+	if hash != e.Identity {
+		return fmt.Errorf("entry %s identity %s does not match identity hash %s", e.Hash, e.Identity, hash)
+	}
+	return nil
+}
+
+// PublicKeyFromEntry parses the entry's Key field into an ECDSA public key,
+// so an entry can be verified without resolving its writer identity.
+func PublicKeyFromEntry(e EncodedEntry) (*ecdsa.PublicKey, error) {
+	if e.Key == "" {
+		return nil, errors.New("entry has no key")
 	}
 
-	// Verify the signature using the public key from the entry
-	verified, err := ks.VerifyMessage(*pubKey, reconstructedEncodedEntry.Bytes, encodedEntry.Signature)
-	return err == nil && verified
+	pubKey, err := keystore.ReconstructPublicKeyFromHex(e.Key)
+	if err != nil {
+		return nil, fmt.Errorf("malformed entry key: %w", err)
+	}
+	if err := checkOnCurve(pubKey); err != nil {
+		return nil, fmt.Errorf("malformed entry key: %w", err)
+	}
+	return pubKey, nil
+}
+
+// checkOnCurve rejects coordinates that are not a point on the P-256 curve.
+func checkOnCurve(pubKey *ecdsa.PublicKey) error {
+	point := make([]byte, 65)
+	point[0] = 4
+	pubKey.X.FillBytes(point[1:33])
+	pubKey.Y.FillBytes(point[33:])
+	_, err := ecdh.P256().NewPublicKey(point)
+	return err
 }
 
 // IsEntry checks if an object is a valid entry
 func IsEntry(entry Entry) bool {
-	return entry.ID != "" && entry.Payload != "" && entry.Clock.ID != "" && entry.Clock.Time > 0
+	return entry.ID != "" && (entry.Payload != "" || len(entry.PayloadData) > 0) && entry.Clock.ID != "" && entry.Clock.Time > 0
 }
 
 // IsEqual checks if two Entries are equal. Exclude Signature, Hash, and Bytes from the comparison since they can differ even if the Entries have the same content.
@@ -131,13 +282,28 @@ func IsEntry(entry Entry) bool {
 func IsEqual(entry1 EncodedEntry, entry2 EncodedEntry) bool {
 	return entry1.Entry.ID == entry2.Entry.ID &&
 		entry1.Entry.Payload == entry2.Entry.Payload &&
+		bytes.Equal(entry1.Entry.PayloadData, entry2.Entry.PayloadData) &&
 		EqualStringSlices(entry1.Entry.Next, entry2.Entry.Next) &&
 		EqualStringSlices(entry1.Entry.Refs, entry2.Entry.Refs) &&
 		entry1.Entry.Clock.ID == entry2.Entry.Clock.ID &&
 		entry1.Entry.Clock.Time == entry2.Entry.Clock.Time &&
 		entry1.Entry.V == entry2.Entry.V &&
+		equalMeta(entry1.Entry.Meta, entry2.Entry.Meta) &&
 		entry1.Entry.Key == entry2.Entry.Key &&
-		entry1.Entry.Identity == entry2.Entry.Identity
+		entry1.Entry.Identity == entry2.Entry.Identity &&
+		entry1.Entry.SigAlgo == entry2.Entry.SigAlgo
+}
+
+func equalMeta(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+	return true
 }
 
 func EqualStringSlices(a, b []string) bool {
@@ -152,80 +318,155 @@ func EqualStringSlices(a, b []string) bool {
 	return true
 }
 
-// Encode encodes the entry into CBOR and returns an EncodedEntry
-func Encode(entry Entry) EncodedEntry {
+// entryNode builds the IPLD map node an entry is encoded from.
+func entryNode(entry Entry) (datamodel.Node, error) {
 	// Create a basic map node for encoding
 	nb := basicnode.Prototype__Map{}.NewBuilder()
-	ma, err := nb.BeginMap(9)
+	fields := int64(9)
+	if len(entry.PayloadData) > 0 {
+		fields++
+	}
+	if len(entry.Meta) > 0 {
+		fields++
+	}
+	if entry.SigAlgo != "" {
+		fields++
+	}
+	ma, err := nb.BeginMap(fields)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to begin entry map: %w", err)
 	}
 
 	// Assemble each field using helper functions
 	if err := assembleStringField(ma, "ID", entry.ID); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble ID: %w", err)
 	}
 
 	if err := assembleStringField(ma, "payload", entry.Payload); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble payload: %w", err)
+	}
+
+	// Payload data is omitted when empty, like meta
+	if len(entry.PayloadData) > 0 {
+		if err := ma.AssembleKey().AssignString("data"); err != nil {
+			return nil, fmt.Errorf("failed to assemble data: %w", err)
+		}
+		if err := ma.AssembleValue().AssignBytes(entry.PayloadData); err != nil {
+			return nil, fmt.Errorf("failed to assemble data: %w", err)
+		}
 	}
 
 	if err := assembleStringList(ma, "next", entry.Next); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble next: %w", err)
 	}
 
 	if err := assembleStringList(ma, "refs", entry.Refs); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble refs: %w", err)
 	}
 
 	if err := assembleClock(ma, "clock", entry.Clock); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble clock: %w", err)
 	}
 
 	if err := assembleIntField(ma, "v", int64(entry.V)); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble v: %w", err)
+	}
+
+	// Meta is omitted when empty, so entries without it encode as before
+	if len(entry.Meta) > 0 {
+		if err := assembleStringMap(ma, "meta", entry.Meta); err != nil {
+			return nil, fmt.Errorf("failed to assemble meta: %w", err)
+		}
 	}
 
 	if err := assembleStringField(ma, "key", entry.Key); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble key: %w", err)
 	}
 
 	if err := assembleStringField(ma, "identity", entry.Identity); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble identity: %w", err)
+	}
+
+	// The signature algorithm is omitted when it is the default
+	if entry.SigAlgo != "" {
+		if err := assembleStringField(ma, "sigAlgo", entry.SigAlgo); err != nil {
+			return nil, fmt.Errorf("failed to assemble sigAlgo: %w", err)
+		}
 	}
 
 	if err := assembleStringField(ma, "sig", entry.Signature); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to assemble sig: %w", err)
 	}
 
 	// Finish assembling the map
 	if err := ma.Finish(); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to finish entry map: %w", err)
 	}
 
 	// Get the final built node
-	node := nb.Build()
+	return nb.Build(), nil
+}
 
-	// Encode to CBOR
-	var buf bytes.Buffer
-	if err := dagcbor.Encode(node, &buf); err != nil {
-		panic(err)
-	}
+// Encode encodes the entry into CBOR and returns an EncodedEntry
+func Encode(entry Entry) (EncodedEntry, error) {
+	return EncodeWithHash(entry, mh.SHA2_256)
+}
 
-	// Calculate CID for CBOR-encoded bytes
-	hash, err := mh.Sum(buf.Bytes(), mh.SHA2_256, -1)
+// EncodeWithHash encodes the entry like Encode, deriving its CID with the
+// multihash function identified by code, such as mh.SHA2_512 or mh.BLAKE3.
+// Codes without a registered hasher are rejected. Decode and the log derive
+// CIDs with SHA2-256, so entries hashed otherwise are for applications that
+// store and address them themselves.
+func EncodeWithHash(entry Entry, code uint64) (EncodedEntry, error) {
+	var buf bytes.Buffer
+	c, err := encodeTo(entry, &buf, code)
 	if err != nil {
-		panic(err)
+		return EncodedEntry{}, err
 	}
-	c := cid.NewCidV1(cid.DagCBOR, hash)
 
 	// Encode CID to base58btc for the hash
 	hashStr, err := c.StringOfBase(multibase.Base58BTC)
 	if err != nil {
-		panic(err)
+		return EncodedEntry{}, fmt.Errorf("failed to encode CID: %w", err)
+	}
+
+	return EncodedEntry{Entry: entry, Bytes: buf.Bytes(), CID: c, Hash: hashStr}, nil
+}
+
+// EncodeTo streams the DAG-CBOR encoding of the entry to w and returns its
+// CID, hashing the bytes as they are written so callers persisting large
+// entries need not hold the whole encoding in memory. The bytes and CID
+// are those Encode produces.
+func EncodeTo(entry Entry, w io.Writer) (cid.Cid, error) {
+	return encodeTo(entry, w, mh.SHA2_256)
+}
+
+// encodeTo streams the entry to w, hashing it with the multihash function
+// identified by code.
+func encodeTo(entry Entry, w io.Writer, code uint64) (cid.Cid, error) {
+	// The identity "hash" would embed the entry in its CID
+	if code == mh.IDENTITY {
+		return cid.Undef, errors.New("unsupported hash function: identity")
+	}
+	hasher, err := mh.GetHasher(code)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("unsupported hash function 0x%x: %w", code, err)
 	}
 
-	return EncodedEntry{Entry: entry, Bytes: buf.Bytes(), CID: c, Hash: hashStr}
+	node, err := entryNode(entry)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if err := dagcbor.Encode(node, io.MultiWriter(w, hasher)); err != nil {
+		return cid.Undef, fmt.Errorf("failed to encode entry to CBOR: %w", err)
+	}
+
+	// Calculate the CID from the streamed digest
+	hash, err := mh.Encode(hasher.Sum(nil), code)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to hash entry: %w", err)
+	}
+	return cid.NewCidV1(cid.DagCBOR, hash), nil
 }
 
 // Decode decodes CBOR-encoded data into an EncodedEntry struct
@@ -287,6 +528,27 @@ func Decode(encodedData []byte) (EncodedEntry, error) {
 		return EncodedEntry{}, err
 	}
 
+	// Payload data, meta and the signature algorithm are optional, and
+	// absent from entries older than the version that added them
+	if sigAlgoNode, err := node.LookupByString("sigAlgo"); err == nil {
+		if entry.SigAlgo, err = sigAlgoNode.AsString(); err != nil {
+			return EncodedEntry{}, err
+		}
+	}
+	if dataNode, err := node.LookupByString("data"); err == nil {
+		if entry.PayloadData, err = dataNode.AsBytes(); err != nil {
+			return EncodedEntry{}, err
+		}
+	}
+	if metaNode, err := node.LookupByString("meta"); err == nil {
+		if entry.Meta, err = stringMapFromNode(metaNode); err != nil {
+			return EncodedEntry{}, err
+		}
+		if err := validateMeta(entry.Meta); err != nil {
+			return EncodedEntry{}, err
+		}
+	}
+
 	// Calculate the CID for CBOR-encoded bytes
 	hash, err := mh.Sum(encodedData, mh.SHA2_256, -1)
 	if err != nil {
@@ -307,6 +569,9 @@ func Decode(encodedData []byte) (EncodedEntry, error) {
 }
 
 func assembleStringField(ma datamodel.MapAssembler, key string, value string) error {
+	if !utf8.ValidString(value) {
+		return errors.New("DAG-CBOR strings must be valid UTF-8")
+	}
 	if err := ma.AssembleKey().AssignString(key); err != nil {
 		return err
 	}
@@ -335,6 +600,9 @@ func assembleStringList(ma datamodel.MapAssembler, key string, values []string)
 		return err
 	}
 	for _, v := range values {
+		if !utf8.ValidString(v) {
+			return errors.New("DAG-CBOR strings must be valid UTF-8")
+		}
 		if err := la.AssembleValue().AssignString(v); err != nil {
 			return err
 		}
@@ -345,6 +613,31 @@ func assembleStringList(ma datamodel.MapAssembler, key string, values []string)
 	return nil
 }
 
+func assembleStringMap(ma datamodel.MapAssembler, key string, values map[string]string) error {
+	if err := ma.AssembleKey().AssignString(key); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	va, err := ma.AssembleValue().BeginMap(int64(len(keys)))
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := assembleStringField(va, k, values[k]); err != nil {
+			return err
+		}
+	}
+	if err := va.Finish(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func assembleClock(ma datamodel.MapAssembler, key string, clock Clock) error {
 	if err := ma.AssembleKey().AssignString(key); err != nil {
 		return err
@@ -386,7 +679,13 @@ func getStringList(node datamodel.Node, key string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if listNode.Kind() != datamodel.Kind_List {
+		return nil, fmt.Errorf("%s is not a list", key)
+	}
 	length := listNode.Length()
+	if length > int64(MaxEntryReferences) {
+		return nil, fmt.Errorf("%s has %d references, exceeding the limit of %d", key, length, MaxEntryReferences)
+	}
 
 	var list []string
 	for i := int64(0); i < length; i++ {
@@ -403,6 +702,30 @@ func getStringList(node datamodel.Node, key string) ([]string, error) {
 	return list, nil
 }
 
+func stringMapFromNode(mapNode datamodel.Node) (map[string]string, error) {
+	if mapNode.Kind() != datamodel.Kind_Map {
+		return nil, errors.New("meta is not a map")
+	}
+	values := make(map[string]string, mapNode.Length())
+	it := mapNode.MapIterator()
+	for !it.Done() {
+		k, v, err := it.Next()
+		if err != nil {
+			return nil, err
+		}
+		key, err := k.AsString()
+		if err != nil {
+			return nil, err
+		}
+		value, err := v.AsString()
+		if err != nil {
+			return nil, fmt.Errorf("meta %q is not a string: %w", key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
 // Helper function to set a default clock if not provided
 func clockOrDefault(clock Clock, identity *identitytypes.Identity) Clock {
 	if clock.ID == "" {
@@ -2,9 +2,12 @@ package oplog
 
 import (
 	"bytes"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
 	"github.com/stretchr/testify/require"
 	"orbitdb/go-orbitdb/keystore"
 	"orbitdb/go-orbitdb/storage"
+	"strings"
 	"testing"
 
 	"orbitdb/go-orbitdb/identities/identitytypes"
@@ -30,10 +33,26 @@ func setupTestKeyStoreAndIdentity(t *testing.T) (*keystore.KeyStore, *identityty
 	return ks, identity
 }
 
+// mustNewEntry creates an entry, failing the test on error.
+func mustNewEntry(t *testing.T, ks *keystore.KeyStore, identity *identitytypes.Identity, id string, payload string, clock Clock, next []string, refs []string) EncodedEntry {
+	t.Helper()
+	entry, err := NewEntry(ks, identity, id, payload, clock, next, refs)
+	require.NoError(t, err, "Failed to create entry")
+	return entry
+}
+
+// mustEncode encodes an entry, failing the test on error.
+func mustEncode(t *testing.T, entry Entry) EncodedEntry {
+	t.Helper()
+	encoded, err := Encode(entry)
+	require.NoError(t, err, "Failed to encode entry")
+	return encoded
+}
+
 func TestNewEntry(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
 	clock := Clock{ID: "test-clock", Time: 1}
-	entry := NewEntry(ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	entry := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
 
 	if entry.ID != "entry-ID" {
 		t.Errorf("Expected entry ID to be 'entry-ID', got '%s'", entry.ID)
@@ -55,7 +74,7 @@ func TestNewEntry(t *testing.T) {
 func TestVerifyEntrySignature(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
 	clock := Clock{ID: "test-clock", Time: 1}
-	entry := NewEntry(ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	entry := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
 
 	isValid := VerifyEntrySignature(ks, entry)
 	if !isValid {
@@ -70,6 +89,97 @@ func TestVerifyEntrySignature(t *testing.T) {
 	}
 }
 
+func TestSigningPayload(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := Clock{ID: identity.ID, Time: 1}
+
+	// Sign the payload of an unsigned entry, then attach the signer fields
+	entry := Entry{ID: "entry-ID", Payload: "payload-data", Clock: clock, V: EntryVersion}
+	payload, err := SigningPayload(entry)
+	if err != nil {
+		t.Fatalf("Failed to build signing payload: %v", err)
+	}
+	signature, err := ks.SignMessage(identity.ID, payload)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	entry.Key, entry.Identity, entry.Signature = identity.PublicKey, identity.Hash, signature
+
+	// The stored bytes include the signature, but re-encoding yields the same payload
+	encoded := mustEncode(t, entry)
+	if bytes.Equal(encoded.Bytes, payload) {
+		t.Fatal("Expected stored bytes to differ from the signing payload")
+	}
+	decoded, err := Decode(encoded.Bytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	again, err := SigningPayload(decoded.Entry)
+	if err != nil {
+		t.Fatalf("Failed to build signing payload: %v", err)
+	}
+	if !bytes.Equal(again, payload) {
+		t.Error("Expected the signing payload to be identical after re-encoding")
+	}
+	if !VerifyEntrySignature(ks, decoded) {
+		t.Error("Expected re-encoded entry to verify")
+	}
+}
+
+func TestPublicKeyFromEntry(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := Clock{ID: "test-clock", Time: 1}
+	entry := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+
+	pubKey, err := PublicKeyFromEntry(entry)
+	if err != nil {
+		t.Fatalf("Expected to parse entry key, got %v", err)
+	}
+	if keystore.PublicKeyToHex(pubKey) != identity.PublicKey {
+		t.Errorf("Expected key %s, got %s", identity.PublicKey, keystore.PublicKeyToHex(pubKey))
+	}
+
+	// A keystore holding no keys can verify the entry using only its embedded key
+	empty := keystore.NewKeyStore(storage.NewMemoryStorage())
+	if !VerifyEntrySignature(empty, entry) {
+		t.Error("Expected entry to verify using its embedded key")
+	}
+
+	malformed := []string{"", "not-hex", "abcd", strings.Repeat("00", 64)}
+	for _, key := range malformed {
+		bad := entry
+		bad.Key = key
+		if _, err := PublicKeyFromEntry(bad); err == nil {
+			t.Errorf("Expected error for malformed key %q", key)
+		}
+	}
+}
+
+func TestVerifyEntryFull(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := Clock{ID: "test-clock", Time: 1}
+	entry := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+
+	if err := VerifyEntryFull(ks, entry, identity); err != nil {
+		t.Fatalf("Expected entry to verify against its identity, got %v", err)
+	}
+
+	// A validly signed entry paired with an unrelated identity record is rejected
+	other, err := providers.NewPublicKeyProvider(ks).CreateIdentity("other-ID")
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	if err := VerifyEntryFull(ks, entry, other); err == nil {
+		t.Error("Expected mismatched key and identity to be rejected")
+	}
+
+	mismatched := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	mismatched.Identity = other.Hash
+	if err := VerifyEntryFull(ks, mismatched, identity); err == nil {
+		t.Error("Expected entry with a foreign identity hash to be rejected")
+	}
+}
+
 func TestIsEntry(t *testing.T) {
 	validEntry := Entry{
 		ID:      "entry-ID",
@@ -91,8 +201,8 @@ func TestIsEqual(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
 	clock := Clock{ID: "test-clock", Time: 1}
 
-	entry1 := NewEntry(ks, identity, "entry-ID", "payload-data", clock, nil, nil)
-	entry2 := NewEntry(ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	entry1 := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	entry2 := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
 
 	// Both Entries have identical content, so they should have the same serialized bytes
 	if !IsEqual(entry1, entry2) {
@@ -100,7 +210,7 @@ func TestIsEqual(t *testing.T) {
 	}
 
 	// Create an entry with different content and check equality
-	entry3 := NewEntry(ks, identity, "entry-ID", "different-payload", clock, nil, nil)
+	entry3 := mustNewEntry(t, ks, identity, "entry-ID", "different-payload", clock, nil, nil)
 	if IsEqual(entry1, entry3) {
 		t.Error("Expected Entries with different content to not be equal")
 	}
@@ -113,7 +223,7 @@ func TestEncode(t *testing.T) {
 		Clock:   Clock{ID: "test-clock", Time: 1},
 	}
 
-	encodedEntry := Encode(entry)
+	encodedEntry := mustEncode(t, entry)
 
 	if encodedEntry.CID.String() == "" {
 		t.Error("Expected CID to be generated, but it was empty")
@@ -123,20 +233,154 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeTo(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entry, err := NewEntryWithMeta(ks, identity, "entry-ID", strings.Repeat("payload-data", 1000), NewClock(identity.ID, 1), nil, nil, map[string]string{"content-type": "text/plain"})
+	require.NoError(t, err, "Failed to create entry")
+
+	var buf bytes.Buffer
+	c, err := EncodeTo(entry.Entry, &buf)
+	require.NoError(t, err, "Failed to stream entry")
+
+	if !bytes.Equal(buf.Bytes(), entry.Bytes) {
+		t.Error("Expected EncodeTo to write the bytes Encode produces")
+	}
+	if !c.Equals(entry.CID) {
+		t.Errorf("Expected CID %s, got %s", entry.CID, c)
+	}
+}
+
+func TestEncodeWithHash(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entry, err := NewEntry(ks, identity, "entry-ID", "payload-data", NewClock(identity.ID, 1), nil, nil)
+	require.NoError(t, err, "Failed to create entry")
+
+	blake2b256 := uint64(mh.BLAKE2B_MIN + 31)
+	sha, err := EncodeWithHash(entry.Entry, mh.SHA2_256)
+	require.NoError(t, err, "Failed to encode with SHA2-256")
+	blake, err := EncodeWithHash(entry.Entry, blake2b256)
+	require.NoError(t, err, "Failed to encode with BLAKE2b-256")
+
+	if !sha.CID.Equals(entry.CID) {
+		t.Errorf("Expected SHA2-256 to match Encode, got %s and %s", sha.CID, entry.CID)
+	}
+	if sha.CID.Equals(blake.CID) || sha.Hash == blake.Hash {
+		t.Error("Expected different hash functions to produce different CIDs")
+	}
+	if !bytes.Equal(sha.Bytes, blake.Bytes) {
+		t.Error("Expected the hash function not to change the encoded bytes")
+	}
+
+	for code, encoded := range map[uint64]EncodedEntry{mh.SHA2_256: sha, blake2b256: blake} {
+		parsed, err := cid.Decode(encoded.Hash)
+		require.NoError(t, err, "Failed to parse CID")
+		decoded, err := mh.Decode(parsed.Hash())
+		require.NoError(t, err, "Failed to decode multihash")
+		if decoded.Code != code || decoded.Length != 32 {
+			t.Errorf("Expected a 32-byte multihash with code 0x%x, got 0x%x (%d bytes)", code, decoded.Code, decoded.Length)
+		}
+	}
+
+	if _, err := EncodeWithHash(entry.Entry, 0x9999); err == nil {
+		t.Error("Expected an unregistered hash code to be rejected")
+	}
+	if _, err := EncodeWithHash(entry.Entry, mh.IDENTITY); err == nil {
+		t.Error("Expected the identity hash to be rejected")
+	}
+}
+
+func TestContentID(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := NewClock(identity.ID, 1)
+
+	// The same author signing the same entry twice gets two blocks but one ID
+	first := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, nil, nil)
+	second := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, []string{}, nil)
+	if first.Hash == second.Hash {
+		t.Fatal("Expected independently signed entries to have different hashes")
+	}
+
+	firstID, err := ContentID(first.Entry)
+	if err != nil {
+		t.Fatalf("Failed to compute content ID: %v", err)
+	}
+	secondID, err := ContentID(second.Entry)
+	if err != nil {
+		t.Fatalf("Failed to compute content ID: %v", err)
+	}
+	if firstID != secondID {
+		t.Fatalf("Expected equal content IDs, got %s and %s", firstID, secondID)
+	}
+	if firstID == first.Hash {
+		t.Fatal("Expected content ID to differ from the signed block hash")
+	}
+
+	// The content ID survives a round trip through storage bytes
+	decoded, err := Decode(first.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if decodedID, _ := ContentID(decoded.Entry); decodedID != firstID {
+		t.Fatalf("Expected decoded content ID %s, got %s", firstID, decodedID)
+	}
+
+	// Entry identity is per author
+	other, err := providers.NewPublicKeyProvider(ks).CreateIdentity("other-ID")
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	foreign := mustNewEntry(t, ks, other, "entry-ID", "payload-data", clock, nil, nil)
+	if foreignID, _ := ContentID(foreign.Entry); foreignID == firstID {
+		t.Fatal("Expected different authors to produce different content IDs")
+	}
+}
+
+func TestEncodeInvalidEntry(t *testing.T) {
+	// DAG-CBOR strings must be valid UTF-8
+	invalid := []Entry{
+		{ID: "entry-ID", Payload: "\xff\xfe", Clock: Clock{ID: "test-clock", Time: 1}},
+		{ID: "entry-ID", Payload: "payload-data", Next: []string{"\xff"}, Clock: Clock{ID: "test-clock", Time: 1}},
+		{ID: "entry-ID", Payload: "payload-data", Clock: Clock{ID: "\xff", Time: 1}},
+	}
+
+	for _, entry := range invalid {
+		if _, err := Encode(entry); err == nil {
+			t.Errorf("Expected error encoding invalid entry %+v", entry)
+		}
+	}
+}
+
+func TestNewEntryInvalid(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := NewClock(identity.ID, 1)
+
+	if _, err := NewEntry(ks, nil, "entry-ID", "payload-data", clock, nil, nil); err == nil {
+		t.Error("Expected error creating an entry without identity")
+	}
+	if _, err := NewEntry(ks, identity, "entry-ID", "", clock, nil, nil); err == nil {
+		t.Error("Expected error creating an entry without payload")
+	}
+	if _, err := NewEntry(ks, identity, "entry-ID", "\xff\xfe", clock, nil, nil); err == nil {
+		t.Error("Expected error creating an entry that cannot be encoded")
+	}
+}
+
 func TestDecode(t *testing.T) {
 	// Create a sample entry
 	entry := Entry{
 		ID:        "entry-ID",
 		Payload:   "payload-data",
 		Clock:     Clock{ID: "test-clock", Time: 1},
-		V:         2,
+		V:         EntryVersion,
 		Key:       "test-key",
 		Identity:  "test-identity",
 		Signature: "test-signature",
 	}
 
 	// Encode the entry
-	encodedEntry := Encode(entry)
+	encodedEntry := mustEncode(t, entry)
 
 	// Decode the encoded bytes back into an EncodedEntry
 	decodedEntry, err := Decode(encodedEntry.Bytes)
@@ -175,3 +419,122 @@ func TestDecode(t *testing.T) {
 		t.Errorf("Encoded bytes do not match decoded bytes")
 	}
 }
+
+func TestDecodeRoundTrip(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := Clock{ID: identity.ID, Time: 3}
+	next := []string{"next-b", "next-a"}
+	refs := []string{"ref-a", "ref-b", "ref-c"}
+	encodedEntry := mustNewEntry(t, ks, identity, "entry-ID", "payload-data", clock, next, refs)
+
+	decodedEntry, err := Decode(encodedEntry.Bytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if !decodedEntry.CID.Equals(encodedEntry.CID) {
+		t.Errorf("Expected CID %s, got %s", encodedEntry.CID, decodedEntry.CID)
+	}
+	if decodedEntry.Hash != encodedEntry.Hash {
+		t.Errorf("Expected hash %s, got %s", encodedEntry.Hash, decodedEntry.Hash)
+	}
+	if decodedEntry.Clock != clock {
+		t.Errorf("Expected clock %+v, got %+v", clock, decodedEntry.Clock)
+	}
+	if strings.Join(decodedEntry.Next, ",") != strings.Join(encodedEntry.Next, ",") {
+		t.Errorf("Expected next %v, got %v", encodedEntry.Next, decodedEntry.Next)
+	}
+	if strings.Join(decodedEntry.Refs, ",") != strings.Join(encodedEntry.Refs, ",") {
+		t.Errorf("Expected refs %v, got %v", encodedEntry.Refs, decodedEntry.Refs)
+	}
+	if !IsEqual(decodedEntry, encodedEntry) {
+		t.Error("Expected decoded entry to equal the original")
+	}
+	if !VerifyEntrySignature(ks, decodedEntry) {
+		t.Error("Expected decoded entry signature to verify")
+	}
+}
+
+func TestEntryMetaRoundTrip(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	clock := Clock{ID: identity.ID, Time: 1}
+	meta := map[string]string{"content-type": "text/plain", "source": "test"}
+
+	entry, err := NewEntryWithMeta(ks, identity, "entry-ID", "payload-data", clock, nil, nil, meta)
+	if err != nil {
+		t.Fatalf("Failed to create entry: %v", err)
+	}
+
+	decoded, err := Decode(entry.Bytes)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.V != EntryVersion || !equalMeta(decoded.Meta, meta) {
+		t.Errorf("Expected version %d with meta %v, got %d with %v", EntryVersion, meta, decoded.V, decoded.Meta)
+	}
+	if decoded.Hash != entry.Hash || !VerifyEntrySignature(ks, decoded) {
+		t.Error("Expected decoded entry to keep its hash and signature")
+	}
+
+	// Altering meta invalidates the signature
+	tampered := decoded
+	tampered.Meta = map[string]string{"content-type": "text/html", "source": "test"}
+	if VerifyEntrySignature(ks, tampered) {
+		t.Error("Expected altered meta to fail signature verification")
+	}
+	tampered.Meta = nil
+	if VerifyEntrySignature(ks, tampered) {
+		t.Error("Expected removed meta to fail signature verification")
+	}
+
+	// Entries without meta, including older versions, encode without the field
+	older := mustEncode(t, Entry{ID: "entry-ID", Payload: "payload-data", Clock: clock, V: 2})
+	if decoded, err := Decode(older.Bytes); err != nil || decoded.Meta != nil || decoded.V != 2 {
+		t.Errorf("Expected a version 2 entry to decode without meta, got %+v (%v)", decoded.Entry, err)
+	}
+
+	oversized := map[string]string{"blob": strings.Repeat("x", MaxEntryMetaSize)}
+	if _, err := NewEntryWithMeta(ks, identity, "entry-ID", "payload-data", clock, nil, nil, oversized); err == nil {
+		t.Error("Expected oversized meta to be rejected")
+	}
+}
+
+func TestLog_AppendWithMeta(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	entry, err := log.AppendWithMeta("payload", map[string]string{"source": "test"})
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	retrieved, err := log.Get(entry.Hash)
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if retrieved.Meta["source"] != "test" {
+		t.Errorf("Expected meta to be stored, got %v", retrieved.Meta)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	if _, err := Decode([]byte("not cbor")); err == nil {
+		t.Error("Expected error decoding malformed bytes")
+	}
+	if _, err := Decode(nil); err == nil {
+		t.Error("Expected error decoding empty bytes")
+	}
+
+	// A CBOR map that does not follow the entry schema
+	identity := identitytypes.Identity{ID: "id", PublicKey: "key", Signatures: map[string]string{}, Type: "type"}
+	_, data, err := identitytypes.EncodeIdentity(identity)
+	if err != nil {
+		t.Fatalf("Failed to encode identity: %v", err)
+	}
+	if _, err := Decode(data); err == nil {
+		t.Error("Expected error decoding bytes with a different schema")
+	}
+}
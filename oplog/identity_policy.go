@@ -0,0 +1,114 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// IdentityResolver looks up a writer identity by its hash.
+type IdentityResolver func(hash string) (*identitytypes.Identity, error)
+
+// UnresolvedIdentityPolicy decides what happens to a joined entry whose
+// writer identity cannot be resolved.
+type UnresolvedIdentityPolicy int
+
+const (
+	// Drop rejects the entry.
+	Drop UnresolvedIdentityPolicy = iota
+	// Quarantine holds the entry aside until RecheckQuarantine can resolve it.
+	Quarantine
+	// FetchThenVerify looks the identity up with the identity fetcher and
+	// drops the entry if that fails too.
+	FetchThenVerify
+)
+
+var (
+	// ErrIdentityUnresolved is returned when a joined entry is dropped because
+	// its identity could not be resolved.
+	ErrIdentityUnresolved = errors.New("entry identity could not be resolved")
+	// ErrEntryQuarantined is returned when a joined entry is quarantined.
+	ErrEntryQuarantined = errors.New("entry quarantined")
+)
+
+// resolveIdentity resolves and verifies the writer identity of a joined entry.
+// It returns a nil identity when the log has no identity resolver.
+func (l *Log) resolveIdentity(entry *EncodedEntry) (*identitytypes.Identity, error) {
+	if l.resolve == nil {
+		return nil, nil
+	}
+
+	identity, err := l.resolve(entry.Identity)
+	if err != nil || identity == nil {
+		switch l.policy {
+		case Quarantine:
+			if err := l.quarantine.Put(entry.Hash, entry.Bytes); err != nil {
+				return nil, fmt.Errorf("failed to quarantine entry %s: %w", entry.Hash, err)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrEntryQuarantined, entry.Hash)
+		case FetchThenVerify:
+			if l.fetch != nil {
+				identity, err = l.fetch(entry.Identity)
+			}
+		}
+		if err != nil || identity == nil {
+			return nil, fmt.Errorf("%w: %s", ErrIdentityUnresolved, entry.Hash)
+		}
+	}
+
+	if err := VerifyEntryFull(l.keystore, *entry, identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// Quarantined returns the entries currently held in quarantine.
+func (l *Log) Quarantined() ([]EncodedEntry, error) {
+	ch, err := l.quarantine.Iterator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate over quarantine: %w", err)
+	}
+
+	var entries []EncodedEntry
+	for kv := range ch {
+		entry, err := Decode([]byte(kv[1]))
+		if err != nil {
+			fmt.Printf("Warning: Skipping invalid quarantined entry with error: %s\n", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// RecheckQuarantine retries every quarantined entry, joining and releasing
+// those whose identity now resolves. It returns the number of entries joined.
+func (l *Log) RecheckQuarantine() (int, error) {
+	entries, err := l.Quarantined()
+	if err != nil {
+		return 0, err
+	}
+
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	joined := 0
+	var errs []error
+	for i := range entries {
+		entry := &entries[i]
+		if err := l.quarantine.Delete(entry.Hash); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// Entries that still cannot be resolved are quarantined again
+		if err := l.JoinEntry(entry, make(map[string]bool)); err != nil {
+			if !errors.Is(err, ErrEntryQuarantined) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		joined++
+	}
+	return joined, errors.Join(errs...)
+}
@@ -0,0 +1,101 @@
+package oplog
+
+import (
+	"errors"
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// mapResolver resolves identities from a fixed set.
+type mapResolver map[string]*identitytypes.Identity
+
+func (m mapResolver) resolve(hash string) (*identitytypes.Identity, error) {
+	if identity, ok := m[hash]; ok {
+		return identity, nil
+	}
+	return nil, errors.New("identity not found")
+}
+
+func TestLog_UnresolvedIdentityPolicy(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	writer, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	entry, err := writer.Append("payload")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	newReader := func(t *testing.T, known mapResolver, opts ...LogOption) *Log {
+		opts = append([]LogOption{WithIdentityResolver(known.resolve)}, opts...)
+		reader, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, opts...)
+		if err != nil {
+			t.Fatalf("Failed to create log: %v", err)
+		}
+		return reader
+	}
+	stored := func(l *Log) bool {
+		_, err := l.Entries.Get(entry.Hash)
+		return err == nil
+	}
+
+	t.Run("Drop", func(t *testing.T) {
+		reader := newReader(t, mapResolver{}, WithUnresolvedIdentityPolicy(Drop, nil))
+		if err := reader.JoinEntry(entry, make(map[string]bool)); !errors.Is(err, ErrIdentityUnresolved) {
+			t.Fatalf("Expected ErrIdentityUnresolved, got %v", err)
+		}
+		if stored(reader) {
+			t.Error("Expected dropped entry not to be stored")
+		}
+	})
+
+	t.Run("Quarantine", func(t *testing.T) {
+		known := mapResolver{}
+		reader := newReader(t, known, WithUnresolvedIdentityPolicy(Quarantine, nil))
+		if err := reader.JoinEntry(entry, make(map[string]bool)); !errors.Is(err, ErrEntryQuarantined) {
+			t.Fatalf("Expected ErrEntryQuarantined, got %v", err)
+		}
+		if stored(reader) {
+			t.Error("Expected quarantined entry not to be stored in the log")
+		}
+		quarantined, err := reader.Quarantined()
+		if err != nil || len(quarantined) != 1 || quarantined[0].Hash != entry.Hash {
+			t.Fatalf("Expected the entry in quarantine, got %v (%v)", quarantined, err)
+		}
+
+		// Once the identity is known the entry is released into the log
+		known[identity.Hash] = identity
+		joined, err := reader.RecheckQuarantine()
+		if err != nil || joined != 1 {
+			t.Fatalf("Expected 1 entry to be joined, got %d (%v)", joined, err)
+		}
+		if !stored(reader) {
+			t.Error("Expected released entry to be stored")
+		}
+		if quarantined, _ := reader.Quarantined(); len(quarantined) != 0 {
+			t.Errorf("Expected empty quarantine, got %d entries", len(quarantined))
+		}
+	})
+
+	t.Run("FetchThenVerify", func(t *testing.T) {
+		fetched := mapResolver{identity.Hash: identity}
+		reader := newReader(t, mapResolver{},
+			WithUnresolvedIdentityPolicy(FetchThenVerify, nil), WithIdentityFetcher(fetched.resolve))
+		if err := reader.JoinEntry(entry, make(map[string]bool)); err != nil {
+			t.Fatalf("Expected fetched identity to verify, got %v", err)
+		}
+		if !stored(reader) {
+			t.Error("Expected verified entry to be stored")
+		}
+
+		failing := newReader(t, mapResolver{},
+			WithUnresolvedIdentityPolicy(FetchThenVerify, nil), WithIdentityFetcher(mapResolver{}.resolve))
+		if err := failing.JoinEntry(entry, make(map[string]bool)); !errors.Is(err, ErrIdentityUnresolved) {
+			t.Fatalf("Expected ErrIdentityUnresolved after a failed fetch, got %v", err)
+		}
+	})
+}
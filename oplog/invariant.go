@@ -0,0 +1,63 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrJoinInvariant is reported when a join leaves the log with fewer entries
+// than it had before, or without an entry it already held.
+var ErrJoinInvariant = errors.New("join invariant violated")
+
+// WithJoinInvariant checks after every Join, JoinAll and JoinEntry that no
+// previously stored entry has been lost. The check lists the whole entry
+// store twice per join, so it is meant for tests and debugging. Violations
+// are passed to report, or printed as warnings when report is nil; the join
+// itself is not failed.
+func WithJoinInvariant(report func(error)) LogOption {
+	return func(l *Log) {
+		if report == nil {
+			report = func(err error) {
+				fmt.Printf("Warning: %s\n", err)
+			}
+		}
+		l.invariant = report
+	}
+}
+
+// entrySet returns the hashes currently in the entry store. It is nil when
+// the invariant check is disabled.
+func (l *Log) entrySet() map[string]bool {
+	if l.invariant == nil {
+		return nil
+	}
+	known := make(map[string]bool)
+	ch, err := l.Entries.Iterator()
+	if err != nil {
+		l.invariant(fmt.Errorf("%w: failed to list entries: %s", ErrJoinInvariant, err))
+		return nil
+	}
+	for kv := range ch {
+		known[kv[0]] = true
+	}
+	return known
+}
+
+// checkJoinInvariant reports entries in before that are no longer stored.
+func (l *Log) checkJoinInvariant(op string, before map[string]bool) {
+	if before == nil {
+		return
+	}
+	after := l.entrySet()
+	if after == nil {
+		return
+	}
+	if len(after) < len(before) {
+		l.invariant(fmt.Errorf("%w: %s reduced the log from %d to %d entries", ErrJoinInvariant, op, len(before), len(after)))
+	}
+	for hash := range before {
+		if !after[hash] {
+			l.invariant(fmt.Errorf("%w: %s lost entry %s", ErrJoinInvariant, op, hash))
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+// droppingStorage deletes the first stored key once more than limit keys are
+// stored, simulating a bug that loses entries.
+type droppingStorage struct {
+	storage.Storage
+	limit int
+	keys  []string
+}
+
+func (s *droppingStorage) Put(key string, value []byte) error {
+	if err := s.Storage.Put(key, value); err != nil {
+		return err
+	}
+	s.keys = append(s.keys, key)
+	if len(s.keys) > s.limit {
+		return s.Storage.Delete(s.keys[0])
+	}
+	return nil
+}
+
+func TestLog_JoinInvariantMonotonic(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	var violations []error
+	report := func(err error) { violations = append(violations, err) }
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithJoinInvariant(report))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	other, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	previous := 0
+	for i := 0; i < 20; i++ {
+		if _, err := other.Append(fmt.Sprintf("other %d", i)); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+		if i%3 == 0 {
+			if _, err := log.Append(fmt.Sprintf("local %d", i)); err != nil {
+				t.Fatalf("Failed to append: %v", err)
+			}
+		}
+		if err := log.Join(other); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+
+		entries, err := log.Values()
+		if err != nil {
+			t.Fatalf("Failed to read values: %v", err)
+		}
+		if len(entries) < previous {
+			t.Fatalf("Entry count decreased from %d to %d after join %d", previous, len(entries), i)
+		}
+		previous = len(entries)
+	}
+
+	if len(violations) != 0 {
+		t.Errorf("Expected no invariant violations, got %v", violations)
+	}
+}
+
+func TestLog_JoinInvariantReportsLoss(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	var violations []error
+	report := func(err error) { violations = append(violations, err) }
+
+	log, err := NewLog("test-log", identity, &droppingStorage{Storage: storage.NewMemoryStorage(), limit: 2}, ks, WithJoinInvariant(report))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	other, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := log.Append(fmt.Sprintf("local %d", i)); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+	if _, err := other.Append("other"); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if err := log.Join(other); err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+
+	if len(violations) == 0 {
+		t.Fatal("Expected the lost entry to be reported")
+	}
+	for _, violation := range violations {
+		if !errors.Is(violation, ErrJoinInvariant) {
+			t.Errorf("Expected ErrJoinInvariant, got %v", violation)
+		}
+	}
+}
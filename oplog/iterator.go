@@ -0,0 +1,141 @@
+package oplog
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// IteratorOptions bounds a Log.Iterator traversal. Bounds are entry hashes:
+// LT and LTE choose where the traversal starts, GT and GTE where it stops,
+// with the E variants including the bound entry itself. Amount limits the
+// number of emitted entries; -1 means unbounded and 0 emits nothing.
+type IteratorOptions struct {
+	GT     string
+	GTE    string
+	LT     string
+	LTE    string
+	Amount int
+}
+
+// Iterator walks the log backwards from its heads, or from the LT/LTE bound,
+// following Next links and emitting entries in reverse clock order until it
+// reaches the GT/GTE bound or has emitted Amount entries. When a Next link is
+// missing from storage, traversal continues through the entry's Refs.
+// Unknown bounds are reported before traversal starts. Callers must drain
+// the channel.
+func (l *Log) Iterator(opts IteratorOptions) (<-chan EncodedEntry, error) {
+	for _, bound := range []string{opts.GT, opts.GTE, opts.LT, opts.LTE} {
+		if bound == "" {
+			continue
+		}
+		if _, err := l.Get(bound); err != nil {
+			return nil, fmt.Errorf("unknown iterator bound %s: %w", bound, err)
+		}
+	}
+
+	// Choose the starting entries
+	var start []EncodedEntry
+	switch {
+	case opts.LTE != "":
+		entry, _ := l.Get(opts.LTE)
+		start = []EncodedEntry{*entry}
+	case opts.LT != "":
+		entry, _ := l.Get(opts.LT)
+		for _, hash := range entry.Next {
+			if next, err := l.Get(hash); err == nil {
+				start = append(start, *next)
+			}
+		}
+	default:
+		heads, err := l.Heads()
+		if err != nil {
+			return nil, err
+		}
+		start = heads
+	}
+
+	out := make(chan EncodedEntry)
+	go func() {
+		defer close(out)
+		if opts.Amount == 0 {
+			return
+		}
+
+		queue := &entryQueue{}
+		queued := make(map[string]bool)
+		push := func(entry EncodedEntry) {
+			if !queued[entry.Hash] {
+				queued[entry.Hash] = true
+				heap.Push(queue, entry)
+			}
+		}
+		for _, entry := range start {
+			push(entry)
+		}
+
+		emitted := 0
+		for queue.Len() > 0 {
+			entry := heap.Pop(queue).(EncodedEntry)
+
+			if entry.Hash == opts.GT {
+				return
+			}
+			out <- entry
+			emitted++
+			if entry.Hash == opts.GTE || (opts.Amount > 0 && emitted >= opts.Amount) {
+				return
+			}
+
+			gap := false
+			for _, hash := range entry.Next {
+				if queued[hash] {
+					continue
+				}
+				next, err := l.Get(hash)
+				if err != nil {
+					fmt.Printf("Warning: Failed to load next entry %s: %s\n", hash, err)
+					gap = true
+					continue
+				}
+				push(*next)
+			}
+
+			// Skip over missing ancestors, e.g. pruned ones, through Refs
+			if gap {
+				for _, hash := range entry.Refs {
+					if queued[hash] {
+						continue
+					}
+					if ref, err := l.Get(hash); err == nil {
+						push(*ref)
+					}
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// entryQueue is a max-heap of entries ordered by clock, then hash.
+type entryQueue []EncodedEntry
+
+func (q entryQueue) Len() int { return len(q) }
+
+func (q entryQueue) Less(i, j int) bool {
+	if diff := CompareClocks(q[i].Clock, q[j].Clock); diff != 0 {
+		return diff > 0
+	}
+	return q[i].Hash > q[j].Hash
+}
+
+func (q entryQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *entryQueue) Push(x any) { *q = append(*q, x.(EncodedEntry)) }
+
+func (q *entryQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,132 @@
+package oplog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func setupIteratorLog(t *testing.T) (*Log, []*EncodedEntry) {
+	t.Helper()
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	entries := make([]*EncodedEntry, 0, 10)
+	for i := 1; i <= 10; i++ {
+		entry, err := log.Append(fmt.Sprintf("entry%d", i))
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return log, entries
+}
+
+func collectPayloads(t *testing.T, log *Log, opts IteratorOptions) []string {
+	t.Helper()
+	ch, err := log.Iterator(opts)
+	if err != nil {
+		t.Fatalf("Failed to create iterator: %v", err)
+	}
+	payloads := []string{}
+	for entry := range ch {
+		payloads = append(payloads, entry.Payload)
+	}
+	return payloads
+}
+
+func TestLog_IteratorPagination(t *testing.T) {
+	log, entries := setupIteratorLog(t)
+
+	// Page backwards through the log three entries at a time
+	var pages [][]string
+	opts := IteratorOptions{Amount: 3}
+	for {
+		ch, err := log.Iterator(opts)
+		if err != nil {
+			t.Fatalf("Failed to create iterator: %v", err)
+		}
+		var page []string
+		var last string
+		for entry := range ch {
+			page = append(page, entry.Payload)
+			last = entry.Hash
+		}
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, page)
+		opts = IteratorOptions{LT: last, Amount: 3}
+	}
+
+	expected := [][]string{
+		{"entry10", "entry9", "entry8"},
+		{"entry7", "entry6", "entry5"},
+		{"entry4", "entry3", "entry2"},
+		{"entry1"},
+	}
+	if fmt.Sprint(pages) != fmt.Sprint(expected) {
+		t.Fatalf("Expected pages %v, got %v", expected, pages)
+	}
+
+	// LTE includes the bound entry
+	page := collectPayloads(t, log, IteratorOptions{LTE: entries[4].Hash, Amount: 2})
+	if fmt.Sprint(page) != "[entry5 entry4]" {
+		t.Fatalf("Expected [entry5 entry4], got %v", page)
+	}
+}
+
+func TestLog_IteratorBounds(t *testing.T) {
+	log, entries := setupIteratorLog(t)
+
+	if all := collectPayloads(t, log, IteratorOptions{Amount: -1}); len(all) != 10 || all[0] != "entry10" || all[9] != "entry1" {
+		t.Fatalf("Expected all ten entries newest first, got %v", all)
+	}
+
+	if none := collectPayloads(t, log, IteratorOptions{Amount: 0}); len(none) != 0 {
+		t.Fatalf("Expected no entries for Amount 0, got %v", none)
+	}
+
+	if gt := collectPayloads(t, log, IteratorOptions{GT: entries[6].Hash, Amount: -1}); fmt.Sprint(gt) != "[entry10 entry9 entry8]" {
+		t.Fatalf("Expected [entry10 entry9 entry8], got %v", gt)
+	}
+
+	if gte := collectPayloads(t, log, IteratorOptions{GTE: entries[6].Hash, Amount: -1}); fmt.Sprint(gte) != "[entry10 entry9 entry8 entry7]" {
+		t.Fatalf("Expected [entry10 entry9 entry8 entry7], got %v", gte)
+	}
+
+	window := collectPayloads(t, log, IteratorOptions{LT: entries[5].Hash, GTE: entries[2].Hash, Amount: -1})
+	if fmt.Sprint(window) != "[entry5 entry4 entry3]" {
+		t.Fatalf("Expected [entry5 entry4 entry3], got %v", window)
+	}
+
+	for _, opts := range []IteratorOptions{
+		{GT: "unknown", Amount: -1},
+		{GTE: "unknown", Amount: -1},
+		{LT: "unknown", Amount: -1},
+		{LTE: "unknown", Amount: -1},
+	} {
+		if _, err := log.Iterator(opts); err == nil {
+			t.Errorf("Expected error for unknown bound in %+v", opts)
+		}
+	}
+}
+
+func TestLog_IteratorSkipsGapsThroughRefs(t *testing.T) {
+	log, entries := setupIteratorLog(t)
+
+	// Remove entry9 from storage, leaving entry10's Next dangling
+	if err := log.Entries.Delete(entries[8].Hash); err != nil {
+		t.Fatalf("Failed to delete entry: %v", err)
+	}
+
+	// entry10 refs entry8, below the gap, so the rest is still reached
+	got := fmt.Sprint(collectPayloads(t, log, IteratorOptions{LTE: entries[9].Hash, Amount: -1}))
+	expected := "[entry10 entry8 entry7 entry6 entry5 entry4 entry3 entry2 entry1]"
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
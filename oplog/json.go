@@ -0,0 +1,101 @@
+package oplog
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+)
+
+// entryJSON is the JSON representation of an EncodedEntry. Bytes are not
+// included; they are rebuilt from the other fields on decode.
+type entryJSON struct {
+	ID          string            `json:"id"`
+	Payload     string            `json:"payload"`
+	PayloadData []byte            `json:"payloadData,omitempty"`
+	Next        []string          `json:"next"`
+	Refs        []string          `json:"refs"`
+	Clock       Clock             `json:"clock"`
+	V           int               `json:"v"`
+	Meta        map[string]string `json:"meta,omitempty"`
+	Key         string            `json:"key"`
+	Identity    string            `json:"identity"`
+	SigAlgo     string            `json:"sigAlgo,omitempty"`
+	Signature   string            `json:"sig"`
+	CID         string            `json:"cid"`
+}
+
+// MarshalJSON encodes the entry's fields and its CID, as a base32 string,
+// for debugging, HTTP APIs and sync messages. DAG-CBOR remains the stored
+// form. The zero EncodedEntry, which has no CID, encodes as null.
+func (e EncodedEntry) MarshalJSON() ([]byte, error) {
+	if !e.CID.Defined() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(entryJSON{
+		ID:          e.ID,
+		Payload:     e.Payload,
+		PayloadData: e.PayloadData,
+		Next:        e.Next,
+		Refs:        e.Refs,
+		Clock:       e.Clock,
+		V:           e.V,
+		Meta:        e.Meta,
+		Key:         e.Key,
+		Identity:    e.Identity,
+		SigAlgo:     e.SigAlgo,
+		Signature:   e.Signature,
+		CID:         e.CID.String(),
+	})
+}
+
+// UnmarshalJSON decodes an entry with UnmarshalEntryJSON. Null leaves the
+// entry unchanged.
+func (e *EncodedEntry) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	entry, err := UnmarshalEntryJSON(b)
+	if err != nil {
+		return err
+	}
+	*e = entry
+	return nil
+}
+
+// UnmarshalEntryJSON decodes an entry produced by MarshalJSON. The entry is
+// re-encoded from the decoded fields with the transmitted CID's hash function
+// and must hash to that CID.
+// The signature is not verified.
+func UnmarshalEntryJSON(b []byte) (EncodedEntry, error) {
+	var data entryJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return EncodedEntry{}, fmt.Errorf("invalid entry JSON: %w", err)
+	}
+	expected, err := cid.Decode(data.CID)
+	if err != nil {
+		return EncodedEntry{}, fmt.Errorf("invalid entry CID %q: %w", data.CID, err)
+	}
+
+	entry, err := EncodeWithHash(Entry{
+		ID:          data.ID,
+		Payload:     data.Payload,
+		PayloadData: data.PayloadData,
+		Next:        data.Next,
+		Refs:        data.Refs,
+		Clock:       data.Clock,
+		V:           data.V,
+		Meta:        data.Meta,
+		Key:         data.Key,
+		Identity:    data.Identity,
+		SigAlgo:     data.SigAlgo,
+		Signature:   data.Signature,
+	}, expected.Prefix().MhType)
+	if err != nil {
+		return EncodedEntry{}, err
+	}
+	if !entry.CID.Equals(expected) {
+		return EncodedEntry{}, fmt.Errorf("%w: expected %s, got %s", ErrCIDMismatch, data.CID, entry.CID)
+	}
+	return entry, nil
+}
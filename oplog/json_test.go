@@ -0,0 +1,101 @@
+package oplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestEntryJSONRoundTrip(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	parent := mustNewEntry(t, ks, identity, "test-log", "parent", NewClock(identity.ID, 1), nil, nil)
+	entry, err := NewEntryWithMeta(ks, identity, "test-log", "payload-data", NewClock(identity.ID, 2), []string{parent.Hash}, []string{parent.Hash}, map[string]string{"content-type": "text/plain"})
+	if err != nil {
+		t.Fatalf("Failed to create entry: %v", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Failed to marshal entry: %v", err)
+	}
+	if !strings.Contains(string(data), `"cid":"`+entry.CID.String()+`"`) {
+		t.Errorf("Expected the JSON to carry the CID, got %s", data)
+	}
+
+	decoded, err := UnmarshalEntryJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal entry: %v", err)
+	}
+	if !decoded.CID.Equals(entry.CID) || decoded.Hash != entry.Hash {
+		t.Errorf("Expected CID %s, got %s", entry.CID, decoded.CID)
+	}
+	if !bytes.Equal(decoded.Bytes, entry.Bytes) {
+		t.Error("Expected the decoded entry to re-encode to the original bytes")
+	}
+	if !IsEqual(decoded, entry) || decoded.Signature != entry.Signature {
+		t.Error("Expected all fields to survive the round trip")
+	}
+	if !VerifyEntrySignature(ks, decoded) {
+		t.Error("Expected the decoded entry to verify")
+	}
+
+	// Changing a field breaks the CID check
+	tampered := strings.Replace(string(data), "payload-data", "tampered", 1)
+	if _, err := UnmarshalEntryJSON([]byte(tampered)); !errors.Is(err, ErrCIDMismatch) {
+		t.Errorf("Expected ErrCIDMismatch for tampered JSON, got %v", err)
+	}
+	if _, err := UnmarshalEntryJSON([]byte("{")); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+
+	// Entries embedded in other values decode through UnmarshalJSON
+	var wrapped struct {
+		Entry EncodedEntry
+		Empty EncodedEntry
+	}
+	data, err = json.Marshal(struct{ Entry, Empty EncodedEntry }{Entry: entry})
+	if err != nil {
+		t.Fatalf("Failed to marshal wrapper: %v", err)
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		t.Fatalf("Failed to unmarshal wrapper: %v", err)
+	}
+	if wrapped.Entry.Hash != entry.Hash || wrapped.Empty.CID.Defined() {
+		t.Errorf("Expected the entry to round-trip and the zero entry to stay empty, got %s", data)
+	}
+}
+
+func TestEntryJSONRoundTripNonDefaultHash(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	signed := mustNewEntry(t, ks, identity, "test-log", "payload-data", NewClock(identity.ID, 1), nil, nil)
+	entry, err := EncodeWithHash(signed.Entry, mh.SHA2_512)
+	if err != nil {
+		t.Fatalf("Failed to encode with SHA2-512: %v", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Failed to marshal entry: %v", err)
+	}
+	decoded, err := UnmarshalEntryJSON(data)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal a SHA2-512 entry: %v", err)
+	}
+	if !decoded.CID.Equals(entry.CID) || decoded.Hash != entry.Hash {
+		t.Errorf("Expected CID %s, got %s", entry.CID, decoded.CID)
+	}
+	if decoded.CID.Prefix().MhType != mh.SHA2_512 {
+		t.Errorf("Expected a SHA2-512 CID, got 0x%x", decoded.CID.Prefix().MhType)
+	}
+
+	tampered := strings.Replace(string(data), "payload-data", "tampered", 1)
+	if _, err := UnmarshalEntryJSON([]byte(tampered)); !errors.Is(err, ErrCIDMismatch) {
+		t.Errorf("Expected ErrCIDMismatch for tampered JSON, got %v", err)
+	}
+}
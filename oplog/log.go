@@ -1,29 +1,128 @@
 package oplog
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"orbitdb/go-orbitdb/identities/identitytypes"
 	"orbitdb/go-orbitdb/keystore"
 	"sort"
 	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
 
 	"orbitdb/go-orbitdb/storage"
 )
 
+// ErrEntryMissing is returned by History when a referenced entry is not stored.
+var ErrEntryMissing = errors.New("entry missing from log")
+
 // Log represents an append-only log
 type Log struct {
-	ID       string
-	Identity *identitytypes.Identity
-	Clock    Clock
-	Head     *EncodedEntry
-	Entries  storage.Storage
-	keystore *keystore.KeyStore
-	Mu       sync.RWMutex
+	ID         string
+	Identity   *identitytypes.Identity
+	Clock      Clock
+	Head       *EncodedEntry
+	Entries    storage.Storage
+	keystore   *keystore.KeyStore
+	access     AccessController
+	audit      AccessAudit
+	strict     bool
+	resolve    IdentityResolver
+	fetch      IdentityResolver
+	policy     UnresolvedIdentityPolicy
+	quarantine storage.Storage
+	metrics    Metrics
+	dedup      map[string]string
+	inserted   map[string]bool
+	insertion  []string
+	heads      []string
+	headsKnown bool
+	ancestry   []string // Head and its first-Next ancestors, nil when unknown
+	tieBreak   TieBreak
+	invariant  func(error)
+	references int
+	Mu         sync.RWMutex
+}
+
+// LogOption configures optional behaviour of a Log.
+type LogOption func(*Log)
+
+// WithAccessController gates appends and joins through the given controller.
+func WithAccessController(ac AccessController) LogOption {
+	return func(l *Log) {
+		l.access = ac
+	}
+}
+
+// WithAccessAudit records every access-control decision to the given audit.
+func WithAccessAudit(audit AccessAudit) LogOption {
+	return func(l *Log) {
+		l.audit = audit
+	}
+}
+
+// WithStrictValidation rejects entries failing ValidateEntry when they are
+// read from storage or joined from other logs.
+func WithStrictValidation() LogOption {
+	return func(l *Log) {
+		l.strict = true
+	}
+}
+
+// WithIdentityResolver resolves the writer identity of joined entries so they
+// can be checked with VerifyEntryFull.
+func WithIdentityResolver(resolve IdentityResolver) LogOption {
+	return func(l *Log) {
+		l.resolve = resolve
+	}
+}
+
+// WithIdentityFetcher sets the remote lookup used by the FetchThenVerify policy.
+func WithIdentityFetcher(fetch IdentityResolver) LogOption {
+	return func(l *Log) {
+		l.fetch = fetch
+	}
+}
+
+// WithUnresolvedIdentityPolicy controls what happens to joined entries whose
+// identity cannot be resolved. Quarantined entries are held in quarantine,
+// which defaults to memory storage.
+func WithUnresolvedIdentityPolicy(policy UnresolvedIdentityPolicy, quarantine storage.Storage) LogOption {
+	return func(l *Log) {
+		l.policy = policy
+		l.quarantine = quarantine
+	}
+}
+
+// WithMetrics reports Append and Join latencies to the given metrics sink.
+func WithMetrics(m Metrics) LogOption {
+	return func(l *Log) {
+		l.metrics = m
+	}
+}
+
+// DefaultReferencesCount is how far back appended entries reference older
+// entries through Refs unless WithReferencesCount is given.
+const DefaultReferencesCount = 16
+
+// WithReferencesCount sets how many entries back appended entries reference
+// through Refs, which hold the entries 2, 4, 8, … links back up to n. Larger
+// values let traversal skip further at the cost of a longer walk on each
+// append; 0 or less disables Refs.
+func WithReferencesCount(n int) LogOption {
+	return func(l *Log) {
+		l.references = n
+	}
 }
 
 // NewLog creates a new log instance
-func NewLog(id string, identity *identitytypes.Identity, entryStorage storage.Storage, keyStore *keystore.KeyStore) (*Log, error) {
+func NewLog(id string, identity *identitytypes.Identity, entryStorage storage.Storage, keyStore *keystore.KeyStore, opts ...LogOption) (*Log, error) {
 	if id == "" {
 		return nil, errors.New("log ID is required")
 	}
@@ -49,46 +148,357 @@ func NewLog(id string, identity *identitytypes.Identity, entryStorage storage.St
 		}
 	}
 
-	return &Log{
-		ID:       id,
-		Identity: identity,
-		Clock:    NewClock(identity.ID, 0),
-		Entries:  entryStorage,
-		keystore: keyStore,
-	}, nil
+	l := &Log{
+		ID:         id,
+		Identity:   identity,
+		Clock:      NewClock(identity.ID, 0),
+		Entries:    entryStorage,
+		keystore:   keyStore,
+		audit:      NoopAudit{},
+		inserted:   make(map[string]bool),
+		references: DefaultReferencesCount,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.quarantine == nil {
+		l.quarantine = storage.NewMemoryStorage()
+	}
+	return l, nil
 }
 
-// Append adds a new entry to the log
+// Append adds a new entry to the log. Its Next links every current head,
+// so branches brought in by a join are merged back into one.
 func (l *Log) Append(payload string) (*EncodedEntry, error) {
+	return l.AppendTx(payload, nil)
+}
+
+// AppendWithMeta adds a new entry carrying metadata; see NewEntryWithMeta.
+func (l *Log) AppendWithMeta(payload string, meta map[string]string) (*EncodedEntry, error) {
+	if l.metrics != nil {
+		defer l.observe(OpAppend, time.Now())
+	}
+	if payload == "" {
+		return nil, errors.New("payload is required")
+	}
+	return l.appendEntry(payload, nil, meta)
+}
+
+// AppendTx appends a new entry and calls commit only once the entry is durable
+// in storage and the head has been advanced. If storing the entry fails the
+// head and clock are left untouched and commit is never called. commit runs
+// after the log lock is released, so it may read from the log.
+func (l *Log) AppendTx(payload string, commit func(*EncodedEntry) error) (*EncodedEntry, error) {
+	if l.metrics != nil {
+		defer l.observe(OpAppend, time.Now())
+	}
+
+	if payload == "" {
+		return nil, errors.New("payload is required")
+	}
+	entry, err := l.appendEntry(payload, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if commit != nil {
+		if err := commit(entry); err != nil {
+			return entry, fmt.Errorf("entry %s stored but commit failed: %w", entry.Hash, err)
+		}
+	}
+	return entry, nil
+}
+
+// AppendBatch appends payloads as a single linear run of entries, the first
+// one's Next pointing at the current heads and each later one's at the one
+// before it, and returns them in order. The log is
+// locked once for the whole batch, entries are written in one batch when the
+// storage is a storage.BatchStorage, and the head and clock advance only once
+// every entry is stored. Entries are not deduplicated against the log.
+func (l *Log) AppendBatch(payloads []string) ([]EncodedEntry, error) {
+	if l.metrics != nil {
+		defer l.observe(OpAppend, time.Now())
+	}
+
 	l.Mu.Lock()
 	defer l.Mu.Unlock()
 
+	next, clock, err := l.headLinks()
+	if err != nil {
+		return nil, err
+	}
+	head := l.Head
+	ancestry := l.ancestryOf(head, nil)
+	entries := make([]EncodedEntry, 0, len(payloads))
+	pending := make(map[string]*EncodedEntry, len(payloads))
+	for i, payload := range payloads {
+		if payload == "" {
+			return nil, fmt.Errorf("payload %d: payload is required", i)
+		}
+
+		var err error
+		if clock, err = SafeTickClock(clock); err != nil {
+			return nil, err
+		}
+
+		entry, err := NewEntryWithMeta(l.keystore, l.Identity, l.ID, payload, clock, next, refsFrom(ancestry), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create entry: %w", err)
+		}
+		if err := l.canAppend(OpAppend, entry, l.Identity); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+		head = &entries[len(entries)-1]
+		pending[entry.Hash] = head
+		next = []string{entry.Hash}
+		if ancestry = l.extendAncestry(ancestry, head); ancestry == nil {
+			ancestry = l.ancestryOf(head, pending)
+		}
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	if batch, ok := l.Entries.(storage.BatchStorage); ok {
+		pairs := make(map[string][]byte, len(entries))
+		for _, entry := range entries {
+			pairs[entry.Hash] = entry.Bytes
+		}
+		if err := batch.PutMany(pairs); err != nil {
+			return nil, fmt.Errorf("failed to store entries: %w", err)
+		}
+		for i := range entries {
+			l.recordStored(&entries[i])
+		}
+	} else {
+		for i := range entries {
+			if err := l.storeEntry(&entries[i]); err != nil {
+				return nil, fmt.Errorf("failed to store entry: %w", err)
+			}
+		}
+	}
+
+	l.Clock = clock
+	last := entries[len(entries)-1]
+	// The first entry links every previous head
+	l.heads, l.headsKnown = []string{last.Hash}, true
+	l.Head = &last
+	l.ancestry = ancestry
+	return entries, nil
+}
+
+// backReferences returns the Refs of an entry appended after head: the
+// hashes of the entries 2, 4, 8, … links back, up to the log's references
+// count, walking the first Next link of each entry. The walk stops early at
+// a missing ancestor. The caller must hold the log lock.
+func (l *Log) backReferences(head *EncodedEntry) []string {
+	return refsFrom(l.ancestryOf(head, nil))
+}
+
+// refsFrom picks the entries 2, 4, 8, … links back from an ancestry.
+func refsFrom(ancestry []string) []string {
+	var refs []string
+	for d := 2; d <= len(ancestry); d *= 2 {
+		refs = append(refs, ancestry[d-1])
+	}
+	return refs
+}
+
+// ancestryOf returns head followed by the hashes reached from it through
+// first Next links, up to the log's references count. Entries in pending
+// are used before storage. The ancestry of Head is cached between appends,
+// so a linear history is not reread from storage on each one; the caller
+// must hold the log lock.
+func (l *Log) ancestryOf(head *EncodedEntry, pending map[string]*EncodedEntry) []string {
+	if head == nil || l.references < 2 {
+		return nil
+	}
+	if head == l.Head && len(l.ancestry) > 0 && l.ancestry[0] == head.Hash {
+		return l.ancestry
+	}
+
+	chain := []string{head.Hash}
+	entry := head
+	for len(chain) < l.references && len(entry.Next) > 0 {
+		hash := entry.Next[0]
+		if next, ok := pending[hash]; ok {
+			entry = next
+		} else {
+			// The caller holds the log lock, so read storage directly
+			data, err := l.Entries.Get(hash)
+			if err != nil {
+				break
+			}
+			decoded, err := Decode(data)
+			if err != nil || decoded.Hash != hash {
+				break
+			}
+			entry = &decoded
+		}
+		chain = append(chain, entry.Hash)
+	}
+	if head == l.Head {
+		l.ancestry = chain
+	}
+	return chain
+}
+
+// extendAncestry returns the ancestry of entry appended on top of a head
+// whose ancestry was prev, or nil when entry's first Next link is not that
+// head.
+func (l *Log) extendAncestry(prev []string, entry *EncodedEntry) []string {
+	if l.references < 2 || len(prev) == 0 || len(entry.Next) == 0 || storageKey(entry.Next[0]) != prev[0] {
+		return nil
+	}
+	chain := make([]string, 0, l.references)
+	chain = append(chain, entry.Hash)
+	return append(chain, prev[:min(len(prev), l.references-1)]...)
+}
+
+// AppendData appends an entry whose payload is binary data, such as a
+// structured value encoded with EncodePayload; see NewEntryWithData.
+func (l *Log) AppendData(data []byte) (*EncodedEntry, error) {
+	return l.AppendDataTx(data, nil)
+}
+
+// AppendDataTx is AppendTx for binary payload data.
+func (l *Log) AppendDataTx(data []byte, commit func(*EncodedEntry) error) (*EncodedEntry, error) {
+	if l.metrics != nil {
+		defer l.observe(OpAppend, time.Now())
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("payload is required")
+	}
+	entry, err := l.appendEntry("", data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if commit != nil {
+		if err := commit(entry); err != nil {
+			return entry, fmt.Errorf("entry %s stored but commit failed: %w", entry.Hash, err)
+		}
+	}
+	return entry, nil
+}
+
+// appendEntry creates, stores and links a new entry under the log lock.
+func (l *Log) appendEntry(payload string, data []byte, meta map[string]string) (*EncodedEntry, error) {
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	next, clock, err := l.headLinks()
+	if err != nil {
+		return nil, err
+	}
+	if clock, err = SafeTickClock(clock); err != nil {
+		return nil, err
+	}
+	refs := l.backReferences(l.Head)
+
+	return l.appendLinked(payload, data, meta, clock, next, refs)
+}
+
+// AppendWith appends an entry whose next and refs links are given by the
+// caller instead of derived from the current head. Every hash must name an
+// entry already in the log and may use any multibase encoding of its CID;
+// links are stored in base58btc and a hash may appear only once per list.
+// Pointing next at several heads writes a merge entry that collapses them
+// into one; the clock is ordered after all of them.
+func (l *Log) AppendWith(payload string, next []string, refs []string) (*EncodedEntry, error) {
+	if l.metrics != nil {
+		defer l.observe(OpAppend, time.Now())
+	}
 	if payload == "" {
 		return nil, errors.New("payload is required")
 	}
+	next, err := linkKeys("next", next)
+	if err != nil {
+		return nil, err
+	}
+	refs, err = linkKeys("ref", refs)
+	if err != nil {
+		return nil, err
+	}
 
-	l.Clock = TickClock(l.Clock)
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
 
-	var next []string
-	if l.Head != nil {
-		next = []string{l.Head.Hash}
+	clock := l.Clock
+	for _, hash := range next {
+		data, err := l.Entries.Get(hash)
+		if err != nil {
+			return nil, fmt.Errorf("next entry %s not found: %w", hash, err)
+		}
+		parent, err := Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode next entry %s: %w", hash, err)
+		}
+		clock = clock.Merge(parent.Clock)
+	}
+	for _, hash := range refs {
+		ok, err := l.Entries.Has(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up ref %s: %w", hash, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("ref entry %s not found", hash)
+		}
 	}
 
-	entry := NewEntry(l.keystore, l.Identity, l.ID, payload, l.Clock, next, nil)
+	clock, err = SafeTickClock(clock)
+	if err != nil {
+		return nil, err
+	}
+	return l.appendLinked(payload, nil, nil, clock, next, refs)
+}
 
-	if err := l.Entries.Put(entry.Hash, entry.Bytes); err != nil {
+// appendLinked creates and stores an entry with the given clock and links,
+// then makes it the head. The caller must hold Mu.
+func (l *Log) appendLinked(payload string, data []byte, meta map[string]string, clock Clock, next, refs []string) (*EncodedEntry, error) {
+	entry, err := newEntry(l.keystore, l.Identity, l.ID, payload, data, clock, next, refs, meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if l.dedup != nil {
+		if existing, ok := l.duplicateOf(entry); ok {
+			return existing, nil
+		}
+	}
+
+	if err := l.canAppend(OpAppend, entry, l.Identity); err != nil {
+		return nil, err
+	}
+
+	prev, known, ancestry := l.heads, l.headsKnown, l.ancestry
+	if err := l.storeEntry(&entry); err != nil {
 		return nil, fmt.Errorf("failed to store entry: %w", err)
 	}
+	l.advanceHeads(prev, known, &entry)
+	l.ancestry = l.extendAncestry(ancestry, &entry)
 
+	l.Clock = clock
 	l.Head = &entry
 	return &entry, nil
 }
 
-// Get retrieves an entry by its hash
+// Get retrieves an entry by its hash. The hash may be the CID in any
+// multibase encoding, such as CID.String(); it is looked up by its
+// base58btc form, which entries are stored under.
 func (l *Log) Get(hash string) (*EncodedEntry, error) {
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
+	return l.get(hash)
+}
 
+// get is Get for callers already holding Mu. Taking the read lock again
+// would deadlock once a writer is waiting.
+func (l *Log) get(hash string) (*EncodedEntry, error) {
+	hash = storageKey(hash)
 	data, err := l.Entries.Get(hash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get entry for hash %s: %w", hash, err)
@@ -99,6 +509,15 @@ func (l *Log) Get(hash string) (*EncodedEntry, error) {
 		return nil, fmt.Errorf("failed to decode entry for hash %s: %w", hash, err)
 	}
 
+	// Guard against a store returning bytes under the wrong key
+	if entry.Hash != hash {
+		return nil, fmt.Errorf("entry stored under %s has CID %s", hash, entry.Hash)
+	}
+
+	if err := l.validate(entry); err != nil {
+		return nil, fmt.Errorf("invalid entry %s: %w", hash, err)
+	}
+
 	if !VerifyEntrySignature(l.keystore, entry) {
 		return nil, fmt.Errorf("invalid signature for entry %s", hash)
 	}
@@ -106,15 +525,106 @@ func (l *Log) Get(hash string) (*EncodedEntry, error) {
 	return &entry, nil
 }
 
-// Values retrieves all Entries in the log, sorted using CompareClocks
+// Has reports whether the entry with the given CID, in any multibase
+// encoding, is stored. Unlike Get it neither loads nor verifies the entry.
+// Strings that are not valid CIDs are reported as absent.
+func (l *Log) Has(hash string) bool {
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return false
+	}
+	key, err := c.StringOfBase(multibase.Base58BTC)
+	if err != nil {
+		return false
+	}
+
+	l.Mu.RLock()
+	defer l.Mu.RUnlock()
+
+	has, err := l.Entries.Has(key)
+	return err == nil && has
+}
+
+// storageKey returns the base58btc form of a CID string. Strings that are
+// not CIDs are returned unchanged, and simply miss in storage.
+func storageKey(hash string) string {
+	c, err := cid.Decode(hash)
+	if err != nil {
+		return hash
+	}
+	key, err := c.StringOfBase(multibase.Base58BTC)
+	if err != nil {
+		return hash
+	}
+	return key
+}
+
+// linkKeys converts caller-supplied links to their storage keys, rejecting
+// a hash that appears twice in any encoding.
+func linkKeys(kind string, hashes []string) ([]string, error) {
+	if hashes == nil {
+		return nil, nil
+	}
+	keys := make([]string, len(hashes))
+	seen := make(map[string]bool, len(hashes))
+	for i, hash := range hashes {
+		key := storageKey(hash)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate %s link %s", kind, hash)
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// Values retrieves all Entries in the log, in the order of SortEntries, or
+// SortEntriesBy with the log's tie-break
 func (l *Log) Values() ([]EncodedEntry, error) {
 	l.Mu.RLock()
 	defer l.Mu.RUnlock()
 
 	entries := make([]EncodedEntry, 0)
+	err := l.eachValue(func(entry EncodedEntry) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortEntries(entries, l.tieBreak)
+	return entries, nil
+}
+
+// Length returns the number of entries Values would return, without
+// collecting or sorting them.
+func (l *Log) Length() int {
+	l.Mu.RLock()
+	defer l.Mu.RUnlock()
+
+	length := 0
+	err := l.eachValue(func(EncodedEntry) {
+		length++
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to count values: %s\n", err)
+		return 0
+	}
+	return length
+}
+
+// IsEmpty reports whether the log has no entries.
+func (l *Log) IsEmpty() bool {
+	return l.Length() == 0
+}
+
+// eachValue calls visit with every stored entry that decodes under its
+// key, validates, and carries a valid signature, skipping the rest with a
+// warning. The caller must hold Mu.
+func (l *Log) eachValue(visit func(EncodedEntry)) error {
 	ch, err := l.Entries.Iterator()
 	if err != nil {
-		return nil, fmt.Errorf("failed to iterate over Entries: %w", err)
+		return fmt.Errorf("failed to iterate over Entries: %w", err)
 	}
 
 	for kv := range ch {
@@ -124,20 +634,24 @@ func (l *Log) Values() ([]EncodedEntry, error) {
 			continue
 		}
 
+		if entry.Hash != kv[0] {
+			fmt.Printf("Warning: Skipping entry stored under %s with CID %s\n", kv[0], entry.Hash)
+			continue
+		}
+
+		if err := l.validate(entry); err != nil {
+			fmt.Printf("Warning: Skipping invalid entry %s: %s\n", entry.Hash, err)
+			continue
+		}
+
 		if !VerifyEntrySignature(l.keystore, entry) {
 			fmt.Printf("Warning: Skipping entry with invalid signature: %s\n", entry.Hash)
 			continue
 		}
 
-		entries = append(entries, entry)
+		visit(entry)
 	}
-
-	// Sort Entries using CompareClocks
-	sort.Slice(entries, func(i, j int) bool {
-		return CompareClocks(entries[i].Clock, entries[j].Clock) < 0
-	})
-
-	return entries, nil
+	return nil
 }
 
 func (l *Log) Traverse(startHash string, shouldStop func(*EncodedEntry) bool) ([]*EncodedEntry, error) {
@@ -150,7 +664,7 @@ func (l *Log) Traverse(startHash string, shouldStop func(*EncodedEntry) bool) ([
 	// Start traversal from the specified entry or the current head
 	var stack []*EncodedEntry
 	if startHash != "" {
-		startEntry, err := l.Get(startHash)
+		startEntry, err := l.get(startHash)
 		if err != nil {
 			return nil, fmt.Errorf("failed to start traversal from entry: %w", err)
 		}
@@ -191,7 +705,7 @@ func (l *Log) Traverse(startHash string, shouldStop func(*EncodedEntry) bool) ([
 
 		// Load and add the `next` Entries to the stack
 		for _, nextHash := range entry.Entry.Next {
-			nextEntry, err := l.Get(nextHash)
+			nextEntry, err := l.get(nextHash)
 			if err != nil {
 				fmt.Printf("Warning: Failed to load next entry %s: %s\n", nextHash, err)
 				continue
@@ -203,16 +717,99 @@ func (l *Log) Traverse(startHash string, shouldStop func(*EncodedEntry) bool) ([
 	return traversed, nil
 }
 
+// StreamFromStorage walks the log backwards from the given heads, or the
+// current head when none are given, decoding entries from storage on the fly.
+// Only the set of visited hashes is kept in memory. The channel is closed when
+// the walk completes or ctx is cancelled.
+func (l *Log) StreamFromStorage(ctx context.Context, heads []string) <-chan EncodedEntry {
+	out := make(chan EncodedEntry)
+
+	stack := append([]string(nil), heads...)
+	if len(stack) == 0 {
+		l.Mu.RLock()
+		if l.Head != nil {
+			stack = []string{l.Head.Hash}
+		}
+		l.Mu.RUnlock()
+	}
+
+	go func() {
+		defer close(out)
+
+		visited := make(map[string]bool)
+		for len(stack) > 0 {
+			hash := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if visited[hash] {
+				continue
+			}
+			visited[hash] = true
+
+			entry, err := l.Get(hash)
+			if err != nil {
+				fmt.Printf("Warning: Failed to load entry %s: %s\n", hash, err)
+				continue
+			}
+
+			select {
+			case out <- *entry:
+			case <-ctx.Done():
+				return
+			}
+
+			for _, next := range entry.Next {
+				if !visited[next] {
+					stack = append(stack, next)
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// JoinEntry verifies an entry and adds it to the log, skipping entries
+// already in processed. The caller must hold l.Mu for writing.
 func (l *Log) JoinEntry(entry *EncodedEntry, processed map[string]bool) error {
+	before := l.entrySet()
+	defer l.checkJoinInvariant(OpJoin, before)
+	return l.joinEntry(entry, processed)
+}
+
+func (l *Log) joinEntry(entry *EncodedEntry, processed map[string]bool) error {
+	if err := l.verifyIncoming(entry); err != nil {
+		return err
+	}
+	return l.storeJoined(entry, processed)
+}
+
+// verifyIncoming checks that an entry from another log belongs to this log,
+// is valid and correctly signed, and that its writer may append.
+func (l *Log) verifyIncoming(entry *EncodedEntry) error {
 	// Check if the entry belongs to the current log
 	if entry.Entry.ID != l.ID {
 		return fmt.Errorf("entry ID '%s' does not match log ID '%s'", entry.Entry.ID, l.ID)
 	}
 
+	if err := l.validate(*entry); err != nil {
+		return fmt.Errorf("invalid entry %s: %w", entry.Hash, err)
+	}
+
 	if !VerifyEntrySignature(l.keystore, *entry) {
 		return fmt.Errorf("invalid signature for entry %s", entry.Hash)
 	}
 
+	identity, err := l.resolveIdentity(entry)
+	if err != nil {
+		return err
+	}
+
+	return l.canAppend(OpJoin, *entry, identity)
+}
+
+// storeJoined stores a verified entry and advances the head and clock.
+func (l *Log) storeJoined(entry *EncodedEntry, processed map[string]bool) error {
 	// Initialize a stack for iterative processing
 	stack := []*EncodedEntry{entry}
 
@@ -228,7 +825,7 @@ func (l *Log) JoinEntry(entry *EncodedEntry, processed map[string]bool) error {
 		processed[currentEntry.Hash] = true
 
 		// Add the entry to storage
-		err := l.Entries.Put(currentEntry.Hash, currentEntry.Bytes)
+		err := l.storeEntry(currentEntry)
 		if err != nil {
 			return fmt.Errorf("failed to store entry: %w", err)
 		}
@@ -237,35 +834,422 @@ func (l *Log) JoinEntry(entry *EncodedEntry, processed map[string]bool) error {
 		if l.Head == nil || CompareClocks(currentEntry.Clock, l.Head.Clock) > 0 {
 			l.Head = currentEntry
 		}
+
+		// Advance the Lamport clock past every joined entry
+		l.Clock = l.Clock.Merge(currentEntry.Clock)
 	}
 
 	return nil
 }
 
+// storeEntry writes an entry to storage unless a block with the same CID is
+// already present, so logs sharing a storage never store an entry twice.
+func (l *Log) storeEntry(entry *EncodedEntry) error {
+	if existing, err := l.Entries.Get(entry.Hash); err != nil || !bytes.Equal(existing, entry.Bytes) {
+		if err := l.Entries.Put(entry.Hash, entry.Bytes); err != nil {
+			return err
+		}
+	}
+	l.recordStored(entry)
+	return nil
+}
+
+// recordStored updates the dedup index and insertion order for an entry
+// that has been written to storage, and drops the cached heads and ancestry.
+func (l *Log) recordStored(entry *EncodedEntry) {
+	l.headsKnown = false
+	l.ancestry = nil
+	if l.dedup != nil {
+		if key, err := ContentID(entry.Entry); err == nil {
+			l.dedup[key] = entry.Hash
+		}
+	}
+	if !l.inserted[entry.Hash] {
+		l.inserted[entry.Hash] = true
+		l.insertion = append(l.insertion, entry.Hash)
+	}
+}
+
+// Join merges every entry stored in otherLog into the log. Each incoming
+// entry is verified before it is accepted; entries failing verification are
+// skipped and the join continues, returning an aggregate error naming the
+// rejected CIDs.
 func (l *Log) Join(otherLog *Log) error {
-	l.Mu.Lock()
-	defer l.Mu.Unlock()
+	if l.metrics != nil {
+		defer l.observe(OpJoin, time.Now())
+	}
 
 	// Check if the other log has the same ID
 	if otherLog.ID != l.ID {
 		return fmt.Errorf("log ID '%s' does not match other log ID '%s'", l.ID, otherLog.ID)
 	}
 
-	// Get all Entries from the other log
-	otherEntries, err := otherLog.Values()
+	// Read the other log's entries directly so unverifiable ones are reported
+	otherLog.Mu.RLock()
+	ch, err := otherLog.Entries.Iterator()
 	if err != nil {
+		otherLog.Mu.RUnlock()
 		return fmt.Errorf("failed to retrieve Entries from other log: %w", err)
 	}
+	var otherEntries []EncodedEntry
+	var errs []error
+	for kv := range ch {
+		entry, err := Decode([]byte(kv[1]))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", kv[0], err))
+			continue
+		}
+		otherEntries = append(otherEntries, entry)
+	}
+	otherLog.Mu.RUnlock()
 
-	// Process each entry using the JoinEntry method
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	before := l.entrySet()
+	defer l.checkJoinInvariant(OpJoin, before)
+
+	// Process each entry using the joinEntry method
 	processed := make(map[string]bool)
-	for _, entry := range otherEntries {
-		if err := l.JoinEntry(&entry, processed); err != nil {
-			fmt.Printf("Warning: Skipping invalid or duplicate entry %s: %v\n", entry.Hash, err)
+	for i := range otherEntries {
+		if err := l.joinEntry(&otherEntries[i], processed); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", otherEntries[i].Hash, err))
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// StateRoot computes a digest of the history reachable from the given head:
+// the hex SHA-256 of the sorted entry hashes, one per line.
+func (l *Log) StateRoot(headHash string) (string, error) {
+	entries, err := l.Traverse(headHash, nil)
+	if err != nil {
+		return "", err
+	}
+
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	sort.Strings(hashes)
+
+	digest := sha256.New()
+	for _, hash := range hashes {
+		digest.Write([]byte(hash + "\n"))
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// VerifyCheckpoint reports whether the given head is present in the log and
+// the state root computed from it matches the trusted one.
+func (l *Log) VerifyCheckpoint(headCID string, stateRoot string) (bool, error) {
+	root, err := l.StateRoot(headCID)
+	if err != nil {
+		return false, fmt.Errorf("checkpoint head %s not available: %w", headCID, err)
+	}
+	return root == stateRoot, nil
+}
+
+// JoinAll joins a batch of entries, sharing one processed set across them.
+// Invalid entries are skipped and reported together in the returned error.
+func (l *Log) JoinAll(entries []EncodedEntry) error {
+	if l.metrics != nil {
+		defer l.observe(OpJoin, time.Now())
+	}
+
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	before := l.entrySet()
+	defer l.checkJoinInvariant(OpJoin, before)
+
+	var errs []error
+	processed := make(map[string]bool)
+	for i := range entries {
+		if err := l.joinEntry(&entries[i], processed); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", entries[i].Hash, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// History returns every entry reachable from the heads through Next links,
+// each once and in the order of SortEntries. Unlike Values, which returns
+// whatever is stored, it fails with ErrEntryMissing when a referenced entry
+// is not in storage, for example before EnsureComplete or after Prune.
+func (l *Log) History() ([]EncodedEntry, error) {
+	heads, err := l.Heads()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	entries := make([]EncodedEntry, 0)
+	stack := make([]EncodedEntry, 0, len(heads))
+	stack = append(stack, heads...)
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if seen[entry.Hash] {
+			continue
+		}
+		seen[entry.Hash] = true
+		entries = append(entries, entry)
+
+		for _, hash := range entry.Next {
+			if seen[hash] {
+				continue
+			}
+			next, err := l.Get(hash)
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %s referenced by %s", ErrEntryMissing, hash, entry.Hash)
+			}
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, *next)
+		}
+	}
+
+	sortEntries(entries, l.tieBreak)
+	return entries, nil
+}
+
+// MissingAncestors returns the Next and Refs hashes referenced by stored entries
+// that are not themselves present in storage, e.g. after only the heads of a
+// remote log were joined. The sync layer can use the result to fetch them.
+func (l *Log) MissingAncestors() []string {
+	l.Mu.RLock()
+	defer l.Mu.RUnlock()
+
+	ch, err := l.Entries.Iterator()
+	if err != nil {
+		fmt.Printf("Warning: Failed to iterate over Entries: %s\n", err)
+		return nil
+	}
+
+	present := make(map[string]bool)
+	referenced := make(map[string]bool)
+	for kv := range ch {
+		present[kv[0]] = true
+
+		entry, err := Decode([]byte(kv[1]))
+		if err != nil {
+			fmt.Printf("Warning: Skipping invalid entry with error: %s\n", err)
+			continue
+		}
+		for _, hash := range entry.Next {
+			referenced[hash] = true
+		}
+		for _, hash := range entry.Refs {
+			referenced[hash] = true
+		}
+	}
+
+	missing := make([]string, 0)
+	for hash := range referenced {
+		if !present[hash] {
+			missing = append(missing, hash)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ValuesByInsertion returns the log's entries in the order this log stored
+// them, by append or join, rather than in causal order. Entries that were
+// already in storage when the log was created come first, in causal order.
+// The insertion sequence is kept in memory only.
+func (l *Log) ValuesByInsertion() []EncodedEntry {
+	values, err := l.Values()
+	if err != nil {
+		fmt.Printf("Warning: Failed to read values: %s\n", err)
+		return nil
+	}
+
+	l.Mu.RLock()
+	insertion := append([]string(nil), l.insertion...)
+	inserted := make(map[string]bool, len(l.inserted))
+	for hash := range l.inserted {
+		inserted[hash] = true
+	}
+	l.Mu.RUnlock()
+
+	byHash := make(map[string]EncodedEntry, len(values))
+	ordered := make([]EncodedEntry, 0, len(values))
+	for _, entry := range values {
+		byHash[entry.Hash] = entry
+		if !inserted[entry.Hash] {
+			ordered = append(ordered, entry)
+		}
+	}
+	for _, hash := range insertion {
+		if entry, ok := byHash[hash]; ok {
+			ordered = append(ordered, entry)
+		}
+	}
+	return ordered
+}
+
+// Heads returns the entries that no other entry in the log points to via
+// Next, latest clock first with ties broken by hash.
+func (l *Log) Heads() ([]EncodedEntry, error) {
+	entries, err := l.Values()
+	if err != nil {
+		return nil, err
+	}
+	return headsOf(entries), nil
+}
+
+// headLinks returns the hashes of the current heads, in Heads order, and
+// the log clock merged with each head's clock, for the Next links and clock
+// of an entry appended on top of them. The heads are cached between appends
+// and recomputed from storage after a join or removal. The caller must hold
+// Mu.
+func (l *Log) headLinks() ([]string, Clock, error) {
+	if l.headsKnown {
+		return append([]string(nil), l.heads...), l.Clock, nil
+	}
+
+	entries := make([]EncodedEntry, 0)
+	if err := l.eachValue(func(entry EncodedEntry) {
+		entries = append(entries, entry)
+	}); err != nil {
+		return nil, Clock{}, err
+	}
+
+	clock := l.Clock
+	var next []string
+	for _, head := range headsOf(entries) {
+		next = append(next, head.Hash)
+		clock = clock.Merge(head.Clock)
+	}
+	l.heads, l.headsKnown = next, true
+	return append([]string(nil), next...), clock, nil
+}
+
+// advanceHeads updates the cached heads after entry was appended on top of
+// prev, the heads known before it was stored. The caller must hold Mu.
+func (l *Log) advanceHeads(prev []string, known bool, entry *EncodedEntry) {
+	if !known {
+		return
+	}
+	linked := make(map[string]bool, len(entry.Next))
+	for _, hash := range entry.Next {
+		linked[storageKey(hash)] = true
+	}
+	heads := []string{entry.Hash}
+	for _, hash := range prev {
+		if !linked[hash] {
+			heads = append(heads, hash)
+		}
+	}
+	l.heads, l.headsKnown = heads, true
+}
+
+// headsOf returns the entries in entries that no other entry points to via
+// Next, latest clock first with ties broken by hash.
+func headsOf(entries []EncodedEntry) []EncodedEntry {
+	referenced := make(map[string]bool)
+	for _, entry := range entries {
+		for _, hash := range entry.Next {
+			referenced[hash] = true
+		}
+	}
+
+	heads := make([]EncodedEntry, 0)
+	for _, entry := range entries {
+		if !referenced[entry.Hash] {
+			heads = append(heads, entry)
+		}
+	}
+
+	sort.Slice(heads, func(i, j int) bool {
+		if diff := CompareClocks(heads[i].Clock, heads[j].Clock); diff != 0 {
+			return diff > 0
+		}
+		return heads[i].Hash < heads[j].Hash
+	})
+	return heads
+}
+
+// Clone returns a copy of the log with the same entries, head, clock and
+// options, backed by its own memory storage, so entries can be appended to
+// the clone speculatively and discarded. The identity, keystore and access
+// controller are shared with the original. The clone starts with a NoopAudit
+// and no metrics, so its decisions are not recorded against the original;
+// opts are applied to the clone and can set its own, as with NewLog. Like
+// NewLog it returns an error, since copying the entries reads the original's
+// storage.
+func (l *Log) Clone(opts ...LogOption) (*Log, error) {
+	l.Mu.RLock()
+	defer l.Mu.RUnlock()
+
+	entries, err := copyStorage(l.Entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy Entries: %w", err)
+	}
+	quarantine, err := copyStorage(l.quarantine)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy quarantine: %w", err)
+	}
+
+	clone := &Log{
+		ID:         l.ID,
+		Identity:   l.Identity,
+		Clock:      l.Clock,
+		Entries:    entries,
+		keystore:   l.keystore,
+		access:     l.access,
+		audit:      NoopAudit{},
+		strict:     l.strict,
+		resolve:    l.resolve,
+		fetch:      l.fetch,
+		policy:     l.policy,
+		quarantine: quarantine,
+		inserted:   make(map[string]bool, len(l.inserted)),
+		insertion:  append([]string(nil), l.insertion...),
+		heads:      append([]string(nil), l.heads...),
+		headsKnown: l.headsKnown,
+		ancestry:   append([]string(nil), l.ancestry...),
+		tieBreak:   l.tieBreak,
+		invariant:  l.invariant,
+		references: l.references,
+	}
+	if l.Head != nil {
+		head := *l.Head
+		clone.Head = &head
+	}
+	if l.dedup != nil {
+		clone.dedup = make(map[string]string, len(l.dedup))
+		for key, hash := range l.dedup {
+			clone.dedup[key] = hash
+		}
+	}
+	for hash := range l.inserted {
+		clone.inserted[hash] = true
+	}
+	for _, opt := range opts {
+		opt(clone)
+	}
+	return clone, nil
+}
+
+// copyStorage copies every key of src into a new memory storage.
+func copyStorage(src storage.Storage) (storage.Storage, error) {
+	dst := storage.NewMemoryStorage()
+	ch, err := src.Iterator()
+	if err != nil {
+		return nil, err
+	}
+	for kv := range ch {
+		if err := dst.Put(kv[0], []byte(kv[1])); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
 }
 
 // Clear removes all Entries from the log
@@ -278,6 +1262,36 @@ func (l *Log) Clear() error {
 	}
 
 	l.Head = nil
+	l.heads, l.headsKnown = nil, true
+	l.ancestry = nil
+	if l.dedup != nil {
+		l.dedup = make(map[string]string)
+	}
+	l.inserted = make(map[string]bool)
+	l.insertion = nil
+	return nil
+}
+
+// Prune deletes the given entries from storage, for example once their
+// effect has been captured by a snapshot. Entries that still reference a
+// pruned entry report it through MissingAncestors. The current head cannot
+// be pruned.
+func (l *Log) Prune(hashes []string) error {
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	for _, hash := range hashes {
+		if l.Head != nil && l.Head.Hash == hash {
+			return fmt.Errorf("cannot prune head entry %s", hash)
+		}
+	}
+	for _, hash := range hashes {
+		if err := l.Entries.Delete(hash); err != nil {
+			return fmt.Errorf("failed to delete entry %s: %w", hash, err)
+		}
+		l.headsKnown = false
+		l.ancestry = nil
+	}
 	return nil
 }
 
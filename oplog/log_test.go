@@ -1,8 +1,21 @@
 package oplog
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
 	"orbitdb/go-orbitdb/storage"
 )
 
@@ -75,6 +88,144 @@ func TestLog_AppendAndGet(t *testing.T) {
 	}
 }
 
+func TestLog_GetByCID(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create new log: %v", err)
+	}
+
+	entry, err := log.Append("entry")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// CID.String() is base32, while entries are keyed by base58btc
+	retrieved, err := log.Get(entry.CID.String())
+	if err != nil {
+		t.Fatalf("Failed to get entry by CID string: %v", err)
+	}
+	if retrieved.Hash != entry.Hash {
+		t.Errorf("Expected entry %s, got %s", entry.Hash, retrieved.Hash)
+	}
+
+	digest, err := mh.Sum([]byte("not an entry"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatalf("Failed to hash: %v", err)
+	}
+	if _, err := log.Get(cid.NewCidV1(cid.DagCBOR, digest).String()); err == nil {
+		t.Error("Expected an error for a CID not in the log")
+	}
+	if _, err := log.Get("not-a-cid"); err == nil {
+		t.Error("Expected an error for an invalid CID string")
+	}
+}
+
+func TestLog_Has(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create new log: %v", err)
+	}
+
+	entry, err := log.Append("entry")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if !log.Has(entry.Hash) || !log.Has(entry.CID.String()) {
+		t.Error("Expected the appended entry to be present")
+	}
+
+	digest, err := mh.Sum([]byte("not an entry"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatalf("Failed to hash: %v", err)
+	}
+	if log.Has(cid.NewCidV1(cid.DagCBOR, digest).String()) {
+		t.Error("Expected an unknown CID to be absent")
+	}
+	if log.Has("not-a-cid") {
+		t.Error("Expected an invalid CID string to be absent")
+	}
+}
+
+func TestLog_ConcurrentAppend(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create new log: %v", err)
+	}
+
+	const appends = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, appends)
+	for i := 0; i < appends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := log.Append("entry" + strconv.Itoa(i)); err != nil {
+				errs <- err
+			}
+			// Readers run alongside the writers
+			if _, err := log.Values(); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent append failed: %v", err)
+	}
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != appends {
+		t.Fatalf("Expected %d entries, got %d", appends, len(values))
+	}
+
+	// The appends form a single chain with consecutive clock times
+	heads, err := log.Heads()
+	if err != nil || len(heads) != 1 {
+		t.Fatalf("Expected a single head, got %d (%v)", len(heads), err)
+	}
+	for i, entry := range values {
+		if entry.Clock.Time != i+1 {
+			t.Errorf("Entry %d: expected clock time %d, got %d", i, i+1, entry.Clock.Time)
+		}
+		if i == 0 {
+			if len(entry.Next) != 0 {
+				t.Errorf("Expected the first entry to have no next, got %v", entry.Next)
+			}
+		} else if len(entry.Next) != 1 || entry.Next[0] != values[i-1].Hash {
+			t.Errorf("Entry %d: expected next [%s], got %v", i, values[i-1].Hash, entry.Next)
+		}
+	}
+	if log.Clock.Time != appends {
+		t.Errorf("Expected clock time %d, got %d", appends, log.Clock.Time)
+	}
+}
+
+func TestLog_AppendEncodeError(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create new log: %v", err)
+	}
+
+	if _, err := log.Append("\xff\xfe"); err == nil {
+		t.Fatal("Expected error appending a payload that cannot be encoded")
+	}
+	if log.Head != nil || log.Clock.Time != 0 {
+		t.Errorf("Expected a failed append to leave the log unchanged, got head %v and clock %v", log.Head, log.Clock)
+	}
+}
+
 func TestLog_AppendAndRetrieve(t *testing.T) {
 	// Step 1: Set up the test keystore and identity
 	ks, identity := setupTestKeyStoreAndIdentity(t)
@@ -223,7 +374,7 @@ func TestLog_JoinEntry(t *testing.T) {
 
 	// Create a new entry to join
 	clock := NewClock(identity.ID, 1)
-	entry := NewEntry(ks, identity, logID, "joined entry", clock, nil, nil)
+	entry := mustNewEntry(t, ks, identity, logID, "joined entry", clock, nil, nil)
 
 	processed := make(map[string]bool)
 	err = log.JoinEntry(&entry, processed)
@@ -306,6 +457,232 @@ func TestLog_Join(t *testing.T) {
 	}
 }
 
+func TestLog_AppendAfterJoinMergesHeads(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log1, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log1: %v", err)
+	}
+	log2, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log2: %v", err)
+	}
+
+	for _, payload := range []string{"a1", "a2"} {
+		if _, err := log1.Append(payload); err != nil {
+			t.Fatalf("Failed to append to log1: %v", err)
+		}
+	}
+	b, err := log2.Append("b1")
+	if err != nil {
+		t.Fatalf("Failed to append to log2: %v", err)
+	}
+	if err := log1.Join(log2); err != nil {
+		t.Fatalf("Failed to join log2 into log1: %v", err)
+	}
+
+	heads, err := log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 2 {
+		t.Fatalf("Expected 2 heads after the join, got %d", len(heads))
+	}
+
+	entry, err := log1.Append("after-join")
+	if err != nil {
+		t.Fatalf("Failed to append after join: %v", err)
+	}
+	if len(entry.Next) != 2 {
+		t.Errorf("Expected the entry to link both heads, got %v", entry.Next)
+	}
+	if entry.Clock.Time <= b.Clock.Time {
+		t.Errorf("Expected the clock to advance past the joined head, got %d", entry.Clock.Time)
+	}
+
+	heads, err = log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 1 || heads[0].Hash != entry.Hash {
+		t.Errorf("Expected the new entry as the only head, got %v", heads)
+	}
+
+	// Later appends stay linear
+	next, err := log1.Append("linear")
+	if err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if len(next.Next) != 1 || next.Next[0] != entry.Hash {
+		t.Errorf("Expected the next entry to link only %s, got %v", entry.Hash, next.Next)
+	}
+}
+
+func TestLog_AppendWithMerge(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log1, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log1: %v", err)
+	}
+	log2, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log2: %v", err)
+	}
+
+	a, err := log1.Append("branch-a")
+	if err != nil {
+		t.Fatalf("Failed to append to log1: %v", err)
+	}
+	b, err := log2.Append("branch-b")
+	if err != nil {
+		t.Fatalf("Failed to append to log2: %v", err)
+	}
+	if err := log1.Join(log2); err != nil {
+		t.Fatalf("Failed to join log2 into log1: %v", err)
+	}
+
+	heads, err := log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 2 {
+		t.Fatalf("Expected 2 heads before the merge, got %d", len(heads))
+	}
+
+	merge, err := log1.AppendWith("merge", []string{a.Hash, b.Hash}, []string{a.Hash})
+	if err != nil {
+		t.Fatalf("Failed to append merge entry: %v", err)
+	}
+	if len(merge.Next) != 2 || len(merge.Refs) != 1 {
+		t.Errorf("Expected the given links, got next %v refs %v", merge.Next, merge.Refs)
+	}
+	if merge.Clock.Time <= max(a.Clock.Time, b.Clock.Time) {
+		t.Errorf("Expected the merge clock after both parents, got %d", merge.Clock.Time)
+	}
+
+	heads, err = log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 1 || heads[0].Hash != merge.Hash {
+		t.Errorf("Expected the merge entry as the only head, got %v", heads)
+	}
+
+	missing := mustNewEntry(t, ks, identity, "other-log", "missing", NewClock(identity.ID, 1), nil, nil)
+	if _, err := log1.AppendWith("bad", []string{missing.Hash}, nil); err == nil {
+		t.Error("Expected an error for a next link not in the log")
+	}
+	if _, err := log1.AppendWith("bad", []string{merge.Hash}, []string{missing.Hash}); err == nil {
+		t.Error("Expected an error for a ref not in the log")
+	}
+	if length := log1.Length(); length != 3 {
+		t.Errorf("Expected failed appends to leave 3 entries, got %d", length)
+	}
+}
+
+func TestLog_AppendWithNormalizesLinks(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log1, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log1: %v", err)
+	}
+	log2, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log2: %v", err)
+	}
+	a, err := log1.Append("branch-a")
+	if err != nil {
+		t.Fatalf("Failed to append to log1: %v", err)
+	}
+	b, err := log2.Append("branch-b")
+	if err != nil {
+		t.Fatalf("Failed to append to log2: %v", err)
+	}
+	if err := log1.Join(log2); err != nil {
+		t.Fatalf("Failed to join log2 into log1: %v", err)
+	}
+
+	// CID.String() is base32, the entries are stored under base58btc
+	merge, err := log1.AppendWith("merge", []string{a.CID.String(), b.CID.String()}, []string{a.CID.String()})
+	if err != nil {
+		t.Fatalf("Failed to append merge entry with base32 links: %v", err)
+	}
+	next := append([]string(nil), merge.Next...)
+	want := []string{a.Hash, b.Hash}
+	sort.Strings(next)
+	sort.Strings(want)
+	if fmt.Sprint(next) != fmt.Sprint(want) || fmt.Sprint(merge.Refs) != fmt.Sprint([]string{a.Hash}) {
+		t.Errorf("Expected base58btc links, got next %v refs %v", merge.Next, merge.Refs)
+	}
+	heads, err := log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 1 || heads[0].Hash != merge.Hash {
+		t.Errorf("Expected the merge entry as the only head, got %v", heads)
+	}
+
+	// The same entry twice is rejected whatever its encoding
+	if _, err := log1.AppendWith("bad", []string{merge.Hash, merge.CID.String()}, nil); err == nil {
+		t.Error("Expected an error for a duplicate next link")
+	}
+	if _, err := log1.AppendWith("bad", []string{merge.Hash}, []string{a.Hash, a.CID.String()}); err == nil {
+		t.Error("Expected an error for a duplicate ref")
+	}
+	if length := log1.Length(); length != 3 {
+		t.Errorf("Expected failed appends to leave 3 entries, got %d", length)
+	}
+}
+
+func TestLog_Length(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log1, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log1: %v", err)
+	}
+	if !log1.IsEmpty() || log1.Length() != 0 {
+		t.Errorf("Expected a new log to be empty, got length %d", log1.Length())
+	}
+
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		if _, err := log1.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+	if log1.IsEmpty() || log1.Length() != 3 {
+		t.Errorf("Expected length 3 after appends, got %d", log1.Length())
+	}
+
+	// log2 shares log1's entries and adds two of its own
+	log2, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log2: %v", err)
+	}
+	if err := log2.Join(log1); err != nil {
+		t.Fatalf("Failed to join log1 into log2: %v", err)
+	}
+	for _, payload := range []string{"entry4", "entry5"} {
+		if _, err := log2.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	if err := log1.Join(log2); err != nil {
+		t.Fatalf("Failed to join log2 into log1: %v", err)
+	}
+	values, err := log1.Values()
+	if err != nil {
+		t.Fatalf("Failed to get log values: %v", err)
+	}
+	if log1.Length() != 5 || log1.Length() != len(values) {
+		t.Errorf("Expected length 5 matching Values, got %d and %d", log1.Length(), len(values))
+	}
+}
+
 func TestLog_Clear(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
 
@@ -344,6 +721,92 @@ func TestLog_Clear(t *testing.T) {
 	}
 }
 
+func TestLog_Clone(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for _, payload := range []string{"entry1", "entry2"} {
+		if _, err := log.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	clone, err := log.Clone()
+	if err != nil {
+		t.Fatalf("Failed to clone log: %v", err)
+	}
+	if clone.Identity != log.Identity {
+		t.Error("Expected the clone to share the identity")
+	}
+	if clone.Head == nil || clone.Head.Hash != log.Head.Hash || clone.Clock != log.Clock {
+		t.Error("Expected the clone to have the same head and clock")
+	}
+
+	if _, err := clone.Append("entry3"); err != nil {
+		t.Fatalf("Failed to append to clone: %v", err)
+	}
+
+	original, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to get log values: %v", err)
+	}
+	if len(original) != 2 || original[1].Payload != "entry2" {
+		t.Errorf("Expected the original to keep 2 entries, got %d", len(original))
+	}
+	if log.Head.Payload != "entry2" || log.Clock.Time != 2 {
+		t.Errorf("Expected the original head to stay at entry2, got %s", log.Head.Payload)
+	}
+
+	cloned, err := clone.Values()
+	if err != nil {
+		t.Fatalf("Failed to get clone values: %v", err)
+	}
+	if len(cloned) != 3 || cloned[2].Payload != "entry3" {
+		t.Errorf("Expected the clone to have 3 entries, got %d", len(cloned))
+	}
+}
+
+func TestLog_CloneAudit(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	audit := NewRingAudit(10)
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithAccessAudit(audit))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if _, err := log.Append("entry1"); err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// Appends to the clone are not recorded against the original
+	clone, err := log.Clone()
+	if err != nil {
+		t.Fatalf("Failed to clone log: %v", err)
+	}
+	if _, err := clone.Append("speculative"); err != nil {
+		t.Fatalf("Failed to append to clone: %v", err)
+	}
+	if n := len(audit.Records()); n != 1 {
+		t.Errorf("Expected the original audit to keep 1 record, got %d", n)
+	}
+
+	// The clone can be given an audit of its own
+	cloneAudit := NewRingAudit(10)
+	clone, err = log.Clone(WithAccessAudit(cloneAudit))
+	if err != nil {
+		t.Fatalf("Failed to clone log: %v", err)
+	}
+	if _, err := clone.Append("speculative"); err != nil {
+		t.Fatalf("Failed to append to clone: %v", err)
+	}
+	if len(audit.Records()) != 1 || len(cloneAudit.Records()) != 1 {
+		t.Errorf("Expected one record in each audit, got %d and %d", len(audit.Records()), len(cloneAudit.Records()))
+	}
+}
+
 func TestLog_Head(t *testing.T) {
 	ks, identity := setupTestKeyStoreAndIdentity(t)
 
@@ -366,3 +829,967 @@ func TestLog_Head(t *testing.T) {
 			entry.Hash, head.Hash)
 	}
 }
+
+func TestLog_MissingAncestors(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	logID := "test-log"
+	source, err := NewLog(logID, identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+
+	var appended []*EncodedEntry
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		entry, err := source.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		appended = append(appended, entry)
+	}
+
+	if missing := source.MissingAncestors(); len(missing) != 0 {
+		t.Errorf("Expected complete log to have no missing ancestors, got %v", missing)
+	}
+
+	// Load only the head into a second log
+	partial, err := NewLog(logID, identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create partial log: %v", err)
+	}
+	if err := partial.JoinEntry(appended[2], make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join head entry: %v", err)
+	}
+
+	// The head links its parent through Next and its grandparent through Refs
+	missing := partial.MissingAncestors()
+	expected := []string{appended[0].Hash, appended[1].Hash}
+	sort.Strings(expected)
+	if strings.Join(missing, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected missing ancestors %v, got %v", expected, missing)
+	}
+
+	// Fetching the reported ancestor moves the gap further back
+	if err := partial.JoinEntry(appended[1], make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join ancestor entry: %v", err)
+	}
+	missing = partial.MissingAncestors()
+	if len(missing) != 1 || missing[0] != appended[0].Hash {
+		t.Errorf("Expected missing ancestors [%s], got %v", appended[0].Hash, missing)
+	}
+}
+
+func TestLog_VerifyCheckpoint(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	var checkpoint *EncodedEntry
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		checkpoint, err = log.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	root, err := log.StateRoot(checkpoint.Hash)
+	if err != nil {
+		t.Fatalf("Failed to compute state root: %v", err)
+	}
+
+	// Later appends do not change the state root of an earlier head
+	if _, err := log.Append("entry4"); err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	ok, err := log.VerifyCheckpoint(checkpoint.Hash, root)
+	if err != nil || !ok {
+		t.Errorf("Expected matching checkpoint to verify, got %v, %v", ok, err)
+	}
+
+	ok, err = log.VerifyCheckpoint(log.Head.Hash, root)
+	if err != nil || ok {
+		t.Errorf("Expected mismatching checkpoint to fail, got %v, %v", ok, err)
+	}
+
+	ok, err = log.VerifyCheckpoint("zdpuAunknownhead", root)
+	if err == nil || ok {
+		t.Error("Expected unknown checkpoint head to return an error")
+	}
+}
+
+// hookStorage calls onGet before every read.
+type hookStorage struct {
+	storage.Storage
+	onGet func()
+}
+
+func (s *hookStorage) Get(key string) ([]byte, error) {
+	if s.onGet != nil {
+		s.onGet()
+	}
+	return s.Storage.Get(key)
+}
+
+func TestLog_StateRootWithWaitingWriter(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	hooked := &hookStorage{Storage: storage.NewMemoryStorage()}
+	log, err := NewLog("test-log", identity, hooked, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		if _, err := log.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	// Once the walk holds the read lock, start a writer and give it time to
+	// block on the lock; a second read lock taken by the walk would then wait
+	// behind it forever
+	var once sync.Once
+	written := make(chan error, 1)
+	hooked.onGet = func() {
+		once.Do(func() {
+			go func() {
+				_, err := log.Append("concurrent")
+				written <- err
+			}()
+			time.Sleep(50 * time.Millisecond)
+		})
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := log.StateRoot(log.Head.Hash)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Failed to compute state root: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StateRoot deadlocked with a waiting writer")
+	}
+	if err := <-written; err != nil {
+		t.Errorf("Expected the waiting append to succeed, got %v", err)
+	}
+}
+
+func TestLog_GetRejectsMisKeyedEntry(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	first, err := log.Append("first")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	second, err := log.Append("second")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// Store the second entry's bytes under the first entry's key
+	if err := log.Entries.Put(first.Hash, second.Bytes); err != nil {
+		t.Fatalf("Failed to overwrite entry: %v", err)
+	}
+
+	if _, err := log.Get(first.Hash); err == nil {
+		t.Error("Expected Get to reject entry stored under the wrong CID")
+	}
+	if _, err := log.Get(second.Hash); err != nil {
+		t.Errorf("Expected correctly keyed entry to load, got %v", err)
+	}
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to get values: %v", err)
+	}
+	if len(values) != 1 || values[0].Hash != second.Hash {
+		t.Errorf("Expected only the correctly keyed entry in values, got %d entries", len(values))
+	}
+}
+
+// countingStorage counts the writes made for each key and the reads.
+type countingStorage struct {
+	storage.Storage
+	puts map[string]int
+	gets int
+}
+
+func (s *countingStorage) Get(key string) ([]byte, error) {
+	s.gets++
+	return s.Storage.Get(key)
+}
+
+func (s *countingStorage) Put(key string, value []byte) error {
+	s.puts[key]++
+	return s.Storage.Put(key, value)
+}
+
+func TestLog_SharedStorageDedup(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	shared := &countingStorage{Storage: storage.NewMemoryStorage(), puts: make(map[string]int)}
+	log1, err := NewLog("test-log", identity, shared, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	log2, err := NewLog("test-log", identity, shared, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	entry, err := log1.Append("shared payload")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if err := log2.JoinEntry(entry, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join entry: %v", err)
+	}
+
+	if shared.puts[entry.Hash] != 1 {
+		t.Errorf("Expected entry to be stored once, got %d writes", shared.puts[entry.Hash])
+	}
+
+	ch, err := shared.Iterator()
+	if err != nil {
+		t.Fatalf("Failed to iterate storage: %v", err)
+	}
+	blocks := 0
+	for range ch {
+		blocks++
+	}
+	if blocks != 1 {
+		t.Errorf("Expected a single stored block, got %d", blocks)
+	}
+	if log2.Head == nil || log2.Head.Hash != entry.Hash {
+		t.Error("Expected the second log to index the shared entry")
+	}
+}
+
+// failingStorage rejects every write while failing is set.
+type failingStorage struct {
+	storage.Storage
+	failing bool
+}
+
+func (s *failingStorage) Put(key string, value []byte) error {
+	if s.failing {
+		return errors.New("storage unavailable")
+	}
+	return s.Storage.Put(key, value)
+}
+
+func TestLog_AppendTx(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entries := &failingStorage{Storage: storage.NewMemoryStorage()}
+	log, err := NewLog("test-log", identity, entries, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	var committed []string
+	commit := func(entry *EncodedEntry) error {
+		// The entry must already be durable and the head advanced
+		if _, err := log.Get(entry.Hash); err != nil {
+			t.Errorf("Expected entry to be stored before commit: %v", err)
+		}
+		if log.Head == nil || log.Head.Hash != entry.Hash {
+			t.Error("Expected head to be advanced before commit")
+		}
+		committed = append(committed, entry.Hash)
+		return nil
+	}
+
+	first, err := log.AppendTx("first", commit)
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	entries.failing = true
+	if _, err := log.AppendTx("second", commit); err == nil {
+		t.Fatal("Expected append to fail when storage fails")
+	}
+
+	if log.Head.Hash != first.Hash {
+		t.Error("Expected head to be unchanged after a failed append")
+	}
+	if log.Clock.Time != 1 {
+		t.Errorf("Expected clock time 1 after a failed append, got %d", log.Clock.Time)
+	}
+	if len(committed) != 1 || committed[0] != first.Hash {
+		t.Errorf("Expected only the first entry to be committed, got %v", committed)
+	}
+}
+
+func TestLog_StreamFromStorage(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entryStorage := storage.NewMemoryStorage()
+	writer, err := NewLog("test-log", identity, entryStorage, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	const count = 1000
+	for i := 0; i < count; i++ {
+		if _, err := writer.Append(fmt.Sprintf("entry-%d", i)); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	// A fresh log over the persisted storage streams without loading an index
+	reader, err := NewLog("test-log", identity, entryStorage, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	expected := count
+	for entry := range reader.StreamFromStorage(context.Background(), []string{writer.Head.Hash}) {
+		if entry.Clock.Time != expected {
+			t.Fatalf("Expected clock time %d, got %d", expected, entry.Clock.Time)
+		}
+		expected--
+	}
+	if expected != 0 {
+		t.Errorf("Expected to stream %d entries, missed %d", count, expected)
+	}
+
+	// Cancelling the context stops the stream early
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := reader.StreamFromStorage(ctx, []string{writer.Head.Hash})
+	<-stream
+	cancel()
+	received := 1
+	for range stream {
+		received++
+	}
+	if received >= count {
+		t.Errorf("Expected cancellation to stop the stream early, received %d entries", received)
+	}
+}
+
+func TestLog_Heads(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	var appended []*EncodedEntry
+	for _, payload := range []string{"one", "two", "three"} {
+		entry, err := log.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		appended = append(appended, entry)
+	}
+
+	heads, err := log.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 1 || heads[0].Hash != appended[2].Hash {
+		t.Fatalf("Expected the last entry as the single head, got %d heads", len(heads))
+	}
+
+	// The head chain links backward through Next
+	for i := len(appended) - 1; i > 0; i-- {
+		next := appended[i].Next
+		if len(next) != 1 || next[0] != appended[i-1].Hash {
+			t.Errorf("Expected entry %d to point at entry %d, got %v", i, i-1, next)
+		}
+	}
+	if len(appended[0].Next) != 0 {
+		t.Errorf("Expected the first entry to have no Next, got %v", appended[0].Next)
+	}
+
+	// A concurrent branch from another writer adds a second head
+	otherKs, other := setupTestKeyStoreAndIdentity(t)
+	otherLog, err := NewLog("test-log", other, storage.NewMemoryStorage(), otherKs)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	branch, err := otherLog.Append("branch")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if err := log.JoinEntry(branch, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join entry: %v", err)
+	}
+
+	heads, err = log.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 2 || heads[0].Hash != appended[2].Hash || heads[1].Hash != branch.Hash {
+		t.Errorf("Expected heads [%s %s], got %d heads", appended[2].Hash, branch.Hash, len(heads))
+	}
+}
+
+func TestLog_JoinDivergent(t *testing.T) {
+	ks1, identity1 := setupTestKeyStoreAndIdentity(t)
+	ks2, identity2 := setupTestKeyStoreAndIdentity(t)
+
+	log1, err := NewLog("test-log", identity1, storage.NewMemoryStorage(), ks1)
+	if err != nil {
+		t.Fatalf("Failed to create log1: %v", err)
+	}
+	log2, err := NewLog("test-log", identity2, storage.NewMemoryStorage(), ks2)
+	if err != nil {
+		t.Fatalf("Failed to create log2: %v", err)
+	}
+
+	// Both logs share a common ancestor, then diverge
+	ancestor, err := log1.Append("ancestor")
+	if err != nil {
+		t.Fatalf("Failed to append to log1: %v", err)
+	}
+	if err := log2.Join(log1); err != nil {
+		t.Fatalf("Failed to join log1 into log2: %v", err)
+	}
+	if log2.Clock.Time != ancestor.Clock.Time {
+		t.Errorf("Expected join to advance the clock to %d, got %d", ancestor.Clock.Time, log2.Clock.Time)
+	}
+
+	left, err := log1.Append("left")
+	if err != nil {
+		t.Fatalf("Failed to append to log1: %v", err)
+	}
+	right, err := log2.Append("right")
+	if err != nil {
+		t.Fatalf("Failed to append to log2: %v", err)
+	}
+
+	// A tampered entry in log2 must be rejected without stopping the join
+	tampered := *right
+	tampered.Entry.Payload = "tampered"
+	tampered = mustEncode(t, tampered.Entry)
+	if err := log2.Entries.Put(tampered.Hash, tampered.Bytes); err != nil {
+		t.Fatalf("Failed to store tampered entry: %v", err)
+	}
+
+	err = log1.Join(log2)
+	if err == nil || !strings.Contains(err.Error(), tampered.Hash) {
+		t.Fatalf("Expected join error naming %s, got %v", tampered.Hash, err)
+	}
+
+	values, err := log1.Values()
+	if err != nil {
+		t.Fatalf("Failed to get log1 values: %v", err)
+	}
+	if len(values) != 3 {
+		t.Errorf("Expected 3 entries after join, got %d", len(values))
+	}
+
+	heads, err := log1.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 2 {
+		t.Fatalf("Expected 2 heads after joining divergent logs, got %d", len(heads))
+	}
+	for _, head := range heads {
+		if head.Hash == ancestor.Hash {
+			t.Error("Expected the common ancestor not to be a head")
+		}
+		if head.Hash != left.Hash && head.Hash != right.Hash {
+			t.Errorf("Unexpected head %s", head.Hash)
+		}
+	}
+}
+
+func TestLog_PersistsAcrossReopen(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	path := t.TempDir()
+
+	entries, err := storage.NewLevelStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create LevelStorage: %v", err)
+	}
+	log, err := NewLog("test-log", identity, entries, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for _, payload := range []string{"first", "second"} {
+		if _, err := log.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Failed to close log: %v", err)
+	}
+
+	// Reopening the same path restores the entries
+	reopened, err := storage.NewLevelStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen LevelStorage: %v", err)
+	}
+	log, err = NewLog("test-log", identity, reopened, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	defer log.Close()
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != 2 || values[0].Payload != "first" || values[1].Payload != "second" {
+		t.Fatalf("Expected both entries after reopening, got %v", values)
+	}
+}
+
+func TestLog_Prune(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	first, err := log.Append("first")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	second, err := log.Append("second")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	if err := log.Prune([]string{second.Hash}); err == nil {
+		t.Fatal("Expected error pruning the head entry")
+	}
+	if err := log.Prune([]string{first.Hash}); err != nil {
+		t.Fatalf("Failed to prune entry: %v", err)
+	}
+
+	if _, err := log.Get(first.Hash); err == nil {
+		t.Fatal("Expected pruned entry to be gone")
+	}
+	if missing := log.MissingAncestors(); len(missing) != 1 || missing[0] != first.Hash {
+		t.Fatalf("Expected pruned entry to be reported missing, got %v", missing)
+	}
+}
+
+func TestLog_ValuesByInsertion(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	var appended []*EncodedEntry
+	for _, payload := range []string{"first", "second", "third"} {
+		entry, err := source.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		appended = append(appended, entry)
+	}
+
+	// The source log stored its entries in causal order
+	if got := payloadsOf(source.ValuesByInsertion()); fmt.Sprint(got) != "[first second third]" {
+		t.Fatalf("Expected insertion order [first second third], got %v", got)
+	}
+
+	// A replica receives the entries out of order
+	replica, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for _, i := range []int{2, 0, 1} {
+		if err := replica.JoinEntry(appended[i], make(map[string]bool)); err != nil {
+			t.Fatalf("Failed to join entry: %v", err)
+		}
+	}
+
+	if got := payloadsOf(replica.ValuesByInsertion()); fmt.Sprint(got) != "[third first second]" {
+		t.Fatalf("Expected insertion order [third first second], got %v", got)
+	}
+	values, err := replica.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if got := payloadsOf(values); fmt.Sprint(got) != "[first second third]" {
+		t.Fatalf("Expected causal order [first second third], got %v", got)
+	}
+
+	// Entries already in storage when a log is created come first
+	reopened, err := NewLog("test-log", identity, replica.Entries, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if got := payloadsOf(reopened.ValuesByInsertion()); fmt.Sprint(got) != "[first second third]" {
+		t.Fatalf("Expected preexisting entries in causal order, got %v", got)
+	}
+}
+
+func payloadsOf(entries []EncodedEntry) []string {
+	payloads := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		payloads = append(payloads, entry.Payload)
+	}
+	return payloads
+}
+
+func TestLog_AppendSingleHeadMatchesHeads(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	for _, payload := range []string{"one", "two", "three"} {
+		heads, err := log.Heads()
+		if err != nil {
+			t.Fatalf("Failed to get heads: %v", err)
+		}
+
+		// The general rule: link every head and tick past the largest clock
+		expectedNext := []string{}
+		expectedTime := 0
+		for _, head := range heads {
+			expectedNext = append(expectedNext, head.Hash)
+			if head.Clock.Time > expectedTime {
+				expectedTime = head.Clock.Time
+			}
+		}
+		expectedTime++
+
+		entry, err := log.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		if strings.Join(entry.Next, ",") != strings.Join(expectedNext, ",") {
+			t.Errorf("Expected next %v, got %v", expectedNext, entry.Next)
+		}
+		if entry.Clock.Time != expectedTime {
+			t.Errorf("Expected clock time %d, got %d", expectedTime, entry.Clock.Time)
+		}
+	}
+}
+
+func BenchmarkLog_AppendSingleHead(b *testing.B) {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity("bench-ID")
+	if err != nil {
+		b.Fatalf("Failed to create identity: %v", err)
+	}
+
+	log, err := NewLog("bench-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		b.Fatalf("Failed to create log: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := log.Append("payload " + strconv.Itoa(i)); err != nil {
+			b.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+}
+
+func TestLog_AppendBatch(t *testing.T) {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity("batch-ID")
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	log, err := NewLog("batch-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	first, err := log.Append("first")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	entries, err := log.AppendBatch([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Failed to append batch: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	// Each entry links the previous one, starting from the old head
+	previous := first.Hash
+	for i, entry := range entries {
+		if len(entry.Next) != 1 || entry.Next[0] != previous {
+			t.Errorf("Entry %d: expected next [%s], got %v", i, previous, entry.Next)
+		}
+		if entry.Clock.Time != i+2 {
+			t.Errorf("Entry %d: expected clock time %d, got %d", i, i+2, entry.Clock.Time)
+		}
+		if _, err := log.Get(entry.Hash); err != nil {
+			t.Errorf("Entry %d not stored: %v", i, err)
+		}
+		previous = entry.Hash
+	}
+
+	heads, err := log.Heads()
+	if err != nil {
+		t.Fatalf("Failed to get heads: %v", err)
+	}
+	if len(heads) != 1 || heads[0].Hash != entries[2].Hash || log.Head.Hash != entries[2].Hash {
+		t.Errorf("Expected the last batch entry to be the only head, got %v", hashesOf(heads))
+	}
+	if log.Clock.Time != 4 {
+		t.Errorf("Expected clock time 4, got %d", log.Clock.Time)
+	}
+
+	// An invalid payload leaves the log untouched
+	if _, err := log.AppendBatch([]string{"d", ""}); err == nil {
+		t.Error("Expected an empty payload to be rejected")
+	}
+	if log.Head.Hash != entries[2].Hash || log.Clock.Time != 4 {
+		t.Error("Expected a rejected batch not to advance the log")
+	}
+}
+
+func BenchmarkLog_AppendBatch(b *testing.B) {
+	payloads := make([]string, 10000)
+	for i := range payloads {
+		payloads[i] = "payload " + strconv.Itoa(i)
+	}
+
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity("bench-ID")
+	if err != nil {
+		b.Fatalf("Failed to create identity: %v", err)
+	}
+
+	b.Run("Individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			log, err := NewLog("bench-log", identity, storage.NewMemoryStorage(), ks)
+			if err != nil {
+				b.Fatalf("Failed to create log: %v", err)
+			}
+			for _, payload := range payloads {
+				if _, err := log.Append(payload); err != nil {
+					b.Fatalf("Failed to append entry: %v", err)
+				}
+			}
+		}
+	})
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			log, err := NewLog("bench-log", identity, storage.NewMemoryStorage(), ks)
+			if err != nil {
+				b.Fatalf("Failed to create log: %v", err)
+			}
+			if _, err := log.AppendBatch(payloads); err != nil {
+				b.Fatalf("Failed to append batch: %v", err)
+			}
+		}
+	})
+}
+
+func hashesOf(entries []EncodedEntry) []string {
+	hashes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	return hashes
+}
+
+func TestLog_HistoryBranchAndMerge(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	otherKs, other := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	root, err := log.Append("root")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+
+	// Two writers branch from the root
+	left, err := log.Append("left")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	right := mustNewEntry(t, otherKs, other, "test-log", "right", NewClock(other.ID, 2), []string{root.Hash}, nil)
+	if err := log.JoinEntry(&right, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join entry: %v", err)
+	}
+
+	// A merge entry references both branches
+	merge := mustNewEntry(t, ks, identity, "test-log", "merge", NewClock(identity.ID, 3), []string{left.Hash, right.Hash}, nil)
+	if err := log.JoinEntry(&merge, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join entry: %v", err)
+	}
+
+	history, err := log.History()
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, entry := range history {
+		counts[entry.Payload]++
+	}
+	for _, payload := range []string{"root", "left", "right", "merge"} {
+		if counts[payload] != 1 {
+			t.Errorf("Expected %q exactly once, got %d", payload, counts[payload])
+		}
+	}
+	if len(history) != 4 || history[0].Hash != root.Hash || history[3].Hash != merge.Hash {
+		t.Errorf("Expected root first and merge last, got %v", payloadsOf(history))
+	}
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if fmt.Sprint(hashesOf(values)) != fmt.Sprint(hashesOf(history)) {
+		t.Errorf("Expected Values and History to agree, got %v and %v", payloadsOf(values), payloadsOf(history))
+	}
+
+	// A missing ancestor is reported by CID
+	if err := log.Prune([]string{root.Hash}); err != nil {
+		t.Fatalf("Failed to prune entry: %v", err)
+	}
+	_, err = log.History()
+	if !errors.Is(err, ErrEntryMissing) || !strings.Contains(err.Error(), root.Hash) {
+		t.Errorf("Expected ErrEntryMissing naming %s, got %v", root.Hash, err)
+	}
+}
+
+func TestLog_AppendAfterJoinIsCausallyLater(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	other, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	if _, err := log.Append("local"); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := other.Append("other"); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+	if err := log.Join(other); err != nil {
+		t.Fatalf("Failed to join: %v", err)
+	}
+
+	entry, err := log.Append("after join")
+	if err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if entry.Clock.Time <= other.Clock.Time {
+		t.Errorf("expected entry time to exceed the joined maximum %d, got %d", other.Clock.Time, entry.Clock.Time)
+	}
+}
+
+func TestLog_AppendBackReferences(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	var chain []*EncodedEntry
+	for i := 0; i < 16; i++ {
+		entry, err := log.Append("entry" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		chain = append(chain, entry)
+	}
+
+	// Entry i refs the entries 2, 4, 8, … positions before it, sorted
+	for i, entry := range chain {
+		var expected []string
+		for distance := 2; distance <= i; distance *= 2 {
+			expected = append(expected, chain[i-distance].Hash)
+		}
+		sort.Strings(expected)
+		if strings.Join(entry.Refs, ",") != strings.Join(expected, ",") {
+			t.Errorf("Entry %d: expected refs %v, got %v", i, expected, entry.Refs)
+		}
+	}
+	if len(chain[15].Refs) != 3 {
+		t.Errorf("Expected the last entry to ref 3 ancestors, got %d", len(chain[15].Refs))
+	}
+
+	// A batch continues the same references
+	batch, err := log.AppendBatch([]string{"entry16", "entry17"})
+	if err != nil {
+		t.Fatalf("Failed to append batch: %v", err)
+	}
+	chain = append(chain, &batch[0], &batch[1])
+	expected := []string{chain[15].Hash, chain[13].Hash, chain[9].Hash, chain[1].Hash}
+	sort.Strings(expected)
+	if strings.Join(batch[1].Refs, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected batch refs %v, got %v", expected, batch[1].Refs)
+	}
+
+	// The ancestry of the head is cached, so appends do not walk storage
+	counted := &countingStorage{Storage: storage.NewMemoryStorage(), puts: make(map[string]int)}
+	cached, err := NewLog("test-log", identity, counted, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	var appended []*EncodedEntry
+	for i := 0; i < 40; i++ {
+		before := counted.gets
+		entry, err := cached.Append("entry" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		if reads := counted.gets - before; reads > 1 {
+			t.Errorf("Append %d: expected at most 1 storage read, got %d", i, reads)
+		}
+		appended = append(appended, entry)
+
+		var expected []string
+		for distance := 2; distance <= i && distance <= DefaultReferencesCount; distance *= 2 {
+			expected = append(expected, appended[i-distance].Hash)
+		}
+		sort.Strings(expected)
+		if strings.Join(entry.Refs, ",") != strings.Join(expected, ",") {
+			t.Errorf("Append %d: expected refs %v, got %v", i, expected, entry.Refs)
+		}
+	}
+
+	// Refs can be disabled
+	plain, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithReferencesCount(0))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		entry, err := plain.Append("entry" + strconv.Itoa(i))
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		if len(entry.Refs) != 0 {
+			t.Errorf("Expected no refs with references disabled, got %v", entry.Refs)
+		}
+	}
+}
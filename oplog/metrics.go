@@ -0,0 +1,97 @@
+package oplog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metrics receives latency observations for log operations. Logs record
+// nothing unless created with WithMetrics.
+type Metrics interface {
+	ObserveLatency(op string, d time.Duration)
+}
+
+// latencyBuckets are the upper bounds of the histogram buckets, doubling
+// from 1µs to roughly 67s. Slower observations land in a final overflow bucket.
+var latencyBuckets = func() []time.Duration {
+	bounds := make([]time.Duration, 27)
+	for i := range bounds {
+		bounds[i] = time.Microsecond << i
+	}
+	return bounds
+}()
+
+// LatencyHistogram is a Metrics implementation keeping a bucketed latency
+// histogram per operation.
+type LatencyHistogram struct {
+	counts map[string][]uint64
+	totals map[string]uint64
+	mu     sync.Mutex
+}
+
+// NewLatencyHistogram creates an empty LatencyHistogram.
+func NewLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{
+		counts: make(map[string][]uint64),
+		totals: make(map[string]uint64),
+	}
+}
+
+// ObserveLatency implements Metrics.
+func (h *LatencyHistogram) ObserveLatency(op string, d time.Duration) {
+	bucket := sort.Search(len(latencyBuckets), func(i int) bool { return d <= latencyBuckets[i] })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts, ok := h.counts[op]
+	if !ok {
+		counts = make([]uint64, len(latencyBuckets)+1)
+		h.counts[op] = counts
+	}
+	counts[bucket]++
+	h.totals[op]++
+}
+
+// Count returns the number of observations recorded for op.
+func (h *LatencyHistogram) Count(op string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.totals[op]
+}
+
+// Percentile returns the upper bound of the bucket holding the p-th
+// percentile (0 < p <= 100) of latencies recorded for op, or 0 when nothing
+// has been recorded.
+func (h *LatencyHistogram) Percentile(op string, p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	total := h.totals[op]
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(p / 100 * float64(total))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, count := range h.counts[op] {
+		seen += count
+		if seen >= rank {
+			if i == len(latencyBuckets) {
+				break
+			}
+			return latencyBuckets[i]
+		}
+	}
+	return latencyBuckets[len(latencyBuckets)-1]
+}
+
+// observe records the latency of an operation started at start.
+func (l *Log) observe(op string, start time.Time) {
+	l.metrics.ObserveLatency(op, time.Since(start))
+}
@@ -0,0 +1,81 @@
+package oplog
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+// fakeMetrics records every observation it receives.
+type fakeMetrics struct {
+	ops []string
+	mu  sync.Mutex
+}
+
+func (m *fakeMetrics) ObserveLatency(op string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops = append(m.ops, op)
+}
+
+func TestLog_Metrics(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	metrics := &fakeMetrics{}
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	for _, payload := range []string{"one", "two", "three"} {
+		if _, err := log.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	other, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if _, err := other.Append("remote"); err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if err := log.Join(other); err != nil {
+		t.Fatalf("Failed to join logs: %v", err)
+	}
+
+	expected := []string{OpAppend, OpAppend, OpAppend, OpJoin}
+	if len(metrics.ops) != len(expected) {
+		t.Fatalf("Expected %d observations, got %v", len(expected), metrics.ops)
+	}
+	for i, op := range expected {
+		if metrics.ops[i] != op {
+			t.Errorf("Observation %d: expected %s, got %s", i, op, metrics.ops[i])
+		}
+	}
+}
+
+func TestLatencyHistogram_Percentile(t *testing.T) {
+	h := NewLatencyHistogram()
+	if h.Percentile(OpAppend, 50) != 0 {
+		t.Error("Expected zero percentile without observations")
+	}
+
+	for i := 0; i < 98; i++ {
+		h.ObserveLatency(OpAppend, 3*time.Microsecond)
+	}
+	h.ObserveLatency(OpAppend, 10*time.Millisecond)
+	h.ObserveLatency(OpAppend, 10*time.Millisecond)
+
+	if h.Count(OpAppend) != 100 {
+		t.Errorf("Expected 100 observations, got %d", h.Count(OpAppend))
+	}
+	if p50 := h.Percentile(OpAppend, 50); p50 != 4*time.Microsecond {
+		t.Errorf("Expected p50 bucket 4µs, got %s", p50)
+	}
+	if p99 := h.Percentile(OpAppend, 99); p99 < 10*time.Millisecond || p99 > 20*time.Millisecond {
+		t.Errorf("Expected p99 bucket around 10ms, got %s", p99)
+	}
+}
@@ -0,0 +1,120 @@
+// Package oplogtest provides helpers for testing replication between logs.
+package oplogtest
+
+import (
+	"fmt"
+	"orbitdb/go-orbitdb/oplog"
+	"reflect"
+)
+
+// MaxRounds bounds how many rounds of pairwise joins AssertConverged runs
+// before comparing the logs.
+var MaxRounds = 16
+
+// TestingT is the subset of testing.TB used by AssertConverged.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// snapshot is the replicated state of a log compared by AssertConverged.
+type snapshot struct {
+	Heads  []string
+	Values []string
+	Roots  []string
+}
+
+// AssertConverged joins every log into every other log until no log gains
+// entries, then asserts that all logs have identical heads, values and state
+// roots. Join errors are tolerated, since a rejected entry shows up as a
+// divergence. It reports whether the logs converged.
+func AssertConverged(t TestingT, logs ...*oplog.Log) bool {
+	t.Helper()
+	if len(logs) < 2 {
+		return true
+	}
+
+	for round := 0; round < MaxRounds; round++ {
+		changed := false
+		for i, dst := range logs {
+			for j, src := range logs {
+				if i == j {
+					continue
+				}
+				before := countEntries(dst)
+				_ = dst.Join(src)
+				if countEntries(dst) != before {
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	want, err := snapshotOf(logs[0])
+	if err != nil {
+		t.Errorf("log 0: %v", err)
+		return false
+	}
+
+	converged := true
+	for i, l := range logs[1:] {
+		got, err := snapshotOf(l)
+		if err != nil {
+			t.Errorf("log %d: %v", i+1, err)
+			converged = false
+			continue
+		}
+		if !reflect.DeepEqual(want.Heads, got.Heads) {
+			t.Errorf("log %d heads %v differ from log 0 heads %v", i+1, got.Heads, want.Heads)
+			converged = false
+		}
+		if !reflect.DeepEqual(want.Values, got.Values) {
+			t.Errorf("log %d has %d values, log 0 has %d; values differ", i+1, len(got.Values), len(want.Values))
+			converged = false
+		}
+		if !reflect.DeepEqual(want.Roots, got.Roots) {
+			t.Errorf("log %d state roots %v differ from log 0 state roots %v", i+1, got.Roots, want.Roots)
+			converged = false
+		}
+	}
+	return converged
+}
+
+// snapshotOf collects the heads, value hashes and per-head state roots of a log.
+func snapshotOf(l *oplog.Log) (snapshot, error) {
+	var s snapshot
+
+	heads, err := l.Heads()
+	if err != nil {
+		return s, fmt.Errorf("failed to read heads: %w", err)
+	}
+	for _, head := range heads {
+		root, err := l.StateRoot(head.Hash)
+		if err != nil {
+			return s, fmt.Errorf("failed to compute state root for %s: %w", head.Hash, err)
+		}
+		s.Heads = append(s.Heads, head.Hash)
+		s.Roots = append(s.Roots, root)
+	}
+
+	values, err := l.Values()
+	if err != nil {
+		return s, fmt.Errorf("failed to read values: %w", err)
+	}
+	for _, value := range values {
+		s.Values = append(s.Values, value.Hash)
+	}
+	return s, nil
+}
+
+// countEntries returns the number of entries stored by a log.
+func countEntries(l *oplog.Log) int {
+	values, err := l.Values()
+	if err != nil {
+		return -1
+	}
+	return len(values)
+}
@@ -0,0 +1,100 @@
+package oplogtest
+
+import (
+	"fmt"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/oplog"
+	"orbitdb/go-orbitdb/storage"
+	"testing"
+)
+
+// recorder captures assertion failures instead of failing the test.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// ownEntriesOnly only accepts entries written by the given identity.
+type ownEntriesOnly struct {
+	hash string
+}
+
+func (a ownEntriesOnly) Type() string { return "own-entries" }
+
+func (a ownEntriesOnly) CanAppend(entry oplog.EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	return entry.Identity == a.hash, nil
+}
+
+func newTestLog(t *testing.T, name string, opts ...func(*identitytypes.Identity) oplog.LogOption) *oplog.Log {
+	t.Helper()
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity(name)
+	if err != nil {
+		t.Fatalf("Failed to create identity: %v", err)
+	}
+	var logOpts []oplog.LogOption
+	for _, opt := range opts {
+		logOpts = append(logOpts, opt(identity))
+	}
+	log, err := oplog.NewLog("test-log", identity, nil, ks, logOpts...)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	return log
+}
+
+func appendAll(t *testing.T, log *oplog.Log, payloads ...string) {
+	t.Helper()
+	for _, payload := range payloads {
+		if _, err := log.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+}
+
+func TestAssertConverged(t *testing.T) {
+	a := newTestLog(t, "alice")
+	b := newTestLog(t, "bob")
+	c := newTestLog(t, "carol")
+
+	appendAll(t, a, "a1", "a2")
+	appendAll(t, b, "b1")
+	appendAll(t, c, "c1", "c2", "c3")
+
+	if !AssertConverged(t, a, b, c) {
+		t.Fatal("Expected logs to converge")
+	}
+
+	values, err := a.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != 6 {
+		t.Fatalf("Expected 6 values after convergence, got %d", len(values))
+	}
+}
+
+func TestAssertConvergedDivergence(t *testing.T) {
+	a := newTestLog(t, "alice")
+	b := newTestLog(t, "bob", func(identity *identitytypes.Identity) oplog.LogOption {
+		return oplog.WithAccessController(ownEntriesOnly{hash: identity.Hash})
+	})
+
+	appendAll(t, a, "a1")
+	appendAll(t, b, "b1")
+
+	r := &recorder{}
+	if AssertConverged(r, a, b) {
+		t.Fatal("Expected logs rejecting each other's entries to diverge")
+	}
+	if len(r.errors) == 0 {
+		t.Fatal("Expected divergence to be reported")
+	}
+}
@@ -0,0 +1,74 @@
+package oplog
+
+import (
+	"crypto/sha256"
+	"sort"
+)
+
+// TieBreak selects how entries with identical clocks are ordered.
+type TieBreak int
+
+const (
+	// TieBreakCID orders tied entries by their CID string. It is the default.
+	TieBreakCID TieBreak = iota
+	// TieBreakBytes orders tied entries by a SHA-256 of their encoded bytes,
+	// so peers agree on the order even if they derive CIDs with different
+	// multihash functions.
+	TieBreakBytes
+)
+
+// WithTieBreak sets how Values and History order entries with identical clocks.
+func WithTieBreak(tieBreak TieBreak) LogOption {
+	return func(l *Log) {
+		l.tieBreak = tieBreak
+	}
+}
+
+// SortEntries returns a copy of entries in the canonical log order: by clock
+// time, then clock id, then CID. The order depends only on the entries, so
+// every peer replays merged logs identically.
+func SortEntries(entries []EncodedEntry) []EncodedEntry {
+	return SortEntriesBy(entries, TieBreakCID)
+}
+
+// SortEntriesBy returns a copy of entries ordered by clock time, then clock
+// id, then the given tie-break.
+func SortEntriesBy(entries []EncodedEntry, tieBreak TieBreak) []EncodedEntry {
+	sorted := append([]EncodedEntry(nil), entries...)
+	sortEntries(sorted, tieBreak)
+	return sorted
+}
+
+// sortEntries sorts entries in place in the order of SortEntriesBy.
+func sortEntries(entries []EncodedEntry, tieBreak TieBreak) {
+	if tieBreak != TieBreakBytes {
+		sort.Slice(entries, func(i, j int) bool {
+			if diff := CompareClocks(entries[i].Clock, entries[j].Clock); diff != 0 {
+				return diff < 0
+			}
+			return entries[i].Hash < entries[j].Hash
+		})
+		return
+	}
+
+	// Hash each entry once rather than on every comparison
+	keyed := make([]bytesKeyed, len(entries))
+	for i, entry := range entries {
+		keyed[i] = bytesKeyed{entry: entry, key: sha256.Sum256(entry.Bytes)}
+	}
+	sort.Slice(keyed, func(i, j int) bool {
+		if diff := CompareClocks(keyed[i].entry.Clock, keyed[j].entry.Clock); diff != 0 {
+			return diff < 0
+		}
+		return string(keyed[i].key[:]) < string(keyed[j].key[:])
+	})
+	for i := range keyed {
+		entries[i] = keyed[i].entry
+	}
+}
+
+// bytesKeyed pairs an entry with the SHA-256 of its encoded bytes.
+type bytesKeyed struct {
+	entry EncodedEntry
+	key   [sha256.Size]byte
+}
@@ -0,0 +1,98 @@
+package oplog
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+	"orbitdb/go-orbitdb/storage"
+)
+
+func TestSortEntries(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	first := mustNewEntry(t, ks, identity, "test-log", "first", Clock{ID: "a", Time: 1}, nil, nil)
+	second := mustNewEntry(t, ks, identity, "test-log", "second", Clock{ID: "b", Time: 1}, nil, nil)
+	tieX := mustNewEntry(t, ks, identity, "test-log", "tie x", Clock{ID: "a", Time: 2}, nil, nil)
+	tieY := mustNewEntry(t, ks, identity, "test-log", "tie y", Clock{ID: "a", Time: 2}, nil, nil)
+	last := mustNewEntry(t, ks, identity, "test-log", "last", Clock{ID: "a", Time: 3}, nil, nil)
+
+	// Identical clocks fall back to the CID
+	if tieY.Hash < tieX.Hash {
+		tieX, tieY = tieY, tieX
+	}
+	expected := []string{first.Hash, second.Hash, tieX.Hash, tieY.Hash, last.Hash}
+
+	input := []EncodedEntry{first, second, tieX, tieY, last}
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		rng.Shuffle(len(input), func(i, j int) { input[i], input[j] = input[j], input[i] })
+		before := fmt.Sprint(hashesOf(input))
+
+		sorted := SortEntries(input)
+		if got := hashesOf(sorted); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("Round %d: expected order %v, got %v", round, expected, got)
+		}
+		if fmt.Sprint(hashesOf(input)) != before {
+			t.Fatal("Expected SortEntries to leave its input unchanged")
+		}
+	}
+}
+
+// withMultihash returns the entry with its CID derived using another multihash.
+func withMultihash(t *testing.T, entry EncodedEntry, code uint64) EncodedEntry {
+	t.Helper()
+
+	digest, err := mh.Sum(entry.Bytes, code, -1)
+	if err != nil {
+		t.Fatalf("Failed to hash entry: %v", err)
+	}
+	entry.CID = cid.NewCidV1(cid.DagCBOR, digest)
+	if entry.Hash, err = entry.CID.StringOfBase(multibase.Base58BTC); err != nil {
+		t.Fatalf("Failed to encode CID: %v", err)
+	}
+	return entry
+}
+
+func TestSortEntriesByBytes(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	var sha256Peer, blake2bPeer []EncodedEntry
+	for _, payload := range []string{"a", "b", "c", "d", "e", "f"} {
+		entry := mustNewEntry(t, ks, identity, "test-log", payload, Clock{ID: "a", Time: 1}, nil, nil)
+		sha256Peer = append(sha256Peer, entry)
+		blake2bPeer = append(blake2bPeer, withMultihash(t, entry, mh.BLAKE2B_MIN+31))
+	}
+	if sha256Peer[0].Hash == blake2bPeer[0].Hash {
+		t.Fatal("Expected the peers to derive different CIDs")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	rng.Shuffle(len(blake2bPeer), func(i, j int) { blake2bPeer[i], blake2bPeer[j] = blake2bPeer[j], blake2bPeer[i] })
+
+	expected := payloadsOf(SortEntriesBy(sha256Peer, TieBreakBytes))
+	if got := payloadsOf(SortEntriesBy(blake2bPeer, TieBreakBytes)); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected peers to agree on order %v, got %v", expected, got)
+	}
+
+	// Logs configured with the option order Values the same way
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithTieBreak(TieBreakBytes))
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for i := range sha256Peer {
+		if err := log.JoinEntry(&sha256Peer[i], make(map[string]bool)); err != nil {
+			t.Fatalf("Failed to join entry: %v", err)
+		}
+	}
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if got := payloadsOf(values); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected Values in order %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,161 @@
+package oplog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+	"github.com/ipld/go-ipld-prime/datamodel"
+	"github.com/ipld/go-ipld-prime/node/basicnode"
+)
+
+// EncodePayload encodes a structured value as DAG-CBOR for an entry's
+// PayloadData, so databases can store maps and lists without wrapping them
+// in strings. Supported values are nil, booleans, integers, floats, strings,
+// byte slices, and slices and string-keyed maps of these.
+func EncodePayload(v any) ([]byte, error) {
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := assembleValue(nb, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := dagcbor.Encode(nb.Build(), &buf); err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodePayload decodes payload data written by EncodePayload. Maps decode
+// as map[string]any, lists as []any, integers as int64 and floats as
+// float64, so numbers keep their kind instead of all becoming float64 as
+// with JSON.
+func DecodePayload(data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, errors.New("invalid or empty payload data")
+	}
+	nb := basicnode.Prototype.Any.NewBuilder()
+	if err := dagcbor.Decode(nb, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return nodeValue(nb.Build())
+}
+
+// assembleValue assigns a Go value to a node assembler.
+func assembleValue(na datamodel.NodeAssembler, v reflect.Value) error {
+	if !v.IsValid() {
+		return na.AssignNull()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return na.AssignNull()
+		}
+		return assembleValue(na, v.Elem())
+	case reflect.Bool:
+		return na.AssignBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return na.AssignInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return na.AssignInt(int64(v.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return na.AssignFloat(v.Float())
+	case reflect.String:
+		return na.AssignString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return na.AssignBytes(v.Bytes())
+		}
+		la, err := na.BeginList(int64(v.Len()))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := assembleValue(la.AssembleValue(), v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return la.Finish()
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported payload map key type %s", v.Type().Key())
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+
+		ma, err := na.BeginMap(int64(len(keys)))
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := ma.AssembleKey().AssignString(key); err != nil {
+				return err
+			}
+			if err := assembleValue(ma.AssembleValue(), v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))); err != nil {
+				return err
+			}
+		}
+		return ma.Finish()
+	default:
+		return fmt.Errorf("unsupported payload type %s", v.Type())
+	}
+}
+
+// nodeValue converts a decoded node back into Go values.
+func nodeValue(node datamodel.Node) (any, error) {
+	switch node.Kind() {
+	case datamodel.Kind_Null:
+		return nil, nil
+	case datamodel.Kind_Bool:
+		return node.AsBool()
+	case datamodel.Kind_Int:
+		return node.AsInt()
+	case datamodel.Kind_Float:
+		return node.AsFloat()
+	case datamodel.Kind_String:
+		return node.AsString()
+	case datamodel.Kind_Bytes:
+		return node.AsBytes()
+	case datamodel.Kind_List:
+		list := make([]any, 0, node.Length())
+		it := node.ListIterator()
+		for !it.Done() {
+			_, item, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+			value, err := nodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case datamodel.Kind_Map:
+		m := make(map[string]any, node.Length())
+		it := node.MapIterator()
+		for !it.Done() {
+			k, item, err := it.Next()
+			if err != nil {
+				return nil, err
+			}
+			key, err := k.AsString()
+			if err != nil {
+				return nil, err
+			}
+			if m[key], err = nodeValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unsupported payload node kind %s", node.Kind())
+	}
+}
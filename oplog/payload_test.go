@@ -0,0 +1,118 @@
+package oplog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	value := map[string]any{
+		"name":  `quoted "name"`,
+		"count": 3,
+		"ratio": 0.5,
+		"tags":  []string{"a", "b"},
+		"nested": map[string]any{
+			"ok":    true,
+			"none":  nil,
+			"bytes": []byte{1, 2, 3},
+			"list":  []any{int64(1), "two", map[string]any{"three": 3.0}},
+		},
+	}
+
+	data, err := EncodePayload(value)
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	decoded, err := DecodePayload(data)
+	if err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	expected := map[string]any{
+		"name":  `quoted "name"`,
+		"count": int64(3),
+		"ratio": 0.5,
+		"tags":  []any{"a", "b"},
+		"nested": map[string]any{
+			"ok":    true,
+			"none":  nil,
+			"bytes": []byte{1, 2, 3},
+			"list":  []any{int64(1), "two", map[string]any{"three": 3.0}},
+		},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, decoded)
+	}
+
+	if _, err := EncodePayload(map[int]string{1: "one"}); err == nil {
+		t.Error("Expected an error for a map with non-string keys")
+	}
+	if _, err := DecodePayload(nil); err == nil {
+		t.Error("Expected an error for empty payload data")
+	}
+}
+
+func TestLog_AppendData(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, nil, ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	doc := map[string]any{"_id": "doc1", "profile": map[string]any{"age": 42, "langs": []any{"go", "js"}}}
+	data, err := EncodePayload(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode payload: %v", err)
+	}
+	entry, err := log.AppendData(data)
+	if err != nil {
+		t.Fatalf("Failed to append data: %v", err)
+	}
+	if _, err := log.Append("string payload"); err != nil {
+		t.Fatalf("Failed to append string payload: %v", err)
+	}
+
+	// The data survives storage and is covered by the signature
+	stored, err := log.Get(entry.Hash)
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if stored.Payload != "" || !VerifyEntrySignature(ks, *stored) {
+		t.Fatalf("Expected a signed data entry, got %+v", stored.Entry)
+	}
+	decoded, err := DecodePayload(stored.PayloadData)
+	if err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	profile := decoded.(map[string]any)["profile"].(map[string]any)
+	if profile["age"] != int64(42) || !reflect.DeepEqual(profile["langs"], []any{"go", "js"}) {
+		t.Errorf("Unexpected decoded document %#v", decoded)
+	}
+
+	tampered := *stored
+	tampered.PayloadData = append([]byte(nil), stored.PayloadData...)
+	tampered.PayloadData[len(tampered.PayloadData)-1] ^= 1
+	if VerifyEntrySignature(ks, tampered) {
+		t.Error("Expected tampered payload data to fail verification")
+	}
+
+	if _, err := log.AppendData(nil); err == nil {
+		t.Error("Expected empty payload data to be rejected")
+	}
+
+	// Payload data needs version 4; version 3 readers do not know the field
+	if stored.V < 4 {
+		t.Errorf("Expected a data entry of at least version 4, got %d", stored.V)
+	}
+	older := stored.Entry
+	older.V = 3
+	if err := validateVersion(older); err == nil {
+		t.Error("Expected a version 3 entry with payload data to be rejected")
+	}
+	older.PayloadData = nil
+	older.Payload = "string payload"
+	if err := validateVersion(older); err != nil {
+		t.Errorf("Expected a version 3 string entry to stay supported, got %v", err)
+	}
+}
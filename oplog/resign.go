@@ -0,0 +1,115 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// Resign repairs legacy entries that were stored without a signature. Only
+// unsigned entries whose Key matches the identity's public key are re-signed;
+// their payload, clock and links are kept.
+//
+// The signature is part of the encoded entry, so every re-signed entry gets a
+// new CID. The old block is removed, links between re-signed entries are
+// rewritten to the new hashes and the head follows its replacement. Signed
+// entries that pointed at a replaced hash are not rewritten, since that would
+// invalidate their own signatures, and will reference a missing ancestor.
+// Resign returns a map from each old hash to its new hash.
+func (l *Log) Resign(identity *identitytypes.Identity) (map[string]string, error) {
+	if identity == nil {
+		return nil, errors.New("identity is required")
+	}
+
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	ch, err := l.Entries.Iterator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate over Entries: %w", err)
+	}
+
+	var unsigned []EncodedEntry
+	for kv := range ch {
+		entry, err := Decode([]byte(kv[1]))
+		if err != nil || entry.Hash != kv[0] {
+			continue
+		}
+		if entry.Signature == "" && entry.Key == identity.PublicKey {
+			unsigned = append(unsigned, entry)
+		}
+	}
+
+	// Re-sign in clock order so parents are replaced before their children
+	sort.Slice(unsigned, func(i, j int) bool {
+		return CompareClocks(unsigned[i].Clock, unsigned[j].Clock) < 0
+	})
+
+	replaced := make(map[string]string, len(unsigned))
+	for _, old := range unsigned {
+		resigned, err := l.resignEntry(identity, old.Entry, replaced)
+		if err != nil {
+			return replaced, fmt.Errorf("failed to re-sign entry %s: %w", old.Hash, err)
+		}
+
+		if err := l.Entries.Put(resigned.Hash, resigned.Bytes); err != nil {
+			return replaced, fmt.Errorf("failed to store re-signed entry: %w", err)
+		}
+		if err := l.Entries.Delete(old.Hash); err != nil {
+			return replaced, fmt.Errorf("failed to remove unsigned entry %s: %w", old.Hash, err)
+		}
+		replaced[old.Hash] = resigned.Hash
+		l.headsKnown = false
+		l.ancestry = nil
+
+		if l.Head != nil && l.Head.Hash == old.Hash {
+			l.Head = &resigned
+		}
+	}
+
+	return replaced, nil
+}
+
+// resignEntry signs an entry with the identity's key, rewriting links to
+// entries that have already been replaced.
+func (l *Log) resignEntry(identity *identitytypes.Identity, entry Entry, replaced map[string]string) (EncodedEntry, error) {
+	relink := func(hashes []string) []string {
+		out := make([]string, len(hashes))
+		for i, hash := range hashes {
+			if newHash, ok := replaced[hash]; ok {
+				hash = newHash
+			}
+			out[i] = hash
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	unsigned := Entry{
+		ID:          entry.ID,
+		Payload:     entry.Payload,
+		PayloadData: entry.PayloadData,
+		Next:        relink(entry.Next),
+		Refs:        relink(entry.Refs),
+		Clock:       entry.Clock,
+		V:           entry.V,
+		Meta:        entry.Meta,
+	}
+
+	payload, err := SigningPayload(unsigned)
+	if err != nil {
+		return EncodedEntry{}, err
+	}
+
+	signature, err := l.keystore.SignMessage(identity.ID, payload)
+	if err != nil {
+		return EncodedEntry{}, err
+	}
+
+	unsigned.Key = identity.PublicKey
+	unsigned.Identity = identity.Hash
+	unsigned.Signature = signature
+	return Encode(unsigned)
+}
@@ -0,0 +1,72 @@
+package oplog
+
+import (
+	"testing"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+func TestLog_Resign(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	// Store a legacy entry carrying the identity's key but no signature
+	legacy := mustEncode(t, Entry{
+		ID:       "test-log",
+		Payload:  "legacy payload",
+		Next:     []string{},
+		Refs:     []string{},
+		Clock:    NewClock(identity.ID, 1),
+		V:        EntryVersion,
+		Key:      identity.PublicKey,
+		Identity: identity.Hash,
+	})
+	if err := log.Entries.Put(legacy.Hash, legacy.Bytes); err != nil {
+		t.Fatalf("Failed to store legacy entry: %v", err)
+	}
+
+	// An unsigned entry with a foreign key must be left alone
+	foreign := mustEncode(t, Entry{
+		ID:      "test-log",
+		Payload: "foreign payload",
+		Next:    []string{},
+		Refs:    []string{},
+		Clock:   NewClock("other", 1),
+		V:       EntryVersion,
+		Key:     "04deadbeef",
+	})
+	if err := log.Entries.Put(foreign.Hash, foreign.Bytes); err != nil {
+		t.Fatalf("Failed to store foreign entry: %v", err)
+	}
+
+	if _, err := log.Get(legacy.Hash); err == nil {
+		t.Fatal("Expected unsigned entry to fail verification before re-signing")
+	}
+
+	replaced, err := log.Resign(identity)
+	if err != nil {
+		t.Fatalf("Failed to re-sign entries: %v", err)
+	}
+	if len(replaced) != 1 {
+		t.Fatalf("Expected 1 re-signed entry, got %d", len(replaced))
+	}
+
+	newHash := replaced[legacy.Hash]
+	resigned, err := log.Get(newHash)
+	if err != nil {
+		t.Fatalf("Expected re-signed entry to verify, got %v", err)
+	}
+	if resigned.Payload != legacy.Payload || resigned.Clock != legacy.Clock {
+		t.Error("Expected payload and clock to be preserved")
+	}
+	if _, err := log.Entries.Get(legacy.Hash); err == nil {
+		t.Error("Expected the unsigned block to be removed")
+	}
+	if _, err := log.Entries.Get(foreign.Hash); err != nil {
+		t.Error("Expected the foreign entry to be left in place")
+	}
+}
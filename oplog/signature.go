@@ -0,0 +1,163 @@
+package oplog
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+)
+
+// SignatureScheme verifies entry signatures made by one identity type, so a
+// single log can hold entries from writers using different key types.
+type SignatureScheme struct {
+	// KeySize is the length in bytes of a decoded public key.
+	KeySize int
+	// Verify checks a hex signature over data against a hex public key.
+	Verify func(publicKey string, data []byte, signature string) (bool, error)
+}
+
+// signatureSchemes stores the schemes entries are verified with, by identity
+// type, and signatureAliases the identity types that name the same algorithm
+// as another.
+var (
+	signatureSchemes   = make(map[string]SignatureScheme)
+	signatureAliases   = make(map[string]string)
+	signatureSchemesMu sync.RWMutex
+)
+
+// RegisterSignatureScheme registers the scheme used to verify entries written
+// by identities of the given type.
+func RegisterSignatureScheme(identityType string, scheme SignatureScheme) {
+	signatureSchemesMu.Lock()
+	defer signatureSchemesMu.Unlock()
+	signatureSchemes[identityType] = scheme
+}
+
+// RegisterSignatureAlias records that identities of type alias sign with the
+// algorithm of identityType, so entries they write may name either in
+// SigAlgo.
+func RegisterSignatureAlias(alias, identityType string) {
+	signatureSchemesMu.Lock()
+	defer signatureSchemesMu.Unlock()
+	signatureAliases[alias] = identityType
+}
+
+// sameSignatureAlgo reports whether two identity types name the same
+// signature algorithm, directly or through a registered alias.
+func sameSignatureAlgo(a, b string) bool {
+	signatureSchemesMu.RLock()
+	defer signatureSchemesMu.RUnlock()
+
+	if canonical, ok := signatureAliases[a]; ok {
+		a = canonical
+	}
+	if canonical, ok := signatureAliases[b]; ok {
+		b = canonical
+	}
+	return a == b
+}
+
+// SignatureSchemes returns the identity types with a registered scheme, sorted.
+func SignatureSchemes() []string {
+	signatureSchemesMu.RLock()
+	defer signatureSchemesMu.RUnlock()
+
+	types := make([]string, 0, len(signatureSchemes))
+	for identityType := range signatureSchemes {
+		types = append(types, identityType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// didIdentityType is the type of DIDProvider identities, which sign with the
+// key of whichever provider the DID wraps.
+const didIdentityType = "DID"
+
+// signatureType returns the identity type whose scheme verifies entries
+// written by identity, or "" when identity is nil. A DID identity uses the
+// scheme of the key type its DID encodes; a DID that does not parse names no
+// scheme, so its entries fail verification.
+func signatureType(identity *identitytypes.Identity) string {
+	if identity == nil {
+		return ""
+	}
+	if identity.Type != didIdentityType {
+		return identity.Type
+	}
+	keyType, _, err := providers.ParseDIDKey(identity.ID)
+	if err != nil {
+		return identity.Type
+	}
+	return keyType
+}
+
+// verifySignature checks a signature with the scheme of identityType. When
+// the type is unknown to the caller it is empty, and every scheme whose key
+// size matches the key is tried.
+func verifySignature(identityType, key string, data []byte, signature string) bool {
+	keyBytes, err := hex.DecodeString(key)
+	if err != nil {
+		return false
+	}
+
+	signatureSchemesMu.RLock()
+	var candidates []SignatureScheme
+	if identityType != "" {
+		if scheme, ok := signatureSchemes[identityType]; ok {
+			candidates = append(candidates, scheme)
+		}
+	} else {
+		for _, scheme := range signatureSchemes {
+			candidates = append(candidates, scheme)
+		}
+	}
+	signatureSchemesMu.RUnlock()
+
+	for _, scheme := range candidates {
+		if scheme.KeySize != len(keyBytes) {
+			continue
+		}
+		if ok, err := scheme.Verify(key, data, signature); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyECDSA verifies signatures made with the keystore's P-256 keys.
+func verifyECDSA(publicKey string, data []byte, signature string) (bool, error) {
+	pubKey, err := keystore.ReconstructPublicKeyFromHex(publicKey)
+	if err != nil {
+		return false, err
+	}
+	if err := checkOnCurve(pubKey); err != nil {
+		return false, fmt.Errorf("malformed key: %w", err)
+	}
+	return keystore.Verify(*pubKey, data, signature)
+}
+
+// verifyEd25519 verifies signatures made by ed25519 identities.
+func verifyEd25519(publicKey string, data []byte, signature string) (bool, error) {
+	pubKey, err := hex.DecodeString(publicKey)
+	if err != nil || len(pubKey) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid ed25519 public key")
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false, fmt.Errorf("invalid ed25519 signature")
+	}
+	return ed25519.Verify(pubKey, data, sig), nil
+}
+
+// init registers the schemes of the built-in identity providers.
+func init() {
+	RegisterSignatureScheme("publickey", SignatureScheme{KeySize: 64, Verify: verifyECDSA})
+	RegisterSignatureScheme("ed25519", SignatureScheme{KeySize: ed25519.PublicKeySize, Verify: verifyEd25519})
+	RegisterSignatureScheme("ethereum", SignatureScheme{KeySize: 64, Verify: providers.VerifyEthereumSignature})
+}
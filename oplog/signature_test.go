@@ -0,0 +1,253 @@
+package oplog
+
+import (
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// newEd25519Entry signs an entry with an ed25519 identity.
+func newEd25519Entry(t *testing.T, logID, payload string, clock Clock) (EncodedEntry, *identitytypes.Identity) {
+	t.Helper()
+
+	provider := providers.NewEd25519Provider(storage.NewMemoryStorage())
+	identity, err := provider.CreateIdentity("ed25519-writer")
+	if err != nil {
+		t.Fatalf("Failed to create ed25519 identity: %v", err)
+	}
+
+	unsigned := Entry{ID: logID, Payload: payload, Next: []string{}, Refs: []string{}, Clock: clock, V: 2}
+	signature, err := provider.Sign(identity.ID, mustEncode(t, unsigned).Bytes)
+	if err != nil {
+		t.Fatalf("Failed to sign entry: %v", err)
+	}
+	signed := unsigned
+	signed.Key = identity.PublicKey
+	signed.Identity = identity.Hash
+	signed.Signature = signature
+	return mustEncode(t, signed), identity
+}
+
+func TestLog_MixedSignatureSchemes(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	ecdsaEntry := mustNewEntry(t, ks, identity, "test-log", "publickey payload", NewClock(identity.ID, 1), nil, nil)
+	ed25519Entry, ed25519Identity := newEd25519Entry(t, "test-log", "ed25519 payload", NewClock("ed25519-writer", 1))
+
+	for _, entry := range []EncodedEntry{ecdsaEntry, ed25519Entry} {
+		if !VerifyEntrySignature(ks, entry) {
+			t.Errorf("Expected entry %q to verify", entry.Payload)
+		}
+		if err := log.JoinEntry(&entry, make(map[string]bool)); err != nil {
+			t.Fatalf("Failed to join entry %q: %v", entry.Payload, err)
+		}
+	}
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(values))
+	}
+
+	// With the writer identity known, only its own scheme is used
+	if err := VerifyEntryFull(ks, ecdsaEntry, identity); err != nil {
+		t.Errorf("Expected publickey entry to verify, got %v", err)
+	}
+	if err := VerifyEntryFull(ks, ed25519Entry, ed25519Identity); err != nil {
+		t.Errorf("Expected ed25519 entry to verify, got %v", err)
+	}
+	mislabeled := *ed25519Identity
+	mislabeled.Type = "publickey"
+	if err := VerifyEntryFull(ks, ed25519Entry, &mislabeled); err == nil {
+		t.Error("Expected ed25519 entry to fail under the publickey scheme")
+	}
+
+	// A tampered payload fails under either scheme
+	tampered := ed25519Entry
+	tampered.Payload = "tampered"
+	if VerifyEntrySignature(ks, tampered) {
+		t.Error("Expected tampered ed25519 entry to fail verification")
+	}
+}
+
+func TestLog_SigAlgoSelectsScheme(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	ecdsaEntry, err := log.Append("publickey payload")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if ecdsaEntry.SigAlgo != "" {
+		t.Errorf("Expected keystore-signed entries to use the default algorithm, got %q", ecdsaEntry.SigAlgo)
+	}
+
+	provider := providers.NewEd25519Provider(storage.NewMemoryStorage())
+	writer, err := provider.CreateIdentity("ed25519-writer")
+	if err != nil {
+		t.Fatalf("Failed to create ed25519 identity: %v", err)
+	}
+	unsigned := Entry{ID: "test-log", Payload: "ed25519 payload", Next: []string{}, Refs: []string{}, Clock: NewClock(writer.ID, 1), V: EntryVersion, SigAlgo: "ed25519"}
+	payload, err := SigningPayload(unsigned)
+	if err != nil {
+		t.Fatalf("Failed to encode signing payload: %v", err)
+	}
+	signed := unsigned
+	signed.Key = writer.PublicKey
+	signed.Identity = writer.Hash
+	if signed.Signature, err = provider.Sign(writer.ID, payload); err != nil {
+		t.Fatalf("Failed to sign entry: %v", err)
+	}
+	ed25519Entry := mustEncode(t, signed)
+
+	decoded, err := Decode(ed25519Entry.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if decoded.SigAlgo != "ed25519" {
+		t.Errorf("Expected SigAlgo ed25519 after decoding, got %q", decoded.SigAlgo)
+	}
+	if err := log.JoinEntry(&decoded, make(map[string]bool)); err != nil {
+		t.Fatalf("Failed to join ed25519 entry: %v", err)
+	}
+
+	values, err := log.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(values))
+	}
+	for _, entry := range values {
+		if !VerifyEntrySignature(ks, entry) {
+			t.Errorf("Expected entry %q to verify", entry.Payload)
+		}
+	}
+
+	// The entry's SigAlgo matches the writer's identity type
+	if err := VerifyEntryFull(ks, ed25519Entry, writer); err != nil {
+		t.Errorf("Expected ed25519 entry to verify, got %v", err)
+	}
+	// An alias with no scheme of its own verifies through SigAlgo
+	RegisterSignatureAlias("ed25519-alias", "ed25519")
+	if !verifyEntrySignature("ed25519-alias", ed25519Entry) {
+		t.Error("Expected SigAlgo ed25519 to be honoured for a registered alias")
+	}
+	if verifyEntrySignature("publickey", ed25519Entry) {
+		t.Error("Expected a SigAlgo not matching the identity type to be ignored")
+	}
+
+	// The algorithm is signed, so relabeling the entry breaks the signature
+	relabeled := signed
+	relabeled.SigAlgo = "publickey"
+	if VerifyEntrySignature(ks, mustEncode(t, relabeled)) {
+		t.Error("Expected a relabeled entry to fail verification")
+	}
+
+	// SigAlgo needs version 5; older readers do not know the field
+	legacy := signed
+	legacy.V = 4
+	if err := validateVersion(legacy); err == nil {
+		t.Error("Expected a version 4 entry with a SigAlgo to be rejected")
+	}
+}
+
+// signWith signs an entry for writer with provider.
+func signWith(t *testing.T, provider providers.KeyProvider, writer *identitytypes.Identity, sigAlgo string) EncodedEntry {
+	t.Helper()
+
+	unsigned := Entry{ID: "test-log", Payload: "did payload", Next: []string{}, Refs: []string{}, Clock: NewClock(writer.ID, 1), V: EntryVersion, SigAlgo: sigAlgo}
+	payload, err := SigningPayload(unsigned)
+	if err != nil {
+		t.Fatalf("Failed to encode signing payload: %v", err)
+	}
+	signed := unsigned
+	signed.Key = writer.PublicKey
+	signed.Identity = writer.Hash
+	if signed.Signature, err = provider.Sign(writer.ID, payload); err != nil {
+		t.Fatalf("Failed to sign entry: %v", err)
+	}
+	return mustEncode(t, signed)
+}
+
+func TestDIDSignatureSchemes(t *testing.T) {
+	ks, _ := setupTestKeyStoreAndIdentity(t)
+
+	for name, keys := range map[string]providers.KeyProvider{
+		"publickey": providers.NewPublicKeyProvider(ks),
+		"ed25519":   providers.NewEd25519Provider(storage.NewMemoryStorage()),
+	} {
+		provider := providers.NewDIDProvider(keys)
+		writer, err := provider.CreateIdentity("did-writer-" + name)
+		if err != nil {
+			t.Fatalf("Failed to create %s DID identity: %v", name, err)
+		}
+		if got := signatureType(writer); got != name {
+			t.Errorf("Expected a %s DID to use the %s scheme, got %q", name, name, got)
+		}
+
+		// The DID's key type picks the scheme, whatever the entry names
+		for _, sigAlgo := range []string{"", "DID", name} {
+			entry := signWith(t, provider, writer, sigAlgo)
+			if err := VerifyEntryFull(ks, entry, writer); err != nil {
+				t.Errorf("Expected %s DID entry with SigAlgo %q to verify, got %v", name, sigAlgo, err)
+			}
+			if !VerifyEntrySignature(ks, entry) {
+				t.Errorf("Expected %s DID entry with SigAlgo %q to verify without its identity", name, sigAlgo)
+			}
+		}
+
+		// A DID that does not parse names no scheme
+		broken := *writer
+		broken.ID = "did:key:invalid"
+		if err := VerifyEntryFull(ks, signWith(t, provider, writer, ""), &broken); err == nil {
+			t.Errorf("Expected a %s entry under an unparsable DID to fail", name)
+		}
+	}
+}
+
+func TestRegisterSignatureScheme(t *testing.T) {
+	for _, identityType := range []string{"ed25519", "ethereum", "publickey"} {
+		found := false
+		for _, registered := range SignatureSchemes() {
+			found = found || registered == identityType
+		}
+		if !found {
+			t.Errorf("Expected built-in scheme %q to be registered", identityType)
+		}
+	}
+
+	RegisterSignatureScheme("always", SignatureScheme{
+		KeySize: 4,
+		Verify: func(publicKey string, data []byte, signature string) (bool, error) {
+			return signature == "ok", nil
+		},
+	})
+	defer func() {
+		signatureSchemesMu.Lock()
+		delete(signatureSchemes, "always")
+		signatureSchemesMu.Unlock()
+	}()
+
+	if !verifySignature("always", "00112233", nil, "ok") {
+		t.Error("Expected custom scheme to verify")
+	}
+	if verifySignature("always", "0011", nil, "ok") {
+		t.Error("Expected key of the wrong size to be rejected")
+	}
+	if verifySignature("unknown", "00112233", nil, "ok") {
+		t.Error("Expected unknown identity type to be rejected")
+	}
+}
@@ -0,0 +1,109 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxEntryReferences is the maximum number of Next or Refs hashes an entry may
+// carry. Decode and ValidateEntry reject entries exceeding it. Decode checks
+// the lengths of the decoded lists; dagcbor's allocation budget refuses a
+// list header declaring more items than the input can hold before that.
+const MaxEntryReferences = 1024
+
+// ErrSelfReference is returned for an entry whose Next or Refs contain its
+// own hash, which would create a cycle in the log.
+var ErrSelfReference = errors.New("entry references itself")
+
+// ValidateEntry checks structural constraints on a decoded entry that the
+// CBOR schema alone does not enforce.
+func ValidateEntry(entry Entry) error {
+	if entry.Signature != "" && entry.Clock.ID == "" {
+		return errors.New("signed entry has an empty clock id")
+	}
+	if entry.Clock.Time < 0 || entry.Clock.Time > MaxClockTime {
+		return fmt.Errorf("clock time %d out of range [0, %d]", entry.Clock.Time, MaxClockTime)
+	}
+	if len(entry.Next) > MaxEntryReferences {
+		return fmt.Errorf("next has %d references, exceeding the limit of %d", len(entry.Next), MaxEntryReferences)
+	}
+	if len(entry.Refs) > MaxEntryReferences {
+		return fmt.Errorf("refs has %d references, exceeding the limit of %d", len(entry.Refs), MaxEntryReferences)
+	}
+	return validateMeta(entry.Meta)
+}
+
+// validateMeta rejects metadata larger than MaxEntryMetaSize.
+func validateMeta(meta map[string]string) error {
+	size := 0
+	for k, v := range meta {
+		size += len(k) + len(v)
+	}
+	if size > MaxEntryMetaSize {
+		return fmt.Errorf("meta is %d bytes, exceeding the limit of %d", size, MaxEntryMetaSize)
+	}
+	return nil
+}
+
+// ErrUnsupportedVersion is returned for an entry whose schema version this
+// implementation cannot read.
+var ErrUnsupportedVersion = errors.New("unsupported entry version")
+
+// supportedEntryVersions lists the entry schema versions accepted into a log.
+var supportedEntryVersions = map[int]bool{
+	2:            true,
+	3:            true,
+	4:            true,
+	EntryVersion: true,
+}
+
+// validateVersion rejects an entry with a schema version missing from
+// supportedEntryVersions, or with fields its version does not define.
+func validateVersion(entry Entry) error {
+	if !supportedEntryVersions[entry.V] {
+		return fmt.Errorf("%w %d", ErrUnsupportedVersion, entry.V)
+	}
+	if entry.V < 3 && len(entry.Meta) > 0 {
+		return fmt.Errorf("version %d entry cannot carry meta", entry.V)
+	}
+	if entry.V < 4 && len(entry.PayloadData) > 0 {
+		return fmt.Errorf("version %d entry cannot carry payload data", entry.V)
+	}
+	if entry.V < 5 && entry.SigAlgo != "" {
+		return fmt.Errorf("version %d entry cannot carry a signature algorithm", entry.V)
+	}
+	return nil
+}
+
+// validateReferences rejects an entry whose Next or Refs contain its own
+// hash. Such an entry cannot be produced by Append, since the hash is only
+// known after Next and Refs are set, but a crafted one would loop traversal.
+func validateReferences(entry EncodedEntry) error {
+	for _, hash := range entry.Next {
+		if hash == entry.Hash {
+			return fmt.Errorf("%w in next", ErrSelfReference)
+		}
+	}
+	for _, hash := range entry.Refs {
+		if hash == entry.Hash {
+			return fmt.Errorf("%w in refs", ErrSelfReference)
+		}
+	}
+	return nil
+}
+
+// validate rejects entries of unsupported versions and self-referential
+// entries, and runs ValidateEntry when the
+// log is in strict mode.
+func (l *Log) validate(entry EncodedEntry) error {
+	if err := validateVersion(entry.Entry); err != nil {
+		return err
+	}
+	if err := validateReferences(entry); err != nil {
+		return err
+	}
+	if !l.strict {
+		return nil
+	}
+	return ValidateEntry(entry.Entry)
+}
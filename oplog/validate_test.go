@@ -0,0 +1,221 @@
+package oplog
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/ipld/go-ipld-prime/codec/dagcbor"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+func TestValidateEntry_ClockRange(t *testing.T) {
+	valid := Entry{ID: "entry-ID", Payload: "payload", Clock: Clock{ID: "a", Time: MaxClockTime}}
+	if err := ValidateEntry(valid); err != nil {
+		t.Errorf("Expected clock at MaxClockTime to be valid, got %v", err)
+	}
+
+	for _, time := range []int{MaxClockTime + 1, -1} {
+		invalid := Entry{ID: "entry-ID", Payload: "payload", Clock: Clock{ID: "a", Time: time}}
+		if err := ValidateEntry(invalid); err == nil {
+			t.Errorf("Expected clock time %d to be rejected", time)
+		}
+	}
+}
+
+func TestValidateEntry_ReferenceLimit(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	next := make([]string, MaxEntryReferences+1)
+	for i := range next {
+		next[i] = "hash" + strconv.Itoa(i)
+	}
+	entry := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), next, nil)
+
+	if err := ValidateEntry(entry.Entry); err == nil {
+		t.Error("Expected ValidateEntry to reject an entry exceeding the reference limit")
+	}
+	if _, err := Decode(entry.Bytes); err == nil {
+		t.Error("Expected Decode to reject an entry exceeding the reference limit")
+	}
+
+	within := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), next[:MaxEntryReferences], next[:MaxEntryReferences])
+	if err := ValidateEntry(within.Entry); err != nil {
+		t.Errorf("Expected entry at the reference limit to be valid, got %v", err)
+	}
+	if _, err := Decode(within.Bytes); err != nil {
+		t.Errorf("Expected entry at the reference limit to decode, got %v", err)
+	}
+}
+
+func TestValidateEntry_ClockID(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	populated := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), nil, nil)
+	if err := ValidateEntry(populated.Entry); err != nil {
+		t.Errorf("Expected signed entry with a clock id to be valid, got %v", err)
+	}
+
+	// Sign an entry whose clock carries no id
+	unsigned := Entry{ID: "test-log", Payload: "payload", Next: []string{}, Refs: []string{}, Clock: Clock{Time: 1}, V: 2}
+	signature, err := ks.SignMessage(identity.ID, mustEncode(t, unsigned).Bytes)
+	if err != nil {
+		t.Fatalf("Failed to sign entry: %v", err)
+	}
+	signed := unsigned
+	signed.Key = identity.PublicKey
+	signed.Identity = identity.Hash
+	signed.Signature = signature
+	empty := mustEncode(t, signed)
+
+	if err := ValidateEntry(empty.Entry); err == nil {
+		t.Error("Expected signed entry with an empty clock id to be rejected")
+	}
+
+	strict, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if err := strict.JoinEntry(&empty, make(map[string]bool)); err == nil {
+		t.Error("Expected strict log to reject entry with an empty clock id")
+	}
+	if err := strict.JoinEntry(&populated, make(map[string]bool)); err != nil {
+		t.Errorf("Expected strict log to accept entry with a clock id, got %v", err)
+	}
+}
+
+func TestLog_AppendClockOverflow(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	log.Clock = NewClock(identity.ID, MaxClockTime-1)
+
+	entry, err := log.Append("last entry")
+	if err != nil {
+		t.Fatalf("Expected append up to MaxClockTime to succeed: %v", err)
+	}
+	if entry.Clock.Time != MaxClockTime {
+		t.Errorf("Expected clock time %d, got %d", MaxClockTime, entry.Clock.Time)
+	}
+
+	if _, err := log.Append("overflowing entry"); !errors.Is(err, ErrClockOverflow) {
+		t.Errorf("Expected ErrClockOverflow, got %v", err)
+	}
+	if log.Head.Hash != entry.Hash {
+		t.Error("Expected head to be unchanged after a rejected append")
+	}
+}
+
+func TestLog_StrictRejectsOutOfRangeClock(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	entry := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, MaxClockTime+1), nil, nil)
+
+	lenient, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if err := lenient.JoinEntry(&entry, make(map[string]bool)); err != nil {
+		t.Errorf("Expected non-strict log to accept entry, got %v", err)
+	}
+
+	strict, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks, WithStrictValidation())
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if err := strict.JoinEntry(&entry, make(map[string]bool)); err == nil {
+		t.Error("Expected strict log to reject entry with out-of-range clock")
+	}
+
+	// Entries already in storage are rejected on read as well
+	if err := strict.Entries.Put(entry.Hash, entry.Bytes); err != nil {
+		t.Fatalf("Failed to store entry: %v", err)
+	}
+	if _, err := strict.Get(entry.Hash); err == nil {
+		t.Error("Expected strict Get to reject entry with out-of-range clock")
+	}
+}
+
+func TestLog_RejectsSelfReference(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	next := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), nil, nil)
+	next.Entry.Next = []string{"other", next.Hash}
+	if err := log.JoinEntry(&next, make(map[string]bool)); !errors.Is(err, ErrSelfReference) {
+		t.Errorf("Expected ErrSelfReference for a self-referential next, got %v", err)
+	}
+
+	refs := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), nil, nil)
+	refs.Entry.Refs = []string{refs.Hash}
+	if err := log.JoinEntry(&refs, make(map[string]bool)); !errors.Is(err, ErrSelfReference) {
+		t.Errorf("Expected ErrSelfReference for a self-referential refs, got %v", err)
+	}
+
+	if log.Head != nil {
+		t.Error("Expected head to be unchanged after rejected entries")
+	}
+}
+
+func TestDecode_ReferenceLimitFromHeaders(t *testing.T) {
+	// A next list declaring 2^32 items with none present is refused by
+	// the decoder's allocation budget before anything is allocated
+	huge := append([]byte{0xa1, 0x64}, "next"...)
+	huge = append(huge, 0x9b, 0, 0, 0, 1, 0, 0, 0, 0)
+	if _, err := Decode(huge); !errors.Is(err, dagcbor.ErrAllocationBudgetExceeded) {
+		t.Errorf("Expected the oversized next header to be rejected, got %v", err)
+	}
+}
+
+func TestLog_EntryVersion(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	log, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+
+	entry, err := log.Append("payload")
+	if err != nil {
+		t.Fatalf("Failed to append entry: %v", err)
+	}
+	if entry.V != EntryVersion {
+		t.Fatalf("Expected entry version %d, got %d", EntryVersion, entry.V)
+	}
+
+	// The version survives encoding and is accepted by the log
+	decoded, err := Decode(entry.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to decode entry: %v", err)
+	}
+	if decoded.V != EntryVersion {
+		t.Errorf("Expected decoded version %d, got %d", EntryVersion, decoded.V)
+	}
+	if err := validateVersion(decoded.Entry); err != nil {
+		t.Errorf("Expected current version to be supported, got %v", err)
+	}
+
+	// Entries of other versions are rejected on join
+	for _, version := range []int{1, EntryVersion + 1} {
+		unsigned := Entry{ID: "test-log", Payload: "payload", Next: []string{}, Refs: []string{}, Clock: NewClock(identity.ID, 2), V: version}
+		encoded := mustEncode(t, unsigned)
+		signature, err := ks.SignMessage(identity.ID, encoded.Bytes)
+		if err != nil {
+			t.Fatalf("Failed to sign entry: %v", err)
+		}
+		unsigned.Key, unsigned.Identity, unsigned.Signature = identity.PublicKey, identity.Hash, signature
+		other := mustEncode(t, unsigned)
+
+		if err := log.JoinEntry(&other, make(map[string]bool)); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("Expected ErrUnsupportedVersion for version %d, got %v", version, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package oplog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// ErrForgedLink is returned by JoinVerified when an entry is stored under a
+// CID that does not match its content, or links an entry that is neither in
+// the joined log nor in this one.
+var ErrForgedLink = errors.New("forged entry link")
+
+// ErrCIDMismatch is returned by DecodeAndVerify when the bytes do not hash
+// to the expected CID.
+var ErrCIDMismatch = errors.New("entry CID mismatch")
+
+// ErrInvalidSignature is returned by DecodeAndVerify when the entry's
+// signature does not verify.
+var ErrInvalidSignature = errors.New("invalid entry signature")
+
+// DecodeAndVerify decodes entry bytes loaded from untrusted storage under
+// hash, and checks that they hash to it and carry a valid signature. With
+// an identity, the entry must also belong to it as for VerifyEntryFull.
+// Tampered bytes fail with ErrCIDMismatch, and entries re-stored under their
+// tampered CID with ErrInvalidSignature.
+func DecodeAndVerify(hash string, data []byte, identity *identitytypes.Identity) (EncodedEntry, error) {
+	expected, err := cid.Decode(hash)
+	if err != nil {
+		return EncodedEntry{}, fmt.Errorf("invalid entry CID %q: %w", hash, err)
+	}
+
+	entry, err := Decode(data)
+	if err != nil {
+		return EncodedEntry{}, fmt.Errorf("failed to decode entry %s: %w", hash, err)
+	}
+	if !entry.CID.Equals(expected) {
+		return EncodedEntry{}, fmt.Errorf("%w: expected %s, got %s", ErrCIDMismatch, hash, entry.Hash)
+	}
+
+	identityType := signatureType(identity)
+	if !verifyEntrySignature(identityType, entry) {
+		return EncodedEntry{}, fmt.Errorf("%w: entry %s", ErrInvalidSignature, entry.Hash)
+	}
+	if identity != nil {
+		if err := verifyEntryIdentity(entry, identity); err != nil {
+			return EncodedEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// JoinVerified joins otherLog like Join, but for logs from untrusted peers:
+// the whole batch is checked before anything is stored, and rejected if any
+// entry fails. Every entry must be stored under the CID recomputed from its
+// bytes, carry a valid signature, and have each Next and Refs link resolve
+// to an entry of the batch or of this log. A partial log, such as one whose
+// ancestors were pruned, is therefore rejected.
+func (l *Log) JoinVerified(otherLog *Log) error {
+	if l.metrics != nil {
+		defer l.observe(OpJoin, time.Now())
+	}
+
+	if otherLog.ID != l.ID {
+		return fmt.Errorf("log ID '%s' does not match other log ID '%s'", l.ID, otherLog.ID)
+	}
+
+	otherLog.Mu.RLock()
+	ch, err := otherLog.Entries.Iterator()
+	if err != nil {
+		otherLog.Mu.RUnlock()
+		return fmt.Errorf("failed to retrieve Entries from other log: %w", err)
+	}
+	var entries []EncodedEntry
+	var errs []error
+	for kv := range ch {
+		entry, err := Decode([]byte(kv[1]))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", kv[0], err))
+			continue
+		}
+		// Decode hashes the bytes, so the key must be the content's CID
+		if entry.Hash != storageKey(kv[0]) {
+			errs = append(errs, fmt.Errorf("%w: entry stored under %s has CID %s", ErrForgedLink, kv[0], entry.Hash))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	otherLog.Mu.RUnlock()
+
+	l.Mu.Lock()
+	defer l.Mu.Unlock()
+
+	batch := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		batch[entry.Hash] = true
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if err := l.verifyIncoming(entry); err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", entry.Hash, err))
+			continue
+		}
+		for _, link := range append(append([]string(nil), entry.Next...), entry.Refs...) {
+			if batch[link] {
+				continue
+			}
+			if has, err := l.Entries.Has(link); err != nil || !has {
+				errs = append(errs, fmt.Errorf("%w: entry %s links %s, which was not provided", ErrForgedLink, entry.Hash, link))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("rejected join of log %s: %w", otherLog.ID, errors.Join(errs...))
+	}
+
+	before := l.entrySet()
+	defer l.checkJoinInvariant(OpJoin, before)
+
+	processed := make(map[string]bool)
+	for i := range entries {
+		if err := l.storeJoined(&entries[i], processed); err != nil {
+			return fmt.Errorf("entry %s: %w", entries[i].Hash, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package oplog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"orbitdb/go-orbitdb/storage"
+)
+
+func TestLog_JoinVerified(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		if _, err := source.Append(payload); err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+
+	target, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create target log: %v", err)
+	}
+	if err := target.JoinVerified(source); err != nil {
+		t.Fatalf("Expected an intact log to join, got %v", err)
+	}
+	values, err := target.Values()
+	if err != nil || len(values) != 3 {
+		t.Fatalf("Expected 3 joined entries, got %d (%v)", len(values), err)
+	}
+}
+
+func TestLog_JoinVerifiedRejectsTamperedAncestor(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+
+	source, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create source log: %v", err)
+	}
+	var entries []*EncodedEntry
+	for _, payload := range []string{"entry1", "entry2", "entry3"} {
+		entry, err := source.Append(payload)
+		if err != nil {
+			t.Fatalf("Failed to append entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	// Rewrite the ancestor's payload in place, keeping it under its old CID
+	ancestor := entries[1]
+	tampered := bytes.Replace(ancestor.Bytes, []byte("entry2"), []byte("forged"), 1)
+	if bytes.Equal(tampered, ancestor.Bytes) {
+		t.Fatal("Failed to tamper with the ancestor")
+	}
+	if err := source.Entries.Put(ancestor.Hash, tampered); err != nil {
+		t.Fatalf("Failed to store tampered entry: %v", err)
+	}
+
+	target, err := NewLog("test-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		t.Fatalf("Failed to create target log: %v", err)
+	}
+	err = target.JoinVerified(source)
+	if !errors.Is(err, ErrForgedLink) {
+		t.Fatalf("Expected ErrForgedLink, got %v", err)
+	}
+
+	// The whole batch is rejected, including the untouched entries
+	values, err := target.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if len(values) != 0 || target.Head != nil {
+		t.Errorf("Expected nothing to be joined, got %d entries", len(values))
+	}
+}
+
+func TestDecodeAndVerify(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	entry := mustNewEntry(t, ks, identity, "test-log", "payload-data", NewClock(identity.ID, 1), nil, nil)
+
+	decoded, err := DecodeAndVerify(entry.Hash, entry.Bytes, identity)
+	if err != nil {
+		t.Fatalf("Expected a good entry to verify, got %v", err)
+	}
+	if !IsEqual(decoded, entry) {
+		t.Error("Expected the decoded entry to equal the original")
+	}
+	if _, err := DecodeAndVerify(entry.Hash, entry.Bytes, nil); err != nil {
+		t.Errorf("Expected a good entry to verify without an identity, got %v", err)
+	}
+
+	// Flip one byte of the payload
+	tampered := bytes.Replace(entry.Bytes, []byte("payload-data"), []byte("payload-dbta"), 1)
+	if bytes.Equal(tampered, entry.Bytes) {
+		t.Fatal("Expected the payload to be found in the encoded bytes")
+	}
+	if _, err := DecodeAndVerify(entry.Hash, tampered, identity); !errors.Is(err, ErrCIDMismatch) {
+		t.Errorf("Expected ErrCIDMismatch for tampered bytes, got %v", err)
+	}
+
+	// Stored under its own CID, the tampered entry still fails its signature
+	forged, err := Decode(tampered)
+	if err != nil {
+		t.Fatalf("Failed to decode tampered entry: %v", err)
+	}
+	if _, err := DecodeAndVerify(forged.Hash, tampered, identity); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a re-hashed tampered entry, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package oplog
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+)
+
+// Verifier verifies entry signatures, remembering entries that verified so
+// replaying a log does not repeat the signature check for each of them.
+//
+// Cached results are keyed by the entry's CID and signature, so the CID must
+// identify the entry's contents: pass entries obtained from Decode or from
+// the log rather than assembled by hand.
+type Verifier struct {
+	cache *lru.Cache
+}
+
+// NewVerifier creates a Verifier caching up to cacheSize verified entries.
+// A cacheSize of zero or less disables caching.
+func NewVerifier(cacheSize int) *Verifier {
+	if cacheSize <= 0 {
+		return &Verifier{}
+	}
+	cache, _ := lru.New(cacheSize)
+	return &Verifier{cache: cache}
+}
+
+// Verify reports whether the entry signature is valid. With an identity the
+// entry is checked as by VerifyEntryFull, otherwise as by VerifyEntrySignature.
+// Only the signature check is cached; the identity checks run on every call.
+func (v *Verifier) Verify(identity *identitytypes.Identity, entry EncodedEntry) bool {
+	identityType := signatureType(identity)
+
+	key := identityType + "/" + entry.Hash + "/" + entry.Signature
+	verified := false
+	if v.cache != nil {
+		_, verified = v.cache.Get(key)
+	}
+	if !verified {
+		if !verifyEntrySignature(identityType, entry) {
+			return false
+		}
+		if v.cache != nil {
+			v.cache.Add(key, struct{}{})
+		}
+	}
+
+	if identity == nil {
+		return true
+	}
+	return verifyEntryIdentity(entry, identity) == nil
+}
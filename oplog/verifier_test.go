@@ -0,0 +1,79 @@
+package oplog
+
+import (
+	"strconv"
+	"testing"
+
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/storage"
+)
+
+func TestVerifier(t *testing.T) {
+	ks, identity := setupTestKeyStoreAndIdentity(t)
+	entry := mustNewEntry(t, ks, identity, "test-log", "payload", NewClock(identity.ID, 1), nil, nil)
+
+	for _, verifier := range []*Verifier{NewVerifier(0), NewVerifier(10)} {
+		// Repeated checks give the same answer with or without the cache
+		for i := 0; i < 2; i++ {
+			if !verifier.Verify(nil, entry) {
+				t.Error("Expected entry to verify without an identity")
+			}
+			if !verifier.Verify(identity, entry) {
+				t.Error("Expected entry to verify against its identity")
+			}
+		}
+
+		// Identity checks are not skipped on a cache hit
+		_, other := setupTestKeyStoreAndIdentity(t)
+		if verifier.Verify(other, entry) {
+			t.Error("Expected entry to fail against another identity")
+		}
+
+		// A different signature is not a cache hit
+		forged := entry
+		forged.Signature = entry.Signature[:len(entry.Signature)-2] + "00"
+		if verifier.Verify(nil, forged) {
+			t.Error("Expected a forged signature to fail")
+		}
+	}
+}
+
+// benchmarkVerify verifies every entry of a 1000-entry log b.N times.
+func benchmarkVerify(b *testing.B, verifier *Verifier) {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity("bench-ID")
+	if err != nil {
+		b.Fatalf("Failed to create identity: %v", err)
+	}
+	log, err := NewLog("bench-log", identity, storage.NewMemoryStorage(), ks)
+	if err != nil {
+		b.Fatalf("Failed to create log: %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		if _, err := log.Append("payload " + strconv.Itoa(i)); err != nil {
+			b.Fatalf("Failed to append entry: %v", err)
+		}
+	}
+	entries, err := log.Values()
+	if err != nil {
+		b.Fatalf("Failed to read values: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, entry := range entries {
+			if !verifier.Verify(identity, entry) {
+				b.Fatalf("Entry %s failed verification", entry.Hash)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifier_Uncached(b *testing.B) {
+	benchmarkVerify(b, NewVerifier(0))
+}
+
+func BenchmarkVerifier_Cached(b *testing.B) {
+	benchmarkVerify(b, NewVerifier(1000))
+}
@@ -23,7 +23,10 @@ func main() {
 	}
 
 	// Encode the entry using your Encode function
-	encodedEntry := oplog.Encode(entry)
+	encodedEntry, err := oplog.Encode(entry)
+	if err != nil {
+		log.Fatalf("Error encoding entry: %v", err)
+	}
 	fmt.Println("CID in Go:", encodedEntry.CID)
 	// Output the bytes in hex format for easy comparison
 	fmt.Println("CBOR Encoded Bytes in Go:", hex.EncodeToString(encodedEntry.Bytes))
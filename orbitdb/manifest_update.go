@@ -0,0 +1,227 @@
+package orbitdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/manifest"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// Prefixes of the manifest storage keys that track updates. Manifests
+// themselves are stored under their hash, updates under the update's own
+// hash, and the head names the latest update.
+const (
+	manifestHeadPrefix   = "head_"
+	manifestUpdatePrefix = "update_"
+)
+
+// UpdateManifest replaces the manifest of an open database, for example to
+// rename it or change its access controller, and adopts the update locally.
+// Only the admin named in the current manifest may update it, and the
+// database type cannot change. An empty Admin keeps the current admin.
+//
+// The returned bytes are the signed update; other peers adopt it with
+// ApplyManifestUpdate. The database keeps its address and its log.
+func (o *OrbitDB) UpdateManifest(db *databases.Database, m manifest.Manifest) ([]byte, error) {
+	if db == nil {
+		return nil, errors.New("database is required")
+	}
+
+	addressHash := strings.TrimPrefix(db.Address, AddressPrefix)
+	previous, err := o.currentManifest(addressHash)
+	if err != nil {
+		return nil, err
+	}
+	head, err := o.manifestHead(addressHash)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Admin == "" || previous.Admin != o.Identity.PublicKey {
+		return nil, errors.New("only the database admin can update its manifest")
+	}
+	if m.Admin == "" {
+		m.Admin = previous.Admin
+	}
+
+	update := manifest.Update{Address: db.Address, Previous: head, Manifest: m}
+	payload, err := manifest.SigningPayload(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest update: %w", err)
+	}
+	if update.Signature, err = o.KeyStore.SignMessage(o.Identity.ID, payload); err != nil {
+		return nil, fmt.Errorf("failed to sign manifest update: %w", err)
+	}
+
+	_, data, err := manifest.EncodeUpdate(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest update: %w", err)
+	}
+	if err := o.ApplyManifestUpdate(data, db); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ApplyManifestUpdate verifies an update published with UpdateManifest and
+// adopts it. The update must be signed by the admin of the current manifest
+// and replace it directly, so stale or replayed updates are rejected. When
+// db is the open database being updated, its access controller, name and
+// admin are switched in place; later calls to Open use the new manifest.
+func (o *OrbitDB) ApplyManifestUpdate(data []byte, db *databases.Database) error {
+	update, err := manifest.DecodeUpdate(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode manifest update: %w", err)
+	}
+	if db != nil && db.Address != update.Address {
+		return fmt.Errorf("manifest update for %s does not match database %s", update.Address, db.Address)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	addressHash := strings.TrimPrefix(update.Address, AddressPrefix)
+	previous, err := o.currentManifest(addressHash)
+	if err != nil {
+		return err
+	}
+	head, err := o.manifestHead(addressHash)
+	if err != nil {
+		return err
+	}
+	if update.Previous != head {
+		return fmt.Errorf("manifest update replaces %s, but the current head is %s", update.Previous, head)
+	}
+	if err := verifyManifestUpdate(o.KeyStore, previous, *update); err != nil {
+		return err
+	}
+	if update.Manifest.Type != previous.Type {
+		return fmt.Errorf("manifest update cannot change the database type from %q to %q", previous.Type, update.Manifest.Type)
+	}
+
+	ac, err := o.accessController(update.Address, &update.Manifest)
+	if err != nil {
+		return err
+	}
+
+	if _, err := o.writeManifest(update.Manifest); err != nil {
+		return err
+	}
+	updateHash, _, err := manifest.EncodeUpdate(*update)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest update: %w", err)
+	}
+	if err := o.manifests.Put(manifestUpdatePrefix+updateHash, data); err != nil {
+		return fmt.Errorf("failed to store manifest update: %w", err)
+	}
+	if err := o.manifests.Put(manifestHeadPrefix+addressHash, []byte(updateHash)); err != nil {
+		return fmt.Errorf("failed to store manifest head: %w", err)
+	}
+
+	if db != nil {
+		db.Log.SetAccessController(ac)
+		db.Name = update.Manifest.Name
+		db.AccessController = update.Manifest.AccessController
+		db.Write = update.Manifest.Write
+		db.Admin = update.Manifest.Admin
+	}
+	return nil
+}
+
+// ManifestHistory returns the hashes of every manifest a database has had,
+// newest first and ending with the manifest its address was derived from.
+// A manifest the database returned to appears once for each time it was
+// adopted.
+func (o *OrbitDB) ManifestHistory(address string) ([]string, error) {
+	addressHash := strings.TrimPrefix(address, AddressPrefix)
+	if _, err := o.readManifest(addressHash); err != nil {
+		return nil, err
+	}
+	head, err := o.manifestHead(addressHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []string
+	for head != addressHash {
+		update, err := o.readUpdate(head)
+		if err != nil {
+			return nil, err
+		}
+		hash, _, err := manifest.EncodeManifest(update.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode manifest of update %s: %w", head, err)
+		}
+		history = append(history, hash)
+		head = update.Previous
+	}
+	return append(history, addressHash), nil
+}
+
+// manifestHead returns the hash of the latest update adopted for the
+// database whose original manifest hash is addressHash, or addressHash
+// itself when it has never been updated.
+func (o *OrbitDB) manifestHead(addressHash string) (string, error) {
+	head, err := o.manifests.Get(manifestHeadPrefix + addressHash)
+	if errors.Is(err, storage.ErrNotFound) {
+		return addressHash, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifest head: %w", err)
+	}
+	return string(head), nil
+}
+
+// readUpdate loads an adopted update by its hash.
+func (o *OrbitDB) readUpdate(hash string) (*manifest.Update, error) {
+	data, err := o.manifests.Get(manifestUpdatePrefix + hash)
+	if err != nil {
+		return nil, fmt.Errorf("manifest update %s not found: %w", hash, err)
+	}
+	update, err := manifest.DecodeUpdate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode manifest update %s: %w", hash, err)
+	}
+	return update, nil
+}
+
+// currentManifest resolves the latest manifest of the database whose
+// original manifest hash is addressHash.
+func (o *OrbitDB) currentManifest(addressHash string) (*manifest.Manifest, error) {
+	head, err := o.manifestHead(addressHash)
+	if err != nil {
+		return nil, err
+	}
+	if head == addressHash {
+		return o.readManifest(addressHash)
+	}
+	update, err := o.readUpdate(head)
+	if err != nil {
+		return nil, err
+	}
+	return &update.Manifest, nil
+}
+
+// verifyManifestUpdate checks that an update is signed by the admin of the
+// manifest it replaces.
+func verifyManifestUpdate(ks *keystore.KeyStore, previous *manifest.Manifest, update manifest.Update) error {
+	if previous.Admin == "" {
+		return errors.New("manifest has no admin and cannot be updated")
+	}
+
+	publicKey, err := keystore.ReconstructPublicKeyFromHex(previous.Admin)
+	if err != nil {
+		return fmt.Errorf("invalid manifest admin key: %w", err)
+	}
+	payload, err := manifest.SigningPayload(update)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest update: %w", err)
+	}
+	if ok, err := ks.VerifyMessage(*publicKey, payload, update.Signature); err != nil || !ok {
+		return errors.New("invalid manifest update signature")
+	}
+	return nil
+}
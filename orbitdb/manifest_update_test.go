@@ -0,0 +1,155 @@
+package orbitdb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/accesscontrol"
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/manifest"
+	"orbitdb/go-orbitdb/orbitdb"
+)
+
+func TestUpdateManifestAccessController(t *testing.T) {
+	accesscontrol.Register("update-filter", func(opts accesscontrol.Options) (accesscontrol.AccessController, error) {
+		return payloadFilter{forbidden: "forbidden"}, nil
+	})
+
+	alice := setupOrbitDB(t)
+	bob := setupOrbitDB(t)
+
+	aliceDB, err := alice.Open("managed-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "open"})
+	require.NoError(t, err)
+	defer aliceDB.Close()
+	assert.Equal(t, alice.Identity.PublicKey, aliceDB.Admin)
+
+	link, err := aliceDB.ShareLink()
+	require.NoError(t, err)
+	store, err := bob.OpenShareLink(link)
+	require.NoError(t, err)
+	bobKV := store.(*databases.KeyValue)
+
+	_, err = bobKV.Put("key1", "forbidden")
+	require.NoError(t, err, "the open controller allows every write")
+
+	// Only the admin may publish an update
+	_, err = bob.UpdateManifest(bobKV.Database, manifest.Manifest{Name: "managed-db", Type: "keyvalue", AccessController: "update-filter"})
+	assert.Error(t, err)
+
+	update, err := alice.UpdateManifest(aliceDB, manifest.Manifest{Name: "renamed-db", Type: "keyvalue", AccessController: "update-filter"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed-db", aliceDB.Name)
+	assert.Equal(t, "update-filter", aliceDB.AccessController)
+	assert.Equal(t, alice.Identity.PublicKey, aliceDB.Admin, "an empty admin keeps the current one")
+
+	// Bob verifies and adopts the update, then enforces the new controller
+	require.NoError(t, bob.ApplyManifestUpdate(update, bobKV.Database))
+	assert.Equal(t, "renamed-db", bobKV.Name)
+	assert.Equal(t, "update-filter", bobKV.AccessController)
+
+	_, err = bobKV.Put("key2", "forbidden")
+	assert.Error(t, err)
+	_, err = bobKV.Put("key2", "allowed")
+	require.NoError(t, err)
+
+	aliceKV := &databases.KeyValue{Database: aliceDB}
+	_, err = aliceKV.Put("key3", "forbidden")
+	assert.Error(t, err)
+
+	// Data written before the update is kept
+	value, err := bobKV.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "forbidden", value)
+
+	// Replaying the update is rejected once it has been adopted
+	assert.Error(t, bob.ApplyManifestUpdate(update, bobKV.Database))
+
+	// The old manifest stays linked for history
+	history, err := bob.ManifestHistory(bobKV.Address)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, bobKV.Address, orbitdb.AddressPrefix+history[1])
+
+	// Reopening by address uses the updated manifest
+	require.NoError(t, bobKV.Close())
+	reopened, err := bob.Open(bobKV.Address, nil)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, "renamed-db", reopened.Name)
+	assert.Equal(t, "update-filter", reopened.AccessController)
+}
+
+func TestApplyManifestUpdateRejectsInvalid(t *testing.T) {
+	alice := setupOrbitDB(t)
+	bob := setupOrbitDB(t)
+
+	aliceDB, err := alice.Open("managed-db", &orbitdb.OpenOptions{Type: "keyvalue"})
+	require.NoError(t, err)
+	defer aliceDB.Close()
+
+	link, err := aliceDB.ShareLink()
+	require.NoError(t, err)
+	store, err := bob.OpenShareLink(link)
+	require.NoError(t, err)
+	bobKV := store.(*databases.KeyValue)
+	defer bobKV.Close()
+
+	data, err := alice.UpdateManifest(aliceDB, manifest.Manifest{Name: "renamed-db", Type: "keyvalue", AccessController: "ipfs"})
+	require.NoError(t, err)
+
+	// A tampered update no longer matches the admin signature
+	update, err := manifest.DecodeUpdate(data)
+	require.NoError(t, err)
+	update.Manifest.AccessController = "open"
+	_, tampered, err := manifest.EncodeUpdate(*update)
+	require.NoError(t, err)
+	assert.Error(t, bob.ApplyManifestUpdate(tampered, bobKV.Database))
+	assert.Equal(t, "", bobKV.AccessController)
+
+	// The database type cannot change
+	_, err = alice.UpdateManifest(aliceDB, manifest.Manifest{Name: "renamed-db", Type: "events"})
+	assert.Error(t, err)
+
+	// Updates for another database are rejected
+	otherDB, err := bob.Open("other-db", &orbitdb.OpenOptions{Type: "keyvalue"})
+	require.NoError(t, err)
+	defer otherDB.Close()
+	assert.Error(t, bob.ApplyManifestUpdate(data, otherDB))
+
+	require.NoError(t, bob.ApplyManifestUpdate(data, bobKV.Database))
+	assert.Equal(t, "ipfs", bobKV.AccessController)
+}
+
+func TestManifestHistoryRenameBack(t *testing.T) {
+	alice := setupOrbitDB(t)
+
+	db, err := alice.Open("db-a", &orbitdb.OpenOptions{Type: "keyvalue"})
+	require.NoError(t, err)
+	original := strings.TrimPrefix(db.Address, orbitdb.AddressPrefix)
+
+	var updates [][]byte
+	for _, name := range []string{"db-b", "db-c", "db-b", "db-a"} {
+		update, err := alice.UpdateManifest(db, manifest.Manifest{Name: name, Type: "keyvalue"})
+		require.NoError(t, err, "rename to %s", name)
+		updates = append(updates, update)
+	}
+	assert.Equal(t, "db-a", db.Name)
+
+	history, err := alice.ManifestHistory(db.Address)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	assert.Equal(t, original, history[0], "renaming back adopts the original manifest again")
+	assert.Equal(t, history[1], history[3], "db-b appears once per adoption")
+	assert.Equal(t, original, history[4])
+
+	// An earlier update does not apply again once its manifest recurs
+	assert.Error(t, alice.ApplyManifestUpdate(updates[1], db))
+
+	require.NoError(t, db.Close())
+	reopened, err := alice.Open(db.Address, nil)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, "db-a", reopened.Name)
+}
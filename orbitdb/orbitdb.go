@@ -0,0 +1,228 @@
+package orbitdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"orbitdb/go-orbitdb/accesscontrol"
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/manifest"
+	"orbitdb/go-orbitdb/oplog"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// AddressPrefix is the prefix of every OrbitDB database address.
+const AddressPrefix = "/orbitdb/"
+
+// DefaultType is the database type used when none is requested.
+const DefaultType = "events"
+
+// OrbitDB creates and opens databases on behalf of a single identity.
+type OrbitDB struct {
+	Identity  *identitytypes.Identity
+	KeyStore  *keystore.KeyStore
+	host      host.Host
+	pubsub    *pubsub.PubSub
+	manifests storage.Storage
+	mu        sync.Mutex // Serializes manifest updates
+}
+
+// OpenOptions configures how a database is created or opened.
+type OpenOptions struct {
+	Type             string          // Database type, used when creating a new database
+	AccessController string          // Access controller recorded in a new manifest
+	Write            []string        // Write list recorded in a new manifest; ipfs defaults to the creator
+	EntryStorage     storage.Storage // Storage for log entries, defaults to memory
+}
+
+// NewOrbitDB creates a new OrbitDB instance. Manifests are kept in
+// manifestStorage, which defaults to memory storage.
+func NewOrbitDB(identity *identitytypes.Identity, keyStore *keystore.KeyStore, host host.Host, ps *pubsub.PubSub, manifestStorage storage.Storage) (*OrbitDB, error) {
+	if identity == nil || !identitytypes.IsIdentity(identity) {
+		return nil, errors.New("valid identity is required")
+	}
+	if host == nil || ps == nil {
+		return nil, errors.New("host and pubsub instances are required")
+	}
+
+	if keyStore == nil {
+		keyStore = keystore.NewKeyStore(storage.NewMemoryStorage())
+	}
+	if manifestStorage == nil {
+		manifestStorage = storage.NewMemoryStorage()
+	}
+
+	return &OrbitDB{
+		Identity:  identity,
+		KeyStore:  keyStore,
+		host:      host,
+		pubsub:    ps,
+		manifests: manifestStorage,
+	}, nil
+}
+
+// Open opens the database at the given address, or creates a new database
+// named address when it is not an OrbitDB address.
+func (o *OrbitDB) Open(address string, opts *OpenOptions) (*databases.Database, error) {
+	if opts == nil {
+		opts = &OpenOptions{}
+	}
+
+	var m *manifest.Manifest
+	if strings.HasPrefix(address, AddressPrefix) {
+		var err error
+		if m, err = o.currentManifest(strings.TrimPrefix(address, AddressPrefix)); err != nil {
+			return nil, err
+		}
+	} else {
+		dbType := opts.Type
+		if dbType == "" {
+			dbType = DefaultType
+		}
+		if _, err := databases.GetDatabaseType(dbType); err != nil {
+			return nil, fmt.Errorf("unsupported database type %q", dbType)
+		}
+		if opts.AccessController != "" {
+			if _, err := accesscontrol.Get(opts.AccessController); err != nil {
+				return nil, fmt.Errorf("unsupported access controller %q", opts.AccessController)
+			}
+		}
+		// An ipfs database without a write list is writable by its creator;
+		// recording that in the manifest gives every peer the same list
+		write := opts.Write
+		if opts.AccessController == "ipfs" && len(write) == 0 {
+			write = []string{o.Identity.ID}
+		}
+		m = &manifest.Manifest{Name: address, Type: dbType, AccessController: opts.AccessController, Write: write, Admin: o.Identity.PublicKey}
+
+		hash, err := o.writeManifest(*m)
+		if err != nil {
+			return nil, err
+		}
+		address = AddressPrefix + hash
+	}
+
+	var logOpts []oplog.LogOption
+	ac, err := o.accessController(address, m)
+	if err != nil {
+		return nil, err
+	}
+	if ac != nil {
+		logOpts = append(logOpts, oplog.WithAccessController(ac))
+	}
+
+	db, err := databases.NewDatabase(address, m.Name, o.Identity, opts.EntryStorage, o.KeyStore, o.host, o.pubsub, logOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", address, err)
+	}
+	db.Type = m.Type
+	db.AccessController = m.AccessController
+	db.Write = m.Write
+	db.Admin = m.Admin
+
+	return db, nil
+}
+
+// accessController creates the access controller named in a manifest, or
+// returns nil when the manifest names none.
+func (o *OrbitDB) accessController(address string, m *manifest.Manifest) (oplog.AccessController, error) {
+	if m.AccessController == "" {
+		return nil, nil
+	}
+	factory, err := accesscontrol.Get(m.AccessController)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported access controller %q", m.AccessController)
+	}
+	ac, err := factory(accesscontrol.Options{Address: address, Name: m.Name, Type: m.Type, Write: m.Write, Identity: o.Identity})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access controller %q: %w", m.AccessController, err)
+	}
+	return ac, nil
+}
+
+// OpenShareLink opens a database shared with Store.ShareLink. The manifest is
+// rebuilt from the link and must hash to the shared address.
+func (o *OrbitDB) OpenShareLink(link string) (databases.Store, error) {
+	bundle, err := databases.ParseShareLink(link)
+	if err != nil {
+		return nil, err
+	}
+
+	factory, err := databases.GetDatabaseType(bundle.Type)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported database type %q", bundle.Type)
+	}
+
+	m := manifest.Manifest{Name: bundle.Name, Type: bundle.Type, AccessController: bundle.AccessController, Write: bundle.Write, Admin: bundle.Admin}
+	hash, data, err := manifest.EncodeManifest(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if AddressPrefix+hash != bundle.Address {
+		return nil, fmt.Errorf("share link manifest does not match address %s", bundle.Address)
+	}
+	if err := o.manifests.Put(hash, data); err != nil {
+		return nil, fmt.Errorf("failed to store manifest: %w", err)
+	}
+
+	db, err := o.Open(bundle.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	typed, err := factory(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s database: %w", bundle.Type, err)
+	}
+	store, ok := typed.(databases.Store)
+	if !ok {
+		db.Close()
+		return nil, fmt.Errorf("database type %q does not implement Store", bundle.Type)
+	}
+	return store, nil
+}
+
+// SupportedTypes returns the names of all registered database types,
+// including custom types added with databases.RegisterDatabaseType.
+func (o *OrbitDB) SupportedTypes() []string {
+	return databases.DatabaseTypes()
+}
+
+// Close releases the manifest storage. Databases opened through this
+// instance are closed separately with Database.Close.
+func (o *OrbitDB) Close() error {
+	return o.manifests.Close()
+}
+
+// writeManifest encodes and stores a manifest, returning its hash.
+func (o *OrbitDB) writeManifest(m manifest.Manifest) (string, error) {
+	hash, data, err := manifest.EncodeManifest(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := o.manifests.Put(hash, data); err != nil {
+		return "", fmt.Errorf("failed to store manifest: %w", err)
+	}
+	return hash, nil
+}
+
+// readManifest loads a stored manifest by its hash.
+func (o *OrbitDB) readManifest(hash string) (*manifest.Manifest, error) {
+	data, err := o.manifests.Get(hash)
+	if err != nil {
+		return nil, fmt.Errorf("manifest %s not found: %w", hash, err)
+	}
+
+	m, err := manifest.DecodeManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", hash, err)
+	}
+	return m, nil
+}
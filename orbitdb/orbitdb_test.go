@@ -0,0 +1,296 @@
+package orbitdb_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/libp2p/go-libp2p"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/multiformats/go-multibase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"orbitdb/go-orbitdb/accesscontrol"
+	"orbitdb/go-orbitdb/databases"
+	"orbitdb/go-orbitdb/identities/identitytypes"
+	"orbitdb/go-orbitdb/identities/providers"
+	"orbitdb/go-orbitdb/keystore"
+	"orbitdb/go-orbitdb/oplog"
+	"orbitdb/go-orbitdb/orbitdb"
+	"orbitdb/go-orbitdb/storage"
+)
+
+// setupOrbitDB creates an OrbitDB instance backed by a fresh libp2p host.
+func setupOrbitDB(t *testing.T) *orbitdb.OrbitDB {
+	return setupOrbitDBAs(t, "test-ID")
+}
+
+func setupOrbitDBAs(t *testing.T, id string) *orbitdb.OrbitDB {
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity(id)
+	require.NoError(t, err, "Failed to create identity")
+
+	h, err := libp2p.New()
+	require.NoError(t, err, "Failed to create libp2p host")
+	t.Cleanup(func() { h.Close() })
+
+	ps, err := pubsub.NewGossipSub(context.Background(), h)
+	require.NoError(t, err, "Failed to create GossipSub instance")
+
+	odb, err := orbitdb.NewOrbitDB(identity, ks, h, ps, nil)
+	require.NoError(t, err, "Failed to create OrbitDB")
+	return odb
+}
+
+func TestOpenWithAccessController(t *testing.T) {
+	odb := setupOrbitDB(t)
+
+	db, err := odb.Open("test-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "ipfs"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(db.Address, orbitdb.AddressPrefix))
+	assert.Equal(t, "ipfs", db.AccessController)
+	address := db.Address
+	require.NoError(t, db.Close())
+
+	// Reopening by address loads the access controller from the manifest
+	reopened, err := odb.Open(address, nil)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	assert.Equal(t, address, reopened.Address)
+	assert.Equal(t, "test-db", reopened.Name)
+	assert.Equal(t, "keyvalue", reopened.Type)
+	assert.Equal(t, "ipfs", reopened.AccessController)
+}
+
+// payloadFilter denies entries whose payload contains a forbidden word.
+type payloadFilter struct {
+	forbidden string
+}
+
+func (f payloadFilter) Type() string { return "payload-filter" }
+
+func (f payloadFilter) CanAppend(entry oplog.EncodedEntry, identity *identitytypes.Identity) (bool, error) {
+	return !strings.Contains(entry.Payload, f.forbidden), nil
+}
+
+func TestOpenWithCustomAccessController(t *testing.T) {
+	var received accesscontrol.Options
+	accesscontrol.Register("payload-filter", func(opts accesscontrol.Options) (accesscontrol.AccessController, error) {
+		received = opts
+		return payloadFilter{forbidden: "forbidden"}, nil
+	})
+
+	odb := setupOrbitDB(t)
+	db, err := odb.Open("filtered-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "payload-filter"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Equal(t, db.Address, received.Address)
+	assert.Equal(t, "filtered-db", received.Name)
+	assert.Equal(t, odb.Identity, received.Identity)
+
+	kv := &databases.KeyValue{Database: db}
+	_, err = kv.Put("key1", "allowed")
+	require.NoError(t, err)
+	_, err = kv.Put("key2", "forbidden")
+	assert.Error(t, err)
+
+	all, err := kv.All()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key1": "allowed"}, all)
+
+	// Unregistered controllers are rejected
+	_, err = odb.Open("unknown-ac-db", &orbitdb.OpenOptions{AccessController: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestOpenWithIPFSWriteList(t *testing.T) {
+	odb := setupOrbitDB(t)
+
+	// A write list naming only another writer denies this identity
+	denied, err := odb.Open("gated-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "ipfs", Write: []string{"someone-else"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"someone-else"}, denied.Write)
+	address := denied.Address
+	require.NoError(t, denied.Close())
+
+	// Reopening by address enforces the write list from the manifest
+	reopened, err := odb.Open(address, nil)
+	require.NoError(t, err)
+	_, err = (&databases.KeyValue{Database: reopened}).Put("key1", "value1")
+	assert.Error(t, err)
+	require.NoError(t, reopened.Close())
+
+	// Listing the identity allows its writes
+	allowed, err := odb.Open("gated-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "ipfs", Write: []string{odb.Identity.ID}})
+	require.NoError(t, err)
+	defer allowed.Close()
+	assert.NotEqual(t, address, allowed.Address, "the write list is part of the manifest")
+
+	kv := &databases.KeyValue{Database: allowed}
+	_, err = kv.Put("key1", "value1")
+	require.NoError(t, err)
+	value, err := kv.Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", value)
+}
+
+func TestOpenIPFSWithoutWriteList(t *testing.T) {
+	alice := setupOrbitDBAs(t, "alice")
+	bob := setupOrbitDBAs(t, "bob")
+
+	// The creator is recorded as the only writer in the hashed manifest
+	db, err := alice.Open("owner-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "ipfs"})
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Equal(t, []string{alice.Identity.ID}, db.Write)
+
+	link, err := db.ShareLink()
+	require.NoError(t, err)
+	store, err := bob.OpenShareLink(link)
+	require.NoError(t, err)
+	bobKV := store.(*databases.KeyValue)
+	defer bobKV.Close()
+	assert.Equal(t, db.Address, bobKV.Address)
+	assert.Equal(t, db.Write, bobKV.Write, "both peers derive the same write list")
+
+	// Both peers enforce it: alice may write, bob may not, on either side
+	kv := &databases.KeyValue{Database: db}
+	_, err = kv.Put("key1", "alice")
+	require.NoError(t, err)
+	_, err = bobKV.Put("key1", "bob")
+	assert.Error(t, err)
+
+	entry, err := oplog.NewEntry(bob.KeyStore, bob.Identity, db.Address, "bob", oplog.NewClock(bob.Identity.ID, 10), nil, nil)
+	require.NoError(t, err)
+	err = db.Log.JoinEntry(&entry, make(map[string]bool))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+	all, err := kv.All()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"key1": "alice"}, all)
+
+	factory, err := accesscontrol.Get("ipfs")
+	require.NoError(t, err)
+	for _, write := range [][]string{db.Write, bobKV.Write} {
+		ac, err := factory(accesscontrol.Options{Address: db.Address, Write: write, Identity: bob.Identity})
+		require.NoError(t, err)
+		ok, _ := ac.CanAppend(oplog.EncodedEntry{}, alice.Identity)
+		assert.True(t, ok, "alice is allowed everywhere")
+		ok, _ = ac.CanAppend(oplog.EncodedEntry{}, bob.Identity)
+		assert.False(t, ok, "bob is denied everywhere")
+	}
+}
+
+func TestOpenUnknownAddress(t *testing.T) {
+	odb := setupOrbitDB(t)
+
+	_, err := odb.Open(orbitdb.AddressPrefix+"zdpuAunknown", nil)
+	assert.Error(t, err)
+}
+
+func TestSupportedTypes(t *testing.T) {
+	odb := setupOrbitDB(t)
+
+	assert.Subset(t, odb.SupportedTypes(), []string{"documents", "events", "keyvalue"})
+
+	databases.RegisterDatabaseType("custom", func(db *databases.Database) (interface{}, error) {
+		return db, nil
+	})
+	assert.Contains(t, odb.SupportedTypes(), "custom")
+
+	// Only registered types can be created
+	db, err := odb.Open("custom-db", &orbitdb.OpenOptions{Type: "custom"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = odb.Open("unknown-db", &orbitdb.OpenOptions{Type: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestShareLinkRoundTrip(t *testing.T) {
+	alice := setupOrbitDB(t)
+	bob := setupOrbitDB(t)
+
+	db, err := alice.Open("shared-db", &orbitdb.OpenOptions{Type: "keyvalue", AccessController: "ipfs"})
+	require.NoError(t, err)
+	defer db.Close()
+
+	link, err := db.ShareLink()
+	require.NoError(t, err)
+
+	// Bob has never seen the manifest; the link carries it
+	store, err := bob.OpenShareLink(link)
+	require.NoError(t, err)
+
+	kv, ok := store.(*databases.KeyValue)
+	require.True(t, ok, "expected a keyvalue store")
+	defer kv.Close()
+
+	assert.Equal(t, db.Address, kv.Address)
+	assert.Equal(t, "shared-db", kv.Name)
+	assert.Equal(t, "keyvalue", kv.Type)
+	assert.Equal(t, "ipfs", kv.AccessController)
+
+	// A tampered link whose manifest no longer matches the address is rejected
+	bundle, err := databases.ParseShareLink(link)
+	require.NoError(t, err)
+	bundle.Type = "events"
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	tampered, err := multibase.Encode(multibase.Base58BTC, data)
+	require.NoError(t, err)
+	_, err = bob.OpenShareLink(tampered)
+	assert.Error(t, err)
+
+	_, err = bob.OpenShareLink("not-a-link")
+	assert.Error(t, err)
+}
+
+func TestCloseReleasesLevelStorage(t *testing.T) {
+	manifestPath := t.TempDir()
+	entryPath := t.TempDir()
+
+	ks := keystore.NewKeyStore(storage.NewMemoryStorage())
+	identity, err := providers.NewPublicKeyProvider(ks).CreateIdentity("test-ID")
+	require.NoError(t, err)
+	h, err := libp2p.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Close() })
+	ps, err := pubsub.NewGossipSub(context.Background(), h)
+	require.NoError(t, err)
+
+	open := func() (*orbitdb.OrbitDB, storage.Storage) {
+		manifests, err := storage.NewLevelStorage(manifestPath)
+		require.NoError(t, err)
+		entries, err := storage.NewLevelStorage(entryPath)
+		require.NoError(t, err)
+		odb, err := orbitdb.NewOrbitDB(identity, ks, h, ps, manifests)
+		require.NoError(t, err)
+		return odb, entries
+	}
+
+	odb, entries := open()
+	db, err := odb.Open("persistent-db", &orbitdb.OpenOptions{Type: "keyvalue", EntryStorage: entries})
+	require.NoError(t, err)
+	address := db.Address
+	_, err = (&databases.KeyValue{Database: db}).Put("key1", "value1")
+	require.NoError(t, err)
+
+	require.NoError(t, db.Close())
+	require.NoError(t, odb.Close())
+
+	// Both LevelDB stores are unlocked and keep their data
+	odb, entries = open()
+	defer odb.Close()
+	reopened, err := odb.Open(address, &orbitdb.OpenOptions{EntryStorage: entries})
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	assert.Equal(t, "persistent-db", reopened.Name)
+	value, err := (&databases.KeyValue{Database: reopened}).Get("key1")
+	require.NoError(t, err)
+	assert.Equal(t, "value1", value)
+}
@@ -40,7 +40,21 @@ func (cs *ComposedStorage) Get(key string) ([]byte, error) {
 			return value, nil
 		}
 	}
-	return nil, errors.New("key not found")
+	return nil, ErrNotFound
+}
+
+// Has reports whether any configured storage has the key.
+func (cs *ComposedStorage) Has(key string) (bool, error) {
+	for _, storage := range cs.storages {
+		ok, err := storage.Has(key)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // Delete removes data from all storages.
@@ -66,3 +66,51 @@ func TestComposedStorage(t *testing.T) {
 		t.Fatal("Expected key2 to be cleared, but it was found")
 	}
 }
+
+func TestComposedStorage_Has(t *testing.T) {
+	first := NewMemoryStorage()
+	second := NewMemoryStorage()
+
+	storage, err := NewComposedStorage(first, second)
+	if err != nil {
+		t.Fatalf("Failed to create ComposedStorage: %v", err)
+	}
+
+	// A key held only by a fallback storage is present
+	second.Put("key1", []byte("value1"))
+	if ok, err := storage.Has("key1"); err != nil || !ok {
+		t.Fatalf("Expected key1 to be present, got %v (%v)", ok, err)
+	}
+	if ok, err := storage.Has("key2"); err != nil || ok {
+		t.Fatalf("Expected key2 to be absent, got %v (%v)", ok, err)
+	}
+}
+
+func TestComposedStorage_BackfillsPrimary(t *testing.T) {
+	primary, err := NewLRUStorage(16)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+	secondary := NewMemoryStorage()
+
+	storage, err := NewComposedStorage(primary, secondary)
+	if err != nil {
+		t.Fatalf("Failed to create ComposedStorage: %v", err)
+	}
+
+	// A primary miss falls through to the secondary
+	secondary.Put("key1", []byte("value1"))
+	if ok, _ := primary.Has("key1"); ok {
+		t.Fatal("Expected key1 to be absent from the primary storage")
+	}
+	value, err := storage.Get("key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("Expected value1, got %s (%v)", value, err)
+	}
+
+	// The hit is now cached in the primary
+	value, err = primary.Get("key1")
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("Expected key1 to be cached in the primary storage, got %s (%v)", value, err)
+	}
+}
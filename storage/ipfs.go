@@ -119,7 +119,7 @@ func NewIPFSBlockStorage(ctx context.Context, ds datastore.Batching, dserv forma
 	// Create the pinner
 	pinner, err := dspinner.New(ctx, ds, dserv)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create pinner: %w", err)
+		return nil, fmt.Errorf("%w: failed to create pinner: %w", ErrStorageUnavailable, err)
 	}
 
 	return &IPFSBlockStorage{
@@ -209,6 +209,19 @@ func (s *IPFSBlockStorage) Get(key string) ([]byte, error) {
 	return block.RawData(), nil
 }
 
+// Has reports whether the block is present in the local blockstore.
+func (s *IPFSBlockStorage) Has(key string) (bool, error) {
+	c, err := cid.Decode(key)
+	if err != nil {
+		return false, fmt.Errorf("invalid CID: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	return s.blockstore.Has(ctx, c)
+}
+
 // Delete removes a block from the blockstore.
 func (s *IPFSBlockStorage) Delete(key string) error {
 	c, err := cid.Decode(key)
@@ -154,6 +154,11 @@ func TestIPFSBlockStorage_PutAndGet(t *testing.T) {
 	retrievedData, err := storage.Get(c.String())
 	require.NoError(t, err, "Failed to get data from storage")
 	require.Equal(t, encodedData, retrievedData, "Retrieved data does not match original")
+
+	// Test Has.
+	ok, err := storage.Has(c.String())
+	require.NoError(t, err, "Failed to check block presence")
+	require.True(t, ok, "Expected stored block to be present")
 }
 
 func TestIPFSBlockStorage_Delete(t *testing.T) {
@@ -1,7 +1,8 @@
 package storage
 
 import (
-	"errors"
+	"fmt"
+
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
@@ -16,7 +17,7 @@ type LevelStorage struct {
 func NewLevelStorage(path string) (*LevelStorage, error) {
 	db, err := leveldb.OpenFile(path, &opt.Options{})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: failed to open LevelDB at %s: %w", ErrStorageUnavailable, path, err)
 	}
 	return &LevelStorage{db: db}, nil
 }
@@ -26,15 +27,29 @@ func (s *LevelStorage) Put(key string, value []byte) error {
 	return s.db.Put([]byte(key), value, nil)
 }
 
+// PutMany stores all key-value pairs in a single LevelDB batch.
+func (s *LevelStorage) PutMany(pairs map[string][]byte) error {
+	batch := new(leveldb.Batch)
+	for key, value := range pairs {
+		batch.Put([]byte(key), value)
+	}
+	return s.db.Write(batch, nil)
+}
+
 // Get retrieves a value by its key from LevelDB.
 func (s *LevelStorage) Get(key string) ([]byte, error) {
 	value, err := s.db.Get([]byte(key), nil)
 	if err == leveldb.ErrNotFound {
-		return nil, errors.New("key not found")
+		return nil, ErrNotFound
 	}
 	return value, err
 }
 
+// Has reports whether the key exists in LevelDB.
+func (s *LevelStorage) Has(key string) (bool, error) {
+	return s.db.Has([]byte(key), nil)
+}
+
 // Delete removes a key-value pair from LevelDB.
 func (s *LevelStorage) Delete(key string) error {
 	return s.db.Delete([]byte(key), nil)
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"os"
 	"testing"
 )
@@ -33,8 +34,8 @@ func TestLevelStorage(t *testing.T) {
 
 	// Test non-existent key
 	_, err = storage.Get("nonexistent")
-	if err == nil {
-		t.Fatal("Expected error for non-existent key, got nil")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for non-existent key, got %v", err)
 	}
 
 	// Test Delete
@@ -64,3 +65,67 @@ func TestLevelStorage(t *testing.T) {
 		t.Fatal("Expected error for cleared key, got nil")
 	}
 }
+
+func TestLevelStorage_Has(t *testing.T) {
+	storage, err := NewLevelStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create LevelStorage: %v", err)
+	}
+	defer storage.Close()
+
+	if ok, err := storage.Has("key1"); err != nil || ok {
+		t.Fatalf("Expected key1 to be absent, got %v (%v)", ok, err)
+	}
+	if err := storage.Put("key1", []byte("value1")); err != nil {
+		t.Fatalf("Failed to put data: %v", err)
+	}
+	if ok, err := storage.Has("key1"); err != nil || !ok {
+		t.Fatalf("Expected key1 to be present, got %v (%v)", ok, err)
+	}
+}
+
+func TestLevelStorage_Persistence(t *testing.T) {
+	path := t.TempDir()
+
+	first, err := NewLevelStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create LevelStorage: %v", err)
+	}
+	if err := first.Put("key1", []byte("value1")); err != nil {
+		t.Fatalf("Failed to put data: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Failed to close storage: %v", err)
+	}
+
+	// A second instance on the same path reads the data back
+	second, err := NewLevelStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen LevelStorage: %v", err)
+	}
+	defer second.Close()
+
+	value, err := second.Get("key1")
+	if err != nil {
+		t.Fatalf("Failed to get data after reopening: %v", err)
+	}
+	if string(value) != "value1" {
+		t.Errorf("Expected value1, got %s", value)
+	}
+}
+
+func TestLevelStorage_Locked(t *testing.T) {
+	path := t.TempDir()
+
+	storage, err := NewLevelStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to create LevelStorage: %v", err)
+	}
+	defer storage.Close()
+
+	// The database is locked by the first instance
+	_, err = NewLevelStorage(path)
+	if !errors.Is(err, ErrStorageUnavailable) {
+		t.Fatalf("Expected ErrStorageUnavailable for a locked database, got %v", err)
+	}
+}
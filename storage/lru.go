@@ -1,11 +1,12 @@
 package storage
 
 import (
-	"errors"
 	lru "github.com/hashicorp/golang-lru"
 )
 
-// LRUStorage implements the Storage interface using an LRU cache.
+// LRUStorage implements the Storage interface using an LRU cache. Once it
+// holds size keys, each Put evicts the least recently used key; Get marks a
+// key as recently used. It is safe for concurrent use.
 type LRUStorage struct {
 	cache *lru.Cache
 }
@@ -30,7 +31,12 @@ func (s *LRUStorage) Get(key string) ([]byte, error) {
 	if value, ok := s.cache.Get(key); ok {
 		return value.([]byte), nil
 	}
-	return nil, errors.New("key not found")
+	return nil, ErrNotFound
+}
+
+// Has reports whether the key is present in the LRU cache without updating its recency.
+func (s *LRUStorage) Has(key string) (bool, error) {
+	return s.cache.Contains(key), nil
 }
 
 // Delete removes a key-value pair from the LRU cache.
@@ -45,7 +51,12 @@ func (s *LRUStorage) Iterator() (<-chan [2]string, error) {
 	go func() {
 		defer close(ch)
 		for _, key := range s.cache.Keys() {
-			value, _ := s.cache.Get(key)
+			// Peek keeps iteration from reordering recency; keys evicted
+			// since Keys was called are skipped
+			value, ok := s.cache.Peek(key)
+			if !ok {
+				continue
+			}
 			ch <- [2]string{key.(string), string(value.([]byte))}
 		}
 	}()
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -45,3 +47,81 @@ func TestLRUStorage(t *testing.T) {
 		t.Fatal("Expected key2 to be cleared, but it was found")
 	}
 }
+
+func TestLRUStorage_Has(t *testing.T) {
+	storage, err := NewLRUStorage(1)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	storage.Put("key1", []byte("value1"))
+	if ok, err := storage.Has("key1"); err != nil || !ok {
+		t.Fatalf("Expected key1 to be present, got %v (%v)", ok, err)
+	}
+
+	// Evicted keys are no longer present
+	storage.Put("key2", []byte("value2"))
+	if ok, err := storage.Has("key1"); err != nil || ok {
+		t.Fatalf("Expected key1 to be evicted, got %v (%v)", ok, err)
+	}
+}
+
+func TestLRUStorage_EvictsLeastRecentlyUsed(t *testing.T) {
+	const maxEntries = 3
+	storage, err := NewLRUStorage(maxEntries)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	storage.Put("key1", []byte("value1"))
+	storage.Put("key2", []byte("value2"))
+	storage.Put("key3", []byte("value3"))
+
+	// Reading key1 makes key2 the least recently used
+	if _, err := storage.Get("key1"); err != nil {
+		t.Fatalf("Failed to get key1: %v", err)
+	}
+	storage.Put("key4", []byte("value4"))
+
+	for _, key := range []string{"key1", "key3", "key4"} {
+		if ok, _ := storage.Has(key); !ok {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+	if ok, _ := storage.Has("key2"); ok {
+		t.Error("Expected key2 to be evicted")
+	}
+}
+
+func TestLRUStorage_Concurrent(t *testing.T) {
+	storage, err := NewLRUStorage(8)
+	if err != nil {
+		t.Fatalf("Failed to create LRUStorage: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("key%d-%d", i, j)
+				storage.Put(key, []byte(key))
+				storage.Get(key)
+				iter, _ := storage.Iterator()
+				for range iter {
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	count := 0
+	iter, _ := storage.Iterator()
+	for range iter {
+		count++
+	}
+	if count != 8 {
+		t.Errorf("Expected 8 keys after concurrent writes, got %d", count)
+	}
+}
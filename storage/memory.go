@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"errors"
 	"sync"
 )
 
@@ -26,17 +25,35 @@ func (ms *MemoryStorage) Put(hash string, data []byte) error {
 	return nil
 }
 
+// PutMany stores all pairs in memory under a single lock
+func (ms *MemoryStorage) PutMany(pairs map[string][]byte) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for hash, data := range pairs {
+		ms.memory[hash] = data
+	}
+	return nil
+}
+
 // Get retrieves data from memory
 func (ms *MemoryStorage) Get(hash string) ([]byte, error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	value, exists := ms.memory[hash]
 	if !exists {
-		return nil, errors.New("key not found")
+		return nil, ErrNotFound
 	}
 	return value, nil
 }
 
+// Has reports whether data is stored in memory under the hash
+func (ms *MemoryStorage) Has(hash string) (bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	_, exists := ms.memory[hash]
+	return exists, nil
+}
+
 // Delete removes data from memory
 func (ms *MemoryStorage) Delete(hash string) error {
 	ms.mu.Lock()
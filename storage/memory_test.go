@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 )
 
@@ -24,8 +27,8 @@ func TestMemoryStorage_PutAndGet(t *testing.T) {
 
 	// Test getting non-existent key
 	_, err = memStorage.Get("nonexistent")
-	if err == nil {
-		t.Fatal("Expected error for non-existent key, got nil")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for non-existent key, got %v", err)
 	}
 }
 
@@ -135,3 +138,79 @@ func TestMemoryStorage_Merge(t *testing.T) {
 		t.Errorf("Expected key2=value2, got %s", value)
 	}
 }
+
+func TestMemoryStorage_Has(t *testing.T) {
+	memStorage := NewMemoryStorage()
+
+	if ok, err := memStorage.Has("key1"); err != nil || ok {
+		t.Fatalf("Expected key1 to be absent, got %v (%v)", ok, err)
+	}
+
+	if err := memStorage.Put("key1", []byte("value1")); err != nil {
+		t.Fatalf("Failed to put data: %v", err)
+	}
+	if ok, err := memStorage.Has("key1"); err != nil || !ok {
+		t.Fatalf("Expected key1 to be present, got %v (%v)", ok, err)
+	}
+
+	if err := memStorage.Delete("key1"); err != nil {
+		t.Fatalf("Failed to delete data: %v", err)
+	}
+	if ok, err := memStorage.Has("key1"); err != nil || ok {
+		t.Fatalf("Expected key1 to be absent after delete, got %v (%v)", ok, err)
+	}
+}
+
+func TestMemoryStorage_ConcurrentPutGet(t *testing.T) {
+	memStorage := NewMemoryStorage()
+
+	const workers = 16
+	const keysPerWorker = 100
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < keysPerWorker; i++ {
+				key := fmt.Sprintf("key-%d-%d", w, i)
+				if err := memStorage.Put(key, []byte(key)); err != nil {
+					errs <- err
+					return
+				}
+				value, err := memStorage.Get(key)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if string(value) != key {
+					errs <- fmt.Errorf("expected %s, got %s", key, value)
+					return
+				}
+				if ok, err := memStorage.Has(key); err != nil || !ok {
+					errs <- fmt.Errorf("expected %s to be present", key)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	ch, err := memStorage.Iterator()
+	if err != nil {
+		t.Fatalf("Failed to iterate: %v", err)
+	}
+	count := 0
+	for range ch {
+		count++
+	}
+	if count != workers*keysPerWorker {
+		t.Errorf("Expected %d keys, got %d", workers*keysPerWorker, count)
+	}
+}
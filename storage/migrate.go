@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+)
+
+// MigrateBatchSize is the number of pairs Migrate writes per batch.
+const MigrateBatchSize = 256
+
+// Migrate copies every key-value pair from src to dst. Destinations that
+// implement BatchStorage are written in batches of MigrateBatchSize. Once
+// copied, every key is read back from dst to verify the migration is complete.
+func Migrate(src, dst Storage) error {
+	iter, err := src.Iterator()
+	if err != nil {
+		return fmt.Errorf("failed to iterate source: %w", err)
+	}
+
+	batch := make(map[string][]byte, MigrateBatchSize)
+	var keys []string
+	var writeErr error
+	flush := func() {
+		if writeErr == nil && len(batch) > 0 {
+			writeErr = putBatch(dst, batch)
+			batch = make(map[string][]byte, MigrateBatchSize)
+		}
+	}
+
+	// Drain the iterator even after a failed write so its goroutine can exit
+	for kv := range iter {
+		keys = append(keys, kv[0])
+		batch[kv[0]] = []byte(kv[1])
+		if len(batch) >= MigrateBatchSize {
+			flush()
+		}
+	}
+	flush()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write destination: %w", writeErr)
+	}
+
+	missing := 0
+	for _, key := range keys {
+		if _, err := dst.Get(key); err != nil {
+			missing++
+		}
+	}
+	if missing > 0 {
+		return fmt.Errorf("migration incomplete: %d of %d keys missing from destination", missing, len(keys))
+	}
+	return nil
+}
+
+// putBatch writes a batch with PutMany when available, one pair at a time otherwise.
+func putBatch(dst Storage, batch map[string][]byte) error {
+	if b, ok := dst.(BatchStorage); ok {
+		return b.PutMany(batch)
+	}
+	for key, value := range batch {
+		if err := dst.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
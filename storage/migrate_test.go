@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+// putCounter counts single and batched writes to a MemoryStorage.
+type putCounter struct {
+	*MemoryStorage
+	batches int
+}
+
+func (s *putCounter) PutMany(pairs map[string][]byte) error {
+	s.batches++
+	return s.MemoryStorage.PutMany(pairs)
+}
+
+func TestMigrate(t *testing.T) {
+	src := NewMemoryStorage()
+	const count = 1000
+	for i := 0; i < count; i++ {
+		if err := src.Put(fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i))); err != nil {
+			t.Fatalf("Failed to put data: %v", err)
+		}
+	}
+
+	dst := &putCounter{MemoryStorage: NewMemoryStorage()}
+	if err := Migrate(src, dst); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	expectedBatches := (count + MigrateBatchSize - 1) / MigrateBatchSize
+	if dst.batches != expectedBatches {
+		t.Errorf("Expected %d batches, got %d", expectedBatches, dst.batches)
+	}
+
+	for i := 0; i < count; i++ {
+		value, err := dst.Get(fmt.Sprintf("key%d", i))
+		if err != nil {
+			t.Fatalf("Expected key%d to be migrated: %v", i, err)
+		}
+		if string(value) != fmt.Sprintf("value%d", i) {
+			t.Errorf("Expected value%d, got %s", i, value)
+		}
+	}
+}
+
+func TestMigrateToLevel(t *testing.T) {
+	src := NewMemoryStorage()
+	if err := src.Put("key1", []byte("value1")); err != nil {
+		t.Fatalf("Failed to put data: %v", err)
+	}
+
+	dst, err := NewLevelStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create LevelStorage: %v", err)
+	}
+	defer dst.Close()
+
+	if err := Migrate(src, dst); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	value, err := dst.Get("key1")
+	if err != nil || string(value) != "value1" {
+		t.Errorf("Expected value1, got %s (%v)", value, err)
+	}
+}
@@ -1,13 +1,25 @@
 package storage
 
+import "errors"
+
+// ErrNotFound is returned by Get when no value is stored under the key.
+var ErrNotFound = errors.New("key not found")
+
+// ErrStorageUnavailable wraps failures to open a storage backend, such as
+// missing permissions or a database locked by another process.
+var ErrStorageUnavailable = errors.New("storage unavailable")
+
 // Storage is an interface
 type Storage interface {
 	// Put stores a key-value pair in the storage.
 	Put(key string, value []byte) error
 
-	// Get retrieves a value by its key. Returns an error if the key is not found.
+	// Get retrieves a value by its key. Returns ErrNotFound if the key is not found.
 	Get(key string) ([]byte, error)
 
+	// Has reports whether a value is stored under the key.
+	Has(key string) (bool, error)
+
 	// Delete removes a key-value pair from the storage.
 	Delete(key string) error
 
@@ -23,3 +35,11 @@ type Storage interface {
 	// Close closes the storage and releases resources.
 	Close() error
 }
+
+// BatchStorage is implemented by storages that can write many pairs at once.
+type BatchStorage interface {
+	Storage
+
+	// PutMany stores all key-value pairs in a single batch.
+	PutMany(pairs map[string][]byte) error
+}
@@ -0,0 +1,215 @@
+package syncutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"orbitdb/go-orbitdb/oplog"
+)
+
+// HeadsProtocolPrefix is the prefix of the stream protocol peers use to
+// exchange heads and missing entries; the log ID completes it.
+const HeadsProtocolPrefix = "/orbitdb/heads/1.0.0/"
+
+// maxEntriesPerRequest bounds how many entries one get request asks for.
+const maxEntriesPerRequest = 64
+
+// Message types of the heads protocol.
+const (
+	headsMessageHeads   = "heads"
+	headsMessageGet     = "get"
+	headsMessageEntries = "entries"
+	headsMessageDone    = "done"
+)
+
+// headsMessage is the wire format of the heads protocol. The initiator sends
+// its heads and the responder answers with its own; the initiator then asks
+// for entries it lacks by hash, walking Next links, until it has them all.
+type headsMessage struct {
+	Type    string   `json:"type"`
+	Heads   []string `json:"heads,omitempty"`
+	Hashes  []string `json:"hashes,omitempty"`
+	Entries [][]byte `json:"entries,omitempty"`
+}
+
+// headsProtocol returns the protocol ID for the synced log.
+func (s *Sync) headsProtocol() protocol.ID {
+	return protocol.ID(HeadsProtocolPrefix + s.log.ID)
+}
+
+// ExchangeHeads catches up with a peer: it sends the local heads, receives
+// the peer's heads and fetches every entry it is missing. Entries are
+// verified and joined as they arrive, so an interrupted exchange resumes
+// where it stopped; entries already stored are never requested again. The
+// peer in turn fetches what it is missing from us.
+func (s *Sync) ExchangeHeads(ctx context.Context, peerID peer.ID) error {
+	stream, err := s.host.NewStream(ctx, peerID, s.headsProtocol())
+	if err != nil {
+		return fmt.Errorf("failed to open heads stream to %s: %w", peerID, err)
+	}
+	defer stream.Close()
+
+	enc := json.NewEncoder(stream)
+	dec := json.NewDecoder(stream)
+
+	heads, err := s.headHashes()
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(headsMessage{Type: headsMessageHeads, Heads: heads}); err != nil {
+		return fmt.Errorf("failed to send heads: %w", err)
+	}
+	var reply headsMessage
+	if err := dec.Decode(&reply); err != nil || reply.Type != headsMessageHeads {
+		return fmt.Errorf("failed to receive heads from %s: %v", peerID, err)
+	}
+
+	seen := make(map[string]bool)
+	want := s.missing(reply.Heads, seen)
+	for len(want) > 0 {
+		batch := want
+		if len(batch) > maxEntriesPerRequest {
+			batch = batch[:maxEntriesPerRequest]
+		}
+		want = want[len(batch):]
+
+		if err := enc.Encode(headsMessage{Type: headsMessageGet, Hashes: batch}); err != nil {
+			return fmt.Errorf("failed to request entries: %w", err)
+		}
+		var entries headsMessage
+		if err := dec.Decode(&entries); err != nil || entries.Type != headsMessageEntries {
+			return fmt.Errorf("failed to receive entries from %s: %v", peerID, err)
+		}
+
+		requested := make(map[string]bool, len(batch))
+		for _, hash := range batch {
+			requested[hash] = true
+		}
+		for _, data := range entries.Entries {
+			entry, err := s.joinTransferred(data, requested)
+			if err != nil {
+				log.Printf("Rejected entry from peer %s: %v", peerID, err)
+				continue
+			}
+			want = append(want, s.missing(entry.Next, seen)...)
+		}
+	}
+
+	return enc.Encode(headsMessage{Type: headsMessageDone})
+}
+
+// handleHeadsStream answers a peer's ExchangeHeads, then fetches the peer's
+// heads if they are not all known locally.
+func (s *Sync) handleHeadsStream(stream network.Stream) {
+	defer stream.Close()
+	remote := stream.Conn().RemotePeer()
+
+	enc := json.NewEncoder(stream)
+	dec := json.NewDecoder(stream)
+
+	var remoteHeads []string
+	for {
+		var msg headsMessage
+		if err := dec.Decode(&msg); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("Heads stream from %s failed: %v", remote, err)
+			}
+			break
+		}
+
+		var reply headsMessage
+		switch msg.Type {
+		case headsMessageHeads:
+			remoteHeads = msg.Heads
+			heads, err := s.headHashes()
+			if err != nil {
+				log.Printf("Failed to read heads: %v", err)
+				return
+			}
+			reply = headsMessage{Type: headsMessageHeads, Heads: heads}
+		case headsMessageGet:
+			reply = headsMessage{Type: headsMessageEntries}
+			for _, hash := range msg.Hashes {
+				if data, err := s.log.Entries.Get(hash); err == nil {
+					reply.Entries = append(reply.Entries, data)
+				}
+			}
+		default:
+			// Done, or a message this version does not know
+		}
+		if reply.Type == "" {
+			break
+		}
+		if err := enc.Encode(reply); err != nil {
+			log.Printf("Failed to answer heads stream from %s: %v", remote, err)
+			return
+		}
+	}
+
+	// Fetch what the peer has and we lack
+	if len(s.missing(remoteHeads, make(map[string]bool))) > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.ExchangeHeads(s.ctx, remote); err != nil && s.ctx.Err() == nil {
+				log.Printf("Failed to fetch heads from %s: %v", remote, err)
+			}
+		}()
+	}
+}
+
+// headHashes returns the hashes of the log's current heads.
+func (s *Sync) headHashes() ([]string, error) {
+	heads, err := s.log.Heads()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read heads: %w", err)
+	}
+	hashes := make([]string, len(heads))
+	for i, head := range heads {
+		hashes[i] = head.Hash
+	}
+	return hashes, nil
+}
+
+// missing returns the hashes not yet stored in the log nor already seen,
+// marking them as seen.
+func (s *Sync) missing(hashes []string, seen map[string]bool) []string {
+	var out []string
+	for _, hash := range hashes {
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		if s.log.Has(hash) {
+			continue
+		}
+		out = append(out, hash)
+	}
+	return out
+}
+
+// joinTransferred decodes an entry received over the heads protocol and
+// joins it, which verifies it. Only requested entries are accepted.
+func (s *Sync) joinTransferred(data []byte, requested map[string]bool) (oplog.EncodedEntry, error) {
+	entry, err := oplog.Decode(data)
+	if err != nil {
+		return oplog.EncodedEntry{}, err
+	}
+	if !requested[entry.Hash] {
+		return oplog.EncodedEntry{}, fmt.Errorf("unrequested entry %s", entry.Hash)
+	}
+
+	s.log.Mu.Lock()
+	defer s.log.Mu.Unlock()
+	if err := s.log.JoinEntry(&entry, make(map[string]bool)); err != nil {
+		return oplog.EncodedEntry{}, err
+	}
+	return entry, nil
+}
@@ -19,6 +19,7 @@ type Sync struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	ID        string           // Peer ID
+	host      host.Host        // libp2p host serving the heads protocol
 	pubsub    *pubsub.PubSub   // libp2p PubSub instance
 	log       *oplog.Log       // Actual Log structure
 	SyncedCh  chan SyncedEntry // Channel for synced entries
@@ -28,6 +29,47 @@ type Sync struct {
 	mu        sync.Mutex      // Protects peer access
 	wg        sync.WaitGroup  // WaitGroup for goroutines
 	peerMap   map[string]bool // Tracks connected peers
+	batch     *BatchOptions   // Batching configuration, nil when disabled
+	pending   []oplog.EncodedEntry
+	pendingSz int
+	batchMu   sync.Mutex // Protects pending batch
+	published int        // Number of pubsub messages published
+}
+
+// BatchOptions bounds how many entries are collected into one pubsub message.
+// A batch is flushed as soon as any of the limits is reached.
+type BatchOptions struct {
+	MaxEntries int           // Maximum number of entries per message
+	MaxBytes   int           // Maximum total entry bytes per message
+	Window     time.Duration // Maximum time an entry waits before being sent
+}
+
+// SyncOption configures optional behaviour of a Sync.
+type SyncOption func(*Sync)
+
+// WithBatching collects broadcast entries into batched messages.
+func WithBatching(opts BatchOptions) SyncOption {
+	return func(s *Sync) {
+		if opts.Window <= 0 {
+			opts.Window = 100 * time.Millisecond
+		}
+		s.batch = &opts
+	}
+}
+
+// WithTopic replaces the default orbit-sync/<log id> pubsub topic.
+func WithTopic(name string) SyncOption {
+	return func(s *Sync) {
+		s.TopicName = name
+	}
+}
+
+// syncMessage is the wire format of a pubsub message. Single entries use
+// Entry, batched messages carry Entries.
+type syncMessage struct {
+	PeerID  string
+	Entry   oplog.EncodedEntry
+	Entries []oplog.EncodedEntry `json:",omitempty"`
 }
 
 // SyncedEntry represents an entry received from a peer.
@@ -37,20 +79,25 @@ type SyncedEntry struct {
 }
 
 // NewSync initializes a new Sync instance for the Log.
-func NewSync(host host.Host, pubsub *pubsub.PubSub, log *oplog.Log) *Sync {
+func NewSync(host host.Host, pubsub *pubsub.PubSub, log *oplog.Log, opts ...SyncOption) *Sync {
 	ctx, cancel := context.WithCancel(context.Background())
 	topicName := fmt.Sprintf("orbit-sync/%s", log.ID)
 
-	return &Sync{
+	s := &Sync{
 		ctx:       ctx,
 		cancel:    cancel,
 		ID:        host.ID().String(),
+		host:      host,
 		pubsub:    pubsub,
 		log:       log,
 		SyncedCh:  make(chan SyncedEntry, 10),
 		TopicName: topicName,
 		peerMap:   make(map[string]bool),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Start begins the synchronization process.
@@ -71,17 +118,32 @@ func (s *Sync) Start() error {
 
 	log.Printf("Sync started: subscribed to topic %s", s.TopicName)
 
+	// Serve heads exchanges from peers catching up
+	s.host.SetStreamHandler(s.headsProtocol(), s.handleHeadsStream)
+
 	// Track peer joining
 	go s.trackPeers()
 
 	s.wg.Add(1)
 	go s.processMessages()
 
+	if s.batch != nil {
+		s.wg.Add(1)
+		go s.flushLoop()
+	}
+
 	return nil
 }
 
 // Stop halts the synchronization process.
 func (s *Sync) Stop() {
+	if s.batch != nil {
+		if err := s.Flush(); err != nil {
+			log.Printf("Error flushing pending batch: %v", err)
+		}
+	}
+
+	s.host.RemoveStreamHandler(s.headsProtocol())
 	s.cancel()
 	s.wg.Wait()
 
@@ -93,38 +155,67 @@ func (s *Sync) Stop() {
 	log.Println("Sync stopped.")
 }
 
-// Add creates an entry in the log and broadcasts it to peers.
+// Add appends an entry to the log and broadcasts it to peers.
 func (s *Sync) Add(payload string) error {
-	s.log.Mu.Lock()
-	defer s.log.Mu.Unlock()
+	entry, err := s.log.Append(payload)
+	if err != nil {
+		return fmt.Errorf("failed to append entry to log: %w", err)
+	}
 
-	entry := oplog.EncodedEntry{
-		Entry: oplog.Entry{
-			ID:       s.log.ID,
-			Payload:  payload,
-			Clock:    s.log.Clock,
-			V:        1,
-			Identity: s.log.Identity.ID,
-		},
-		Bytes: []byte(payload),                // Placeholder for actual encoding
-		Hash:  fmt.Sprintf("%x", s.log.Clock), // Example hash generation
+	// Broadcast to peers
+	if err := s.Broadcast(*entry); err != nil {
+		return err
 	}
 
-	// Add to the log
-	if err := s.log.Entries.Put(entry.Hash, entry.Bytes); err != nil {
-		return fmt.Errorf("failed to store entry in log: %w", err)
+	log.Printf("Broadcasted entry: %s from peer: %s\n", payload, s.ID)
+	return nil
+}
+
+// Broadcast sends an entry that is already stored in the log to peers.
+// With batching enabled the entry is queued until the batch is flushed.
+func (s *Sync) Broadcast(entry oplog.EncodedEntry) error {
+	if s.batch == nil {
+		return s.publish(syncMessage{PeerID: s.ID, Entry: entry})
 	}
 
-	// Broadcast to peers
-	entryData := struct {
-		PeerID string
-		Entry  oplog.EncodedEntry
-	}{
-		PeerID: s.ID,
-		Entry:  entry,
+	s.batchMu.Lock()
+	s.pending = append(s.pending, entry)
+	s.pendingSz += len(entry.Bytes)
+	full := (s.batch.MaxEntries > 0 && len(s.pending) >= s.batch.MaxEntries) ||
+		(s.batch.MaxBytes > 0 && s.pendingSz >= s.batch.MaxBytes)
+	s.batchMu.Unlock()
+
+	if full {
+		return s.Flush()
 	}
+	return nil
+}
+
+// Flush publishes all pending batched entries as a single message.
+func (s *Sync) Flush() error {
+	s.batchMu.Lock()
+	entries := s.pending
+	s.pending = nil
+	s.pendingSz = 0
+	s.batchMu.Unlock()
 
-	data, err := json.Marshal(entryData)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return s.publish(syncMessage{PeerID: s.ID, Entries: entries})
+}
+
+// PublishedMessages returns the number of pubsub messages published so far.
+func (s *Sync) PublishedMessages() int {
+	s.batchMu.Lock()
+	defer s.batchMu.Unlock()
+	return s.published
+}
+
+// publish marshals a message and publishes it on the topic.
+func (s *Sync) publish(msg syncMessage) error {
+	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal entry: %w", err)
 	}
@@ -133,10 +224,31 @@ func (s *Sync) Add(payload string) error {
 		return fmt.Errorf("failed to publish entry: %w", err)
 	}
 
-	log.Printf("Broadcasted entry: %s from peer: %s\n", payload, s.ID)
+	s.batchMu.Lock()
+	s.published++
+	s.batchMu.Unlock()
 	return nil
 }
 
+// flushLoop flushes pending entries once the batching window elapses.
+func (s *Sync) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.batch.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				log.Printf("Error flushing batch: %v", err)
+			}
+		}
+	}
+}
+
 // processMessages listens for incoming messages from PubSub and processes single head entries.
 func (s *Sync) processMessages() {
 	defer s.wg.Done()
@@ -159,15 +271,18 @@ func (s *Sync) processMessages() {
 			continue
 		}
 
-		var payload struct {
-			PeerID string
-			Entry  oplog.EncodedEntry
-		}
+		var payload syncMessage
 		if err := json.Unmarshal(msg.Data, &payload); err != nil {
 			log.Printf("Failed to unmarshal message: %v\n", err)
 			continue
 		}
 
+		if len(payload.Entries) > 0 {
+			log.Printf("Processing batch of %d entries from peer: %s\n", len(payload.Entries), payload.PeerID)
+			s.receiveBatch(payload.PeerID, payload.Entries)
+			continue
+		}
+
 		log.Printf("Processing head entry: %s from peer: %s\n", payload.Entry.Payload, payload.PeerID)
 
 		// Process the received head (log entry)
@@ -229,7 +344,7 @@ func (s *Sync) PeerJoin(peerID string) {
 			ID:       fmt.Sprintf("%s-join", peerID),
 			Payload:  fmt.Sprintf("Peer %s has joined the network", peerID),
 			Clock:    s.log.Clock,
-			V:        1,
+			V:        oplog.EntryVersion,
 			Identity: s.log.Identity.ID,
 		},
 		Bytes: []byte(fmt.Sprintf("Peer %s has joined the network", peerID)),
@@ -254,7 +369,7 @@ func (s *Sync) PeerLeave(peerID string) {
 			ID:       fmt.Sprintf("%s-leave", peerID),
 			Payload:  fmt.Sprintf("Peer %s has left the network", peerID),
 			Clock:    s.log.Clock,
-			V:        1,
+			V:        oplog.EntryVersion,
 			Identity: s.log.Identity.ID,
 		},
 		Bytes: []byte(fmt.Sprintf("Peer %s has left the network", peerID)),
@@ -286,43 +401,75 @@ func (s *Sync) sendHead(peerID string) error {
 		return fmt.Errorf("no head entry found")
 	}
 
-	entry := oplog.Encode(head.Entry)
-
-	// Broadcast the head (entry) to the peer
-	entryData := struct {
-		PeerID string
-		Entry  oplog.EncodedEntry
-	}{
-		PeerID: s.ID,
-		Entry:  entry,
-	}
-
-	data, err := json.Marshal(entryData)
+	entry, err := oplog.Encode(head.Entry)
 	if err != nil {
-		return fmt.Errorf("failed to marshal entry: %w", err)
+		return fmt.Errorf("failed to encode head entry: %w", err)
 	}
 
-	if err := s.topic.Publish(s.ctx, data); err != nil {
-		return fmt.Errorf("failed to publish entry: %w", err)
+	// Broadcast the head (entry) to the peer
+	if err := s.publish(syncMessage{PeerID: s.ID, Entry: entry}); err != nil {
+		return err
 	}
 
 	log.Printf("Broadcasted head entry to peer %s: %s", peerID, head.Payload)
 	return nil
 }
 
-// receiveHead processes a received head (log entry) from a peer.
-func (s *Sync) receiveHead(peerID string, entry oplog.EncodedEntry) {
-	// Add the entry to the log
-	s.log.Mu.Lock()
-	if err := s.log.Entries.Put(entry.Hash, entry.Bytes); err != nil {
-		log.Printf("Failed to store entry from peer %s: %v", peerID, err)
-		s.log.Mu.Unlock()
+// receiveHead joins a received head (log entry) from a peer into the log.
+func (s *Sync) receiveHead(peerID string, received oplog.EncodedEntry) {
+	entry, ok := s.decodeReceived(peerID, received)
+	if !ok {
 		return
 	}
+
+	s.log.Mu.Lock()
+	err := s.log.JoinEntry(&entry, make(map[string]bool))
 	s.log.Mu.Unlock()
+	if err != nil {
+		log.Printf("Failed to join entry from peer %s: %v", peerID, err)
+		return
+	}
 
 	log.Printf("Processed head entry from peer %s: %s", peerID, entry.Payload)
 
 	// Notify listeners via the SyncedCh channel
 	s.SyncedCh <- SyncedEntry{PeerID: peerID, Entry: entry}
 }
+
+// receiveBatch joins a batch of entries from a peer into the log.
+func (s *Sync) receiveBatch(peerID string, received []oplog.EncodedEntry) {
+	entries := make([]oplog.EncodedEntry, 0, len(received))
+	for _, r := range received {
+		if entry, ok := s.decodeReceived(peerID, r); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := s.log.JoinAll(entries); err != nil {
+		log.Printf("Failed to join some entries from peer %s: %v", peerID, err)
+	}
+
+	for _, entry := range entries {
+		if s.log.Has(entry.Hash) {
+			s.SyncedCh <- SyncedEntry{PeerID: peerID, Entry: entry}
+		}
+	}
+}
+
+// decodeReceived rebuilds a received entry from its bytes, since the other
+// fields of the message are not covered by the signature. Entries the log
+// already holds are skipped.
+func (s *Sync) decodeReceived(peerID string, received oplog.EncodedEntry) (oplog.EncodedEntry, bool) {
+	entry, err := oplog.Decode(received.Bytes)
+	if err != nil {
+		log.Printf("Failed to decode entry from peer %s: %v", peerID, err)
+		return oplog.EncodedEntry{}, false
+	}
+	if s.log.Has(entry.Hash) {
+		return oplog.EncodedEntry{}, false
+	}
+	return entry, true
+}
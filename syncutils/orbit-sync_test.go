@@ -442,3 +442,212 @@ PeerDiscoveryComplete:
 	syncSelf.Stop()
 	syncPeer.Stop()
 }
+
+func TestSyncBatchedBroadcast(t *testing.T) {
+	ctx := context.Background()
+
+	// Create two libp2p hosts for the peers
+	hostSelf, err := libp2p.New()
+	require.NoError(t, err, "Failed to create libp2p host for self")
+	defer hostSelf.Close()
+
+	hostPeer, err := libp2p.New()
+	require.NoError(t, err, "Failed to create libp2p host for peer")
+	defer hostPeer.Close()
+
+	// Explicitly connect the two hosts
+	hostSelf.Peerstore().AddAddr(hostPeer.ID(), hostPeer.Addrs()[0], peerstore.PermanentAddrTTL)
+	hostPeer.Peerstore().AddAddr(hostSelf.ID(), hostSelf.Addrs()[0], peerstore.PermanentAddrTTL)
+	require.NoError(t, hostSelf.Connect(ctx, peer.AddrInfo{ID: hostPeer.ID()}))
+
+	psSelf, err := pubsub.NewGossipSub(ctx, hostSelf)
+	require.NoError(t, err, "Failed to create GossipSub for self")
+	psPeer, err := pubsub.NewGossipSub(ctx, hostPeer)
+	require.NoError(t, err, "Failed to create GossipSub for peer")
+
+	logSelf := createMockLog(t, "shared-log", "self-identity")
+	logPeer := createMockLog(t, "shared-log", "peer-identity")
+
+	syncSelf := syncutils.NewSync(hostSelf, psSelf, logSelf)
+	syncPeer := syncutils.NewSync(hostPeer, psPeer, logPeer,
+		syncutils.WithBatching(syncutils.BatchOptions{MaxEntries: 5, Window: time.Minute}))
+
+	require.NoError(t, syncSelf.Start(), "Failed to start syncSelf")
+	require.NoError(t, syncPeer.Start(), "Failed to start syncPeer")
+	defer syncSelf.Stop()
+	defer syncPeer.Stop()
+
+	// Wait for the peers to discover each other on the topic
+	require.Eventually(t, func() bool {
+		return len(psSelf.ListPeers("orbit-sync/shared-log")) > 0 &&
+			len(psPeer.ListPeers("orbit-sync/shared-log")) > 0
+	}, 2*time.Second, 100*time.Millisecond, "Timeout waiting for peer discovery")
+
+	// A burst of ten appends is sent as two batches of five
+	const burst = 10
+	before := syncPeer.PublishedMessages()
+	for i := 0; i < burst; i++ {
+		entry, err := logPeer.Append("burst-entry")
+		require.NoError(t, err, "Failed to append entry")
+		require.NoError(t, syncPeer.Broadcast(*entry), "Failed to broadcast entry")
+	}
+	assert.Equal(t, 2, syncPeer.PublishedMessages()-before, "Expected burst to be sent in two messages")
+
+	received := 0
+	timeout := time.After(3 * time.Second)
+	for received < burst {
+		select {
+		case synced := <-syncSelf.SyncedCh:
+			if synced.Entry.Payload == "burst-entry" {
+				received++
+			}
+		case <-timeout:
+			t.Fatalf("Timeout waiting for batched entries, received %d of %d", received, burst)
+		}
+	}
+
+	// The receiver converges on the sender's entries
+	peerValues, err := logPeer.Values()
+	require.NoError(t, err)
+	selfValues, err := logSelf.Values()
+	require.NoError(t, err)
+	require.Len(t, selfValues, burst)
+	for i := range peerValues {
+		assert.Equal(t, peerValues[i].Hash, selfValues[i].Hash, "Entry %d differs between peers", i)
+	}
+}
+
+func TestSyncVerifiesAndDeduplicates(t *testing.T) {
+	ctx := context.Background()
+
+	// Create two libp2p hosts for the peers
+	hostSelf, err := libp2p.New()
+	require.NoError(t, err, "Failed to create libp2p host for self")
+	defer hostSelf.Close()
+
+	hostPeer, err := libp2p.New()
+	require.NoError(t, err, "Failed to create libp2p host for peer")
+	defer hostPeer.Close()
+
+	// Explicitly connect the two hosts
+	hostSelf.Peerstore().AddAddr(hostPeer.ID(), hostPeer.Addrs()[0], peerstore.PermanentAddrTTL)
+	hostPeer.Peerstore().AddAddr(hostSelf.ID(), hostSelf.Addrs()[0], peerstore.PermanentAddrTTL)
+	require.NoError(t, hostSelf.Connect(ctx, peer.AddrInfo{ID: hostPeer.ID()}))
+
+	psSelf, err := pubsub.NewGossipSub(ctx, hostSelf)
+	require.NoError(t, err, "Failed to create GossipSub for self")
+	psPeer, err := pubsub.NewGossipSub(ctx, hostPeer)
+	require.NoError(t, err, "Failed to create GossipSub for peer")
+
+	logSelf := createMockLog(t, "shared-log", "self-identity")
+	logPeer := createMockLog(t, "shared-log", "peer-identity")
+
+	const topic = "custom-topic"
+	syncSelf := syncutils.NewSync(hostSelf, psSelf, logSelf, syncutils.WithTopic(topic))
+	syncPeer := syncutils.NewSync(hostPeer, psPeer, logPeer, syncutils.WithTopic(topic))
+
+	require.NoError(t, syncSelf.Start(), "Failed to start syncSelf")
+	require.NoError(t, syncPeer.Start(), "Failed to start syncPeer")
+	defer syncSelf.Stop()
+	defer syncPeer.Stop()
+
+	// Wait for the peers to discover each other on the topic
+	require.Eventually(t, func() bool {
+		return len(psSelf.ListPeers(topic)) > 0 && len(psPeer.ListPeers(topic)) > 0
+	}, 2*time.Second, 100*time.Millisecond, "Timeout waiting for peer discovery")
+
+	// A forged entry, whose payload no longer matches its signature, is dropped
+	forged, err := logPeer.Append("forged-entry")
+	require.NoError(t, err, "Failed to append entry")
+	forged.Entry.Payload = "tampered"
+	tampered, err := oplog.Encode(forged.Entry)
+	require.NoError(t, err, "Failed to encode entry")
+	require.NoError(t, syncPeer.Broadcast(tampered), "Failed to broadcast entry")
+
+	// An appended entry is verified and joined; a second broadcast is ignored
+	require.NoError(t, syncPeer.Add("peer-entry"), "Failed to add entry")
+	require.NoError(t, syncPeer.Broadcast(*logPeer.Head), "Failed to rebroadcast entry")
+
+	select {
+	case synced := <-syncSelf.SyncedCh:
+		assert.Equal(t, "peer-entry", synced.Entry.Payload, "Expected only the valid entry to be synced")
+		joined, err := logSelf.Get(synced.Entry.Hash)
+		require.NoError(t, err, "Expected synced entry to be joined into the log")
+		assert.Equal(t, logPeer.Head.Hash, joined.Hash)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for synced entry")
+	}
+
+	select {
+	case synced := <-syncSelf.SyncedCh:
+		t.Fatalf("Expected duplicate and forged entries to be ignored, got %q", synced.Entry.Payload)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+// TestSyncExchangeHeads tests that two peers with divergent logs converge
+// after one heads exchange, including when one side already holds part of
+// the other's history.
+func TestSyncExchangeHeads(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hostSelf, err := libp2p.New()
+	require.NoError(t, err)
+	defer hostSelf.Close()
+	hostPeer, err := libp2p.New()
+	require.NoError(t, err)
+	defer hostPeer.Close()
+
+	hostSelf.Peerstore().AddAddr(hostPeer.ID(), hostPeer.Addrs()[0], peerstore.PermanentAddrTTL)
+	hostPeer.Peerstore().AddAddr(hostSelf.ID(), hostSelf.Addrs()[0], peerstore.PermanentAddrTTL)
+	require.NoError(t, hostSelf.Connect(ctx, peer.AddrInfo{ID: hostPeer.ID()}))
+
+	psSelf, err := pubsub.NewGossipSub(ctx, hostSelf)
+	require.NoError(t, err)
+	psPeer, err := pubsub.NewGossipSub(ctx, hostPeer)
+	require.NoError(t, err)
+
+	logSelf := createMockLog(t, "heads-log", "self")
+	logPeer := createMockLog(t, "heads-log", "peer")
+
+	for i := 0; i < 3; i++ {
+		_, err := logSelf.Append("self entry")
+		require.NoError(t, err)
+	}
+	var peerEntries []*oplog.EncodedEntry
+	for i := 0; i < 100; i++ {
+		entry, err := logPeer.Append("peer entry")
+		require.NoError(t, err)
+		peerEntries = append(peerEntries, entry)
+	}
+
+	// Simulate an earlier exchange that was interrupted part way
+	for _, entry := range peerEntries[:10] {
+		require.NoError(t, logSelf.JoinEntry(entry, make(map[string]bool)))
+	}
+
+	syncSelf := syncutils.NewSync(hostSelf, psSelf, logSelf)
+	syncPeer := syncutils.NewSync(hostPeer, psPeer, logPeer)
+	require.NoError(t, syncSelf.Start())
+	defer syncSelf.Stop()
+	require.NoError(t, syncPeer.Start())
+	defer syncPeer.Stop()
+
+	require.NoError(t, syncSelf.ExchangeHeads(ctx, hostPeer.ID()))
+
+	hashes := func(l *oplog.Log) []string {
+		values, err := l.Values()
+		require.NoError(t, err)
+		out := make([]string, len(values))
+		for i, entry := range values {
+			out[i] = entry.Hash
+		}
+		return out
+	}
+
+	assert.Len(t, hashes(logSelf), 103, "Self should have fetched all peer entries")
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual(hashes(logSelf), hashes(logPeer))
+	}, 5*time.Second, 50*time.Millisecond, "Logs should converge to identical values")
+}